@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/hero"
 	"github.com/hidevopsio/iris/middleware/basicauth"
 )
 
@@ -39,6 +40,37 @@ func newApp() *iris.Application {
 		needAuth.Get("/settings", h)
 	}
 
+	// The passwords can be hashed and the users can be loaded from an htpasswd file
+	// or from any other `UserStore`, i.e a database.
+	users, err := basicauth.LoadHtpasswd("./users.htpasswd")
+	if err != nil {
+		panic(err)
+	}
+
+	hashedAuthentication := basicauth.New(basicauth.Config{
+		Users: users,
+		Store: basicauth.UserStoreFunc(func(username string) (*basicauth.User, bool) {
+			if username != "argon2" {
+				return nil, false
+			}
+
+			return &basicauth.User{
+				Username: username,
+				Password: "$argon2id$v=19$m=8192,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$WUZpJYiHcTrtMWWvj65AVBTMoEtcmf6unmNxuIEDRRs",
+				// ask this user for credentials again after one hour.
+				Expires: time.Hour,
+			}, true
+		}),
+	})
+
+	// the authenticated user is a dependency of the hero handlers and the mvc controllers.
+	h := hero.New().Register(basicauth.GetUser)
+
+	// http://localhost:8080/me
+	app.Get("/me", hashedAuthentication, h.Handler(func(user *basicauth.User) string {
+		return "Hello " + user.Username
+	}))
+
 	return app
 }
 
@@ -1,9 +1,13 @@
 package main
 
 import (
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hidevopsio/iris"
 	"github.com/hidevopsio/iris/httptest"
+	"github.com/hidevopsio/iris/middleware/basicauth"
 )
 
 func TestBasicAuth(t *testing.T) {
@@ -26,4 +30,103 @@ func TestBasicAuth(t *testing.T) {
 	// with invalid basic auth
 	e.GET("/admin/settings").WithBasicAuth("invalidusername", "invalidpassword").
 		Expect().Status(httptest.StatusUnauthorized)
+
+	// with hashed passwords.
+	e.GET("/me").WithBasicAuth("bcrypt", "bcrypt-password").Expect().
+		Status(httptest.StatusOK).Body().Equal("Hello bcrypt")
+	e.GET("/me").WithBasicAuth("sha", "sha-password").Expect().
+		Status(httptest.StatusOK).Body().Equal("Hello sha")
+	e.GET("/me").WithBasicAuth("sha512", "sha512-password").Expect().
+		Status(httptest.StatusOK).Body().Equal("Hello sha512")
+	e.GET("/me").WithBasicAuth("argon2", "argon2-password").Expect().
+		Status(httptest.StatusOK).Body().Equal("Hello argon2")
+	e.GET("/me").WithBasicAuth("bcrypt", "sha-password").Expect().Status(httptest.StatusUnauthorized)
+	e.GET("/me").WithBasicAuth("argon2", "invalid").Expect().Status(httptest.StatusUnauthorized)
+	e.GET("/me").WithBasicAuth("myusername", "mypassword").Expect().Status(httptest.StatusUnauthorized)
+}
+
+func TestBasicAuthExpires(t *testing.T) {
+	app := iris.New()
+	app.Get("/", basicauth.New(basicauth.Config{
+		Users:   map[string]string{"myusername": "mypassword"},
+		Expires: time.Hour,
+		Store: basicauth.UserStoreFunc(func(username string) (*basicauth.User, bool) {
+			return &basicauth.User{Username: username, Password: "{SHA}/bUn3yvdsx9+nWPLnLW2aYqwpXw=", Expires: time.Millisecond}, username == "short"
+		}),
+	}), func(ctx iris.Context) {
+		ctx.WriteString(basicauth.GetUser(ctx).Username)
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/").WithBasicAuth("myusername", "mypassword").Expect().Status(httptest.StatusOK).Body().Equal("myusername")
+	e.GET("/").WithBasicAuth("short", "shortpassword").Expect().Status(httptest.StatusOK).Body().Equal("short")
+
+	time.Sleep(5 * time.Millisecond)
+
+	// the login of the "short" has expired, it's asked for credentials again and then it logins again.
+	e.GET("/").WithBasicAuth("short", "shortpassword").Expect().Status(httptest.StatusUnauthorized).
+		Header("WWW-Authenticate").Equal(`Basic realm="Authorization Required"`)
+	e.GET("/").WithBasicAuth("short", "shortpassword").Expect().Status(httptest.StatusOK)
+	e.GET("/").WithBasicAuth("myusername", "mypassword").Expect().Status(httptest.StatusOK)
+}
+
+func TestVerifyPassword(t *testing.T) {
+	tests := []struct {
+		hashed, password string
+		ok               bool
+	}{
+		{"$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5", "Hello world!", true},
+		{"$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1", "Hello world!", true},
+		{"$5$rounds=1000$abc$UxKib5kobt2BZp/yfOEWbjik.BPMiS9MzbXyO6zXMC0", "x", true},
+		{"$5$rounds=1000$abc$UxKib5kobt2BZp/yfOEWbjik.BPMiS9MzbXyO6zXMC0", "y", false},
+		{"$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1", "Hello world", false},
+		// the plain text passwords are accepted only by the Config.Users.
+		{"plain", "plain", false},
+		// the unsupported hashes never match, not even themselves.
+		{"$apr1$r31.....$HqJZimcKQFAMYayBlzkrA/", "$apr1$r31.....$HqJZimcKQFAMYayBlzkrA/", false},
+		{"{SSHA}c29tZXRoaW5n", "{SSHA}c29tZXRoaW5n", false},
+		{"$argon2id$v=19$m=8192,t=1,p=1$invalid", "argon2-password", false},
+	}
+
+	for i, tt := range tests {
+		if got := basicauth.VerifyPassword(tt.hashed, tt.password); got != tt.ok {
+			t.Fatalf("[%d] expected %q to verify %t but got %t", i, tt.password, tt.ok, got)
+		}
+	}
+}
+
+func TestBasicAuthUnsupportedHashes(t *testing.T) {
+	const apr1 = "$apr1$r31.....$HqJZimcKQFAMYayBlzkrA/"
+
+	// htpasswd's default MD5 and the plain text passwords are rejected.
+	if _, err := basicauth.ParseHtpasswd(strings.NewReader("bcrypt:$2a$04$3BBPzNXT6jHwHQP7A3lYPuWT5R4oOP085BCuYEZNEPJtOpFx2AmpO\nmd5:" + apr1)); err == nil {
+		t.Fatalf("expected an error for the $apr1$ hash")
+	}
+	if _, err := basicauth.ParseHtpasswd(strings.NewReader("plain:mypassword")); err == nil {
+		t.Fatalf("expected an error for the plain text password")
+	}
+
+	app := iris.New()
+	app.Get("/", basicauth.New(basicauth.Config{
+		Users: map[string]string{"plain": "mypassword", "dollar": "$ecret", "brace": "{secret}"},
+		Store: basicauth.UserStoreFunc(func(username string) (*basicauth.User, bool) {
+			if username == "nil" {
+				return nil, true
+			}
+			return &basicauth.User{Username: username, Password: "storepassword"}, username == "store"
+		}),
+	}), func(ctx iris.Context) {
+		ctx.WriteString(basicauth.GetUser(ctx).Username)
+	})
+
+	e := httptest.New(t, app)
+	// the plain text passwords are accepted only by the Config.Users,
+	// the ones which look like an unsupported hash too.
+	e.GET("/").WithBasicAuth("plain", "mypassword").Expect().Status(httptest.StatusOK).Body().Equal("plain")
+	e.GET("/").WithBasicAuth("dollar", "$ecret").Expect().Status(httptest.StatusOK).Body().Equal("dollar")
+	e.GET("/").WithBasicAuth("brace", "{secret}").Expect().Status(httptest.StatusOK).Body().Equal("brace")
+	e.GET("/").WithBasicAuth("dollar", "ecret").Expect().Status(httptest.StatusUnauthorized)
+	e.GET("/").WithBasicAuth("store", "storepassword").Expect().Status(httptest.StatusUnauthorized)
+	// a nil user of the store is not found.
+	e.GET("/").WithBasicAuth("nil", "").Expect().Status(httptest.StatusUnauthorized)
 }
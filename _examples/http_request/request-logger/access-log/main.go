@@ -0,0 +1,72 @@
+package main
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/middleware/logger"
+)
+
+func newApp(ac *logger.AccessLog) *iris.Application {
+	app := iris.New()
+	app.Use(ac.ServeHTTP)
+	// log the not found and the other http errors too.
+	app.OnAnyErrorCode(ac.ServeHTTP, func(ctx iris.Context) {
+		ctx.Writef("error %d", ctx.GetStatusCode())
+	})
+
+	app.Get("/", func(ctx iris.Context) {
+		ctx.WriteString("index")
+	})
+
+	app.Get("/users/{id:int}", func(ctx iris.Context) {
+		ctx.Writef("user %s", ctx.Params().Get("id"))
+	}).Name = "user"
+
+	app.Post("/users", func(ctx iris.Context) {
+		body, _ := io.ReadAll(ctx.Request().Body)
+		ctx.StatusCode(iris.StatusCreated)
+		ctx.Write(body)
+	})
+
+	return app
+}
+
+func main() {
+	// the access.log is rotated every day or when it's larger than 10MB,
+	// the rotated files are compressed and only the last 7 of them are kept.
+	output, err := logger.NewRotateFile("./logs/access.log", logger.RotateConfig{
+		MaxSize:    10 << 20,
+		Interval:   24 * time.Hour,
+		MaxBackups: 7,
+		Compress:   true,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	ac := logger.NewAccessLog(logger.AccessLogConfig{
+		// the records are written to the console and to the file.
+		Output: io.MultiWriter(os.Stdout, output),
+		// or logger.FormatLogfmt, logger.FormatCommon, logger.FormatCombined.
+		Formatter: logger.FormatJSON,
+		// write the records asynchronously.
+		BufferSize: 1024,
+		Headers:    []string{"Accept-Language"},
+		// log only the half of the requests, the server errors are always logged.
+		// SampleRate: 0.5,
+	})
+
+	app := newApp(ac)
+
+	// http://localhost:8080
+	// http://localhost:8080/users/42
+	// http://localhost:8080/notfound
+	app.Run(iris.Addr(":8080"))
+
+	// write the buffered records on shutdown.
+	ac.Close()
+	output.Close()
+}
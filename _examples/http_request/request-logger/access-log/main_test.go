@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hidevopsio/iris/httptest"
+	"github.com/hidevopsio/iris/middleware/logger"
+)
+
+func TestAccessLogJSON(t *testing.T) {
+	var buf bytes.Buffer
+	ac := logger.NewAccessLog(logger.AccessLogConfig{
+		Output:  &buf,
+		Headers: []string{"Accept-Language"},
+	})
+
+	e := httptest.New(t, newApp(ac))
+	e.GET("/users/42").WithHeader("X-Request-Id", "req-1").WithHeader("Accept-Language", "el-GR").
+		Expect().Status(httptest.StatusOK).Body().Equal("user 42")
+	e.POST("/users").WithText("iris").Expect().Status(httptest.StatusCreated).
+		Header("X-Request-Id").NotEmpty()
+	e.GET("/notfound").Expect().Status(httptest.StatusNotFound)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if expected, got := 3, len(lines); expected != got {
+		t.Fatalf("expected %d records but got %d: %s", expected, got, buf.String())
+	}
+
+	var records []map[string]interface{}
+	for _, line := range lines {
+		rec := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("%s: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+
+	expected := map[string]interface{}{
+		"request_id": "req-1",
+		"route":      "user",
+		"method":     "GET",
+		"path":       "/users/42",
+		"status":     float64(200),
+		"params":     map[string]interface{}{"id": "42"},
+		"bytes_in":   float64(0),
+		"bytes_out":  float64(7),
+		"headers":    map[string]interface{}{"Accept-Language": "el-GR"},
+	}
+	for key, value := range expected {
+		if got := records[0][key]; !equal(got, value) {
+			t.Fatalf("expected %s to be %v but got %v", key, value, got)
+		}
+	}
+
+	if got := records[1]["bytes_in"]; got != float64(4) {
+		t.Fatalf("expected bytes_in to be 4 but got %v", got)
+	}
+
+	if got := records[2]["status"]; got != float64(404) {
+		t.Fatalf("expected status to be 404 but got %v", got)
+	}
+}
+
+func TestAccessLogCombined(t *testing.T) {
+	var buf bytes.Buffer
+	ac := logger.NewAccessLog(logger.AccessLogConfig{
+		Output:     &buf,
+		Formatter:  logger.FormatCombined,
+		BufferSize: 16,
+	})
+
+	e := httptest.New(t, newApp(ac))
+	e.GET("/").WithQuery("a", "b").WithBasicAuth("frank", "pass").WithHeader("Referer", "http://example.com").
+		WithHeader("User-Agent", "Mozilla/4.08").Expect().Status(httptest.StatusOK)
+
+	// flush.
+	ac.Close()
+
+	line := buf.String()
+	if !strings.Contains(line, ` - frank [`) ||
+		!strings.HasSuffix(line, `] "GET /?a=b HTTP/1.1" 200 5 "http://example.com" "Mozilla/4.08"`+"\n") {
+		t.Fatalf("unexpected combined log format: %s", line)
+	}
+}
+
+func TestAccessLogSampling(t *testing.T) {
+	var buf bytes.Buffer
+	ac := logger.NewAccessLog(logger.AccessLogConfig{
+		Output:     &buf,
+		Formatter:  logger.FormatLogfmt,
+		SampleRate: 0.000001,
+	})
+
+	e := httptest.New(t, newApp(ac))
+	for i := 0; i < 10; i++ {
+		e.GET("/").Expect().Status(httptest.StatusOK)
+	}
+
+	if buf.Len() != 0 {
+		t.Fatalf("expected the requests to be sampled out but got: %s", buf.String())
+	}
+}
+
+func TestRotateFile(t *testing.T) {
+	dir, err := os.MkdirTemp("", "access-log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "access.log")
+	f, err := logger.NewRotateFile(filename, logger.RotateConfig{
+		MaxSize:    10,
+		MaxBackups: 2,
+		Compress:   true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"1234567\n", "2234567\n", "3234567\n", "4234567\n"} {
+		if _, err = f.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "4234567\n", string(contents); expected != got {
+		t.Fatalf("expected the current file to contain %q but got %q", expected, got)
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(dir, "access-*.log.gz"))
+	if expected, got := 2, len(backups); expected != got {
+		t.Fatalf("expected %d compressed backups but got %d: %v", expected, got, backups)
+	}
+}
+
+func equal(a, b interface{}) bool {
+	x, _ := json.Marshal(a)
+	y, _ := json.Marshal(b)
+	return bytes.Equal(x, y)
+}
@@ -15,9 +15,7 @@ func newApp() *iris.Application {
 			"en-US": "./locales/locale_en-US.ini",
 			"el-GR": "./locales/locale_el-GR.ini",
 			"zh-CN": "./locales/locale_zh-CN.ini"}})
-	app.Use(globalLocale)
-
-	app.Get("/", func(ctx iris.Context) {
+	app.Get("/", globalLocale, func(ctx iris.Context) {
 
 		// it tries to find the language by:
 		// ctx.Values().GetString("language")
@@ -25,6 +23,8 @@ func newApp() *iris.Application {
 		// it tries to find from the URLParameter setted on the configuration
 		// if not found then
 		// it tries to find the language by the "language" cookie
+		// if not found then
+		// it tries to find the language by the Accept-Language header, by its q-values
 		// if didn't found then it it set to the Default setted on the configuration
 
 		// hi is the key, 'iris' is the %s on the .ini file
@@ -59,6 +59,53 @@ func newApp() *iris.Application {
 			"key2", fromSecondFileValue)
 	})
 
+	// The locale files can be yaml, json or toml too, their keys can be nested
+	// and the messages can have plural forms and named arguments.
+	// The "pt-BR" messages which are missing are translated from the "pt" and then from the default language.
+	catalogLocale := i18n.New(i18n.Config{
+		Default:      "en-US",
+		URLParameter: "lang",
+		Languages: map[string]string{
+			"en-US": "./locales/catalog/en-US.yml",
+			"pt":    "./locales/catalog/pt.json",
+			"pt-BR": "./locales/catalog/pt-BR.toml"}})
+
+	app.Get("/catalog", catalogLocale, func(ctx iris.Context) {
+		count, _ := ctx.URLParamInt("count")
+
+		ctx.Writef("%s\n%s\n%s\n%s",
+			ctx.Translate("nav.home"),
+			ctx.Translate("nav.about"),
+			ctx.Translate("cats", iris.Map{"count": count}),
+			ctx.Translate("inbox", iris.Map{"name": "iris", "count": count}))
+	})
+
+	// the translate function of the request's language is the "tr" view data.
+	app.RegisterView(iris.HTML("./templates", ".html"))
+	app.Get("/template", catalogLocale, func(ctx iris.Context) {
+		// {{call .tr "nav.home"}}
+		ctx.View("index.html")
+	})
+
+	// The language can be the prefix of the path too, i.e "/pt-BR/about",
+	// the wrapper removes it before the routing so the "/about" route serves all the languages.
+	// The languages of the Accept-Language header are negotiated by their q-values.
+	pathConfig := i18n.Config{
+		Default: "en-US",
+		Languages: map[string]string{
+			"en-US": "./locales/catalog/en-US.yml",
+			"pt":    "./locales/catalog/pt.json",
+			"pt-BR": "./locales/catalog/pt-BR.toml"},
+		Extractors:    []i18n.LanguageExtractor{i18n.FromPath(), i18n.FromHeader()},
+		DisableCookie: true,
+	}
+	app.WrapRouter(i18n.NewWrapper(pathConfig))
+
+	app.Get("/about", i18n.New(pathConfig), func(ctx iris.Context) {
+		language := ctx.Values().GetString(ctx.Application().ConfigurationReadOnly().GetTranslateLanguageContextKey())
+		ctx.Writef("%s: %s", language, ctx.Translate("nav.about"))
+	})
+
 	return app
 }
 
@@ -73,6 +120,12 @@ func main() {
 	// or http://localhost:8080/multi (default is en-US)
 	// or http://localhost:8080/multi?lang=en-US
 	//
+	// go to http://localhost:8080/catalog?lang=pt-BR&count=2
+	// or http://localhost:8080/template?lang=pt-BR
+	//
+	// go to http://localhost:8080/pt-BR/about
+	// or http://localhost:8080/about (the language of the Accept-Language header)
+	//
 	// or use cookies to set the language.
 	app.Run(iris.Addr(":8080"))
 }
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/hidevopsio/iris"
 	"github.com/hidevopsio/iris/httptest"
+	"github.com/hidevopsio/iris/middleware/i18n"
 )
 
 func TestI18n(t *testing.T) {
@@ -46,4 +48,87 @@ func TestI18n(t *testing.T) {
 	e.GET("/multi").WithQueryString("lang=en-US").Expect().Status(httptest.StatusOK).
 		Body().Equal(enusMulti)
 
+	e.GET("/catalog").WithQueryString("lang=en-US&count=0").Expect().Status(httptest.StatusOK).
+		Body().Equal("Home\nAbout\nno cats\niris has no messages")
+	e.GET("/catalog").WithQueryString("lang=en-US&count=1").Expect().Status(httptest.StatusOK).
+		Body().Equal("Home\nAbout\n1 cat\niris has 1 message")
+	e.GET("/catalog").WithQueryString("lang=en-US&count=5").Expect().Status(httptest.StatusOK).
+		Body().Equal("Home\nAbout\n5 cats\niris has 5 messages")
+	// pt-BR -> pt -> en-US, the zero is singular in portuguese.
+	e.GET("/catalog").WithQueryString("lang=pt-BR&count=0").Expect().Status(httptest.StatusOK).
+		Body().Equal("Início\nSobre\n0 gato\niris has no messages")
+	e.GET("/catalog").WithQueryString("lang=pt-BR&count=2").Expect().Status(httptest.StatusOK).
+		Body().Equal("Início\nSobre\n2 gatos\niris has 2 messages")
+	// pt -> en-US.
+	e.GET("/catalog").WithQueryString("lang=pt&count=2").Expect().Status(httptest.StatusOK).
+		Body().Equal("Início\nAbout\n2 gatos\niris has 2 messages")
+
+	e.GET("/template").WithQueryString("lang=pt-BR").Expect().Status(httptest.StatusOK).
+		Body().Equal("<h1>Início</h1>")
+
+	// the language prefix of the path.
+	e.GET("/pt-BR/about").Expect().Status(httptest.StatusOK).Body().Equal("pt-BR: Sobre")
+	e.GET("/pt/about").Expect().Status(httptest.StatusOK).Body().Equal("pt: About")
+	e.GET("/en-US/about").Expect().Status(httptest.StatusOK).Body().Equal("en-US: About")
+	// the Accept-Language by its q-values, without a cookie.
+	e.GET("/about").WithHeader("Accept-Language", "fr;q=0.9, en-US;q=0.5, pt-BR;q=0.8").Expect().
+		Status(httptest.StatusOK).Body().Equal("pt-BR: Sobre")
+	e.GET("/about").WithHeader("Accept-Language", "pt-BR;q=0, *;q=0.1").Expect().
+		Status(httptest.StatusOK).Body().Equal("en-US: About")
+	e.GET("/about").WithHeader("Accept-Language", "pt-BR").Expect().
+		Status(httptest.StatusOK).Cookies().Empty()
+	// the header's languages are negotiated by their q-values too on the cookie-based middlewares.
+	e.GET("/").WithHeader("Accept-Language", "zh-CN;q=0.5, el-GR;q=0.8").WithCookie("language", "").Expect().
+		Status(httptest.StatusOK).Body().Equal(elgr)
+}
+
+func TestI18nPathRedirect(t *testing.T) {
+	app := iris.New()
+
+	c := i18n.Config{
+		Default: "en-US",
+		Languages: map[string]string{
+			"en-US": "./locales/locale_en-US.ini",
+			"el-GR": "./locales/locale_el-GR.ini"},
+		PathRedirect: true,
+	}
+	app.WrapRouter(i18n.NewWrapper(c))
+	app.Get("/hi", i18n.New(c), func(ctx iris.Context) {
+		ctx.WriteString(ctx.Translate("hi", "iris"))
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/el-GR/hi").Expect().Status(httptest.StatusOK).Body().Equal("γεια, iris")
+	// the client follows the redirect to the "/el-GR/hi?a=b".
+	e.GET("/hi").WithHeader("Accept-Language", "el;q=0.8, de").WithQuery("a", "b").Expect().
+		Status(httptest.StatusOK).Body().Equal("γεια, iris")
+	e.GET("/hi").Expect().Status(httptest.StatusOK).Body().Equal("hello, iris")
+	// the requests which can't be redirected are served as they're.
+	e.POST("/hi").Expect().Status(httptest.StatusNotFound)
+}
+
+func TestI18nCatalogINI(t *testing.T) {
+	catalog := i18n.NewCatalog()
+	// the inline comments, the raw and the multi-line values of the ini files.
+	data := "hi = hello, %s ; an inline comment\n" +
+		"quoted = `hello; iris`\n" +
+		"[nav]\n" +
+		"home = Home # an inline comment too\n" +
+		"about = \"\"\"About\nus\"\"\"\n"
+
+	err := catalog.LoadData("en-US", "ini", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, expected := range map[string]string{
+		"hi":        "hello, iris",
+		"quoted":    "hello; iris",
+		"nav.home":  "Home",
+		"nav.about": "About\nus",
+	} {
+		if got := catalog.Translate("en-US", key, "iris"); got != expected {
+			t.Fatalf("expected %s to be translated to %q but got %q", key, expected, got)
+		}
+	}
 }
@@ -96,6 +96,7 @@ const ifNoneMatchHeaderKey = "If-None-Match"
 // assets.StaticWeb("/", "./assets") or StaticEmbedded("/", "./assets") or StaticEmbeddedGzip("/", "./assets").
 //
 // Similar to `Cache304` but it doesn't depends on any "modified date", it uses just the ETag and If-None-Match headers.
+// See `Conditional` for dynamic responses, it generates the ETag from the response body.
 //
 // Read more at: https://developer.mozilla.org/en-US/docs/Web/HTTP/Caching and
 // https://en.wikipedia.org/wiki/HTTP_ETag
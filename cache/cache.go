@@ -43,7 +43,8 @@ import (
 // Use it for server-side caching, see the `iris#Cache304` for an alternative approach that
 // may fit your needs most.
 //
-// You can add validators with this function.
+// You can add validators with this function
+// and change the storage of the cached responses with its `Store`, see the "cache/store" package.
 func Cache(expiration time.Duration) *client.Handler {
 	return client.NewHandler(expiration)
 }
@@ -65,3 +66,28 @@ func Handler(expiration time.Duration) context.Handler {
 	h := Cache(expiration).ServeHTTP
 	return h
 }
+
+var (
+	// Tag attaches tags to the response, i.e "user:42",
+	// the `PurgeTag` removes the cached responses of a tag.
+	//
+	// Usage:
+	// app.Get("/users/{id:int}", cache.Handler(time.Minute), func(ctx iris.Context) {
+	// 	cache.Tag(ctx, "user:"+ctx.Params().Get("id"))
+	// 	[...]
+	// })
+	Tag = client.Tag
+	// PurgeURL removes the cached responses of a request path and query, i.e "/users/42?lang=en",
+	// or of a full url, i.e "http://admin.mydomain.com/users/42".
+	PurgeURL = client.PurgeURL
+	// PurgePrefix removes the cached responses of the request paths which start with a prefix, i.e "/users/".
+	PurgePrefix = client.PurgePrefix
+	// PurgeRoute removes the cached responses of a route based on its name.
+	PurgeRoute = client.PurgeRoute
+	// PurgeTag removes the cached responses which have at least one of the tags, see `Tag`.
+	PurgeTag = client.PurgeTag
+	// PurgeHandler returns a handler which removes the cached responses
+	// based on the "url", "prefix", "route" and "tag" url query or form values,
+	// i.e "/cache/purge?tag=user:42". Register it behind an authentication middleware.
+	PurgeHandler = client.PurgeHandler
+)
@@ -1,7 +1,14 @@
 package cache_test
 
 import (
+	"compress/gzip"
+	"io/ioutil"
 	"net/http"
+	stdhttptest "net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -9,6 +16,8 @@ import (
 	"github.com/hidevopsio/iris/cache"
 	"github.com/hidevopsio/iris/cache/client"
 	"github.com/hidevopsio/iris/cache/client/rule"
+	"github.com/hidevopsio/iris/cache/store"
+	"github.com/hidevopsio/iris/cache/store/boltdb"
 
 	"github.com/hidevopsio/iris"
 	"github.com/hidevopsio/iris/context"
@@ -209,3 +218,313 @@ func TestCacheValidator(t *testing.T) {
 		t.Fatalf(t.Name()+": %v", errTestFailed.Format(3, counter))
 	}
 }
+
+func TestCacheStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iris-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := boltdb.New(filepath.Join(dir, "cache.db"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	var n uint32
+
+	memory := store.NewMemory(store.MemoryConfig{MaxEntries: 1})
+	app.Get("/memory/{id}", cache.Cache(cacheDuration).Store(memory).ServeHTTP, func(ctx context.Context) {
+		atomic.AddUint32(&n, 1)
+		ctx.Header("X-Id", ctx.Params().Get("id"))
+		ctx.WriteString(ctx.Params().Get("id"))
+	})
+
+	// a nil store, i.e of a failed connection, is ignored, the default memory store is used instead.
+	var failed *boltdb.Store
+	app.Get("/nil", cache.Cache(cacheDuration).Store(failed).ServeHTTP, func(ctx context.Context) {
+		atomic.AddUint32(&n, 1)
+		ctx.WriteString("nil")
+	})
+
+	app.Get("/disk", cache.Cache(cacheDuration).Store(db).ServeHTTP, func(ctx context.Context) {
+		atomic.AddUint32(&n, 1)
+		ctx.Header("X-Id", "disk")
+		ctx.WriteString("disk")
+	})
+
+	e := httptest.New(t, app)
+
+	for _, path := range []string{"/memory/1", "/memory/1", "/memory/2", "/memory/1"} {
+		id := path[len("/memory/"):]
+		e.GET(path).Expect().Status(http.StatusOK).Header("X-Id").Equal(id)
+	}
+	// 1, cached, 2 evicts 1, 1 again.
+	if counter := atomic.LoadUint32(&n); counter != 3 {
+		t.Fatal(errTestFailed.Format(3, counter))
+	}
+
+	if expected, got := 1, memory.Len(); expected != got {
+		t.Fatalf("expected the memory store to keep %d entry but it has %d", expected, got)
+	}
+
+	e.GET("/disk").Expect().Status(http.StatusOK).Body().Equal("disk")
+	e.GET("/disk").Expect().Status(http.StatusOK).Header("X-Id").Equal("disk")
+	if counter := atomic.LoadUint32(&n); counter != 4 {
+		t.Fatal(errTestFailed.Format(4, counter))
+	}
+
+	e.GET("/nil").Expect().Status(http.StatusOK).Body().Equal("nil")
+	e.GET("/nil").Expect().Status(http.StatusOK).Body().Equal("nil")
+	if counter := atomic.LoadUint32(&n); counter != 5 {
+		t.Fatal(errTestFailed.Format(5, counter))
+	}
+}
+
+func TestCacheVary(t *testing.T) {
+	app := iris.New()
+	var n uint32
+
+	app.Get("/", cache.Handler(cacheDuration), func(ctx context.Context) {
+		atomic.AddUint32(&n, 1)
+		ctx.Header("Vary", "Accept-Encoding")
+
+		if !ctx.ClientSupportsGzip() {
+			ctx.WriteString(expectedBodyStr)
+			return
+		}
+
+		ctx.Header("Content-Encoding", "gzip")
+		w := gzip.NewWriter(ctx)
+		w.Write([]byte(expectedBodyStr))
+		w.Close()
+	})
+
+	e := httptest.New(t, app)
+
+	for i := 0; i < 2; i++ {
+		e.GET("/").Expect().Status(http.StatusOK).
+			ContentEncoding().Body().Equal(expectedBodyStr)
+
+		body := e.GET("/").WithHeader("Accept-Encoding", "gzip").Expect().Status(http.StatusOK).
+			ContentEncoding("gzip").Body().Raw()
+
+		r, err := gzip.NewReader(strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b, _ := ioutil.ReadAll(r); string(b) != expectedBodyStr {
+			t.Fatalf("expected gzip body: %s but got: %s", expectedBodyStr, b)
+		}
+	}
+
+	// once for identity and once for gzip.
+	if counter := atomic.LoadUint32(&n); counter != 2 {
+		t.Fatal(errTestFailed.Format(2, counter))
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	app := iris.New()
+	var n uint32
+
+	h := func(ctx context.Context) {
+		atomic.AddUint32(&n, 1)
+		ctx.Writef("%s %s", ctx.GetHeader("Accept-Language"), ctx.GetCookie("theme"))
+	}
+
+	byLanguageAndTheme := cache.Cache(cacheDuration).VaryByHeader("accept-language").VaryByCookie("theme")
+	app.Get("/vary", byLanguageAndTheme.ServeHTTP, h)
+
+	// all paths share the same response.
+	byNothing := cache.Cache(cacheDuration).Key(func(ctx context.Context) string { return "static" })
+	app.Get("/key/{p:path}", byNothing.ServeHTTP, h)
+
+	e := httptest.New(t, app)
+
+	e.GET("/vary").WithHeader("Accept-Language", "en").Expect().Body().Equal("en ")
+	e.GET("/vary").WithHeader("Accept-Language", "el").Expect().Body().Equal("el ")
+	e.GET("/vary").WithHeader("Accept-Language", "el").WithCookie("theme", "dark").Expect().Body().Equal("el dark")
+	e.GET("/vary").WithHeader("Accept-Language", "en").Expect().Body().Equal("en ")
+	if counter := atomic.LoadUint32(&n); counter != 3 {
+		t.Fatal(errTestFailed.Format(3, counter))
+	}
+
+	e.GET("/key/a").WithHeader("Accept-Language", "en").Expect().Body().Equal("en ")
+	e.GET("/key/b").WithHeader("Accept-Language", "el").Expect().Body().Equal("en ")
+	if counter := atomic.LoadUint32(&n); counter != 4 {
+		t.Fatal(errTestFailed.Format(4, counter))
+	}
+}
+
+func TestCacheSingleFlight(t *testing.T) {
+	app := iris.New()
+	var n uint32
+
+	app.Get("/", cache.Handler(cacheDuration), func(ctx context.Context) {
+		atomic.AddUint32(&n, 1)
+		time.Sleep(100 * time.Millisecond)
+		ctx.WriteString(expectedBodyStr)
+	})
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	bodies := make(chan string, 10)
+	for i := 0; i < cap(bodies); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := stdhttptest.NewRecorder()
+			app.ServeHTTP(rec, stdhttptest.NewRequest(http.MethodGet, "/", nil))
+			bodies <- rec.Body.String()
+		}()
+	}
+
+	wg.Wait()
+	close(bodies)
+
+	for body := range bodies {
+		if body != expectedBodyStr {
+			t.Fatalf("expected body: %s but got: %s", expectedBodyStr, body)
+		}
+	}
+
+	if counter := atomic.LoadUint32(&n); counter != 1 {
+		t.Fatal(errTestFailed.Format(1, counter))
+	}
+}
+
+func TestCacheStale(t *testing.T) {
+	app := iris.New()
+	var n, n2, n3, served uint32
+
+	// the background requests execute the route's handlers only, not the global middleware.
+	app.Use(func(ctx context.Context) {
+		atomic.AddUint32(&served, 1)
+		ctx.Next()
+	})
+
+	app.Get("/revalidate", cache.Handler(cacheDuration), func(ctx context.Context) {
+		counter := atomic.AddUint32(&n, 1)
+		ctx.Header("Cache-Control", "max-age=2, stale-while-revalidate=10")
+		ctx.Writef("%d", counter)
+	})
+
+	app.Get("/vary", cache.Handler(cacheDuration), func(ctx context.Context) {
+		counter := atomic.AddUint32(&n3, 1)
+		ctx.Header("Cache-Control", "max-age=2, stale-while-revalidate=10")
+		ctx.Header("Vary", "Accept")
+		ctx.Writef("body %d", counter)
+	})
+
+	app.Get("/error", cache.Cache(cacheDuration).StaleIfError(10*time.Second).ServeHTTP, func(ctx context.Context) {
+		if atomic.AddUint32(&n2, 1) > 1 {
+			ctx.StatusCode(iris.StatusInternalServerError)
+			ctx.WriteString("error")
+			return
+		}
+
+		ctx.WriteString(expectedBodyStr)
+	})
+
+	e := httptest.New(t, app)
+
+	e.GET("/revalidate").Expect().Status(http.StatusOK).Body().Equal("1")
+	e.GET("/vary").WithHeader("Accept", "text/plain").Expect().Status(http.StatusOK).Body().Equal("body 1")
+	e.GET("/vary").WithHeader("Accept", "text/plain").Expect().Status(http.StatusOK).Body().Equal("body 1")
+	e.GET("/error").Expect().Status(http.StatusOK).Body().Equal(expectedBodyStr)
+	time.Sleep(cacheDuration + cacheDuration/10)
+
+	// the stale response is served and it's revalidated in the background.
+	e.GET("/revalidate").Expect().Status(http.StatusOK).Body().Equal("1")
+	// the stale response of the request headers' values, not the entry which keeps the "Vary" names.
+	e.GET("/vary").WithHeader("Accept", "text/plain").Expect().Status(http.StatusOK).Body().Equal("body 1")
+	deadline := time.Now().Add(2 * time.Second)
+	for atomic.LoadUint32(&n) != 2 || atomic.LoadUint32(&n3) != 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the stale responses to be revalidated in the background")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	time.Sleep(50 * time.Millisecond) // let the background requests store the responses.
+	e.GET("/revalidate").Expect().Status(http.StatusOK).Body().Equal("2")
+	e.GET("/vary").WithHeader("Accept", "text/plain").Expect().Status(http.StatusOK).Body().Equal("body 2")
+
+	// the handler fails, the stale response is served instead.
+	e.GET("/error").Expect().Status(http.StatusOK).Body().Equal(expectedBodyStr)
+	if counter := atomic.LoadUint32(&n2); counter != 2 {
+		t.Fatal(errTestFailed.Format(2, counter))
+	}
+
+	if counter := atomic.LoadUint32(&served); counter != 9 {
+		t.Fatal(errTestFailed.Format(9, counter))
+	}
+}
+
+func TestCachePurge(t *testing.T) {
+	app := iris.New()
+	var n uint32
+
+	h := func(ctx context.Context) {
+		atomic.AddUint32(&n, 1)
+		if id := ctx.Params().Get("id"); id != "" {
+			cache.Tag(ctx, "user:"+id)
+		}
+		ctx.WriteString(ctx.Path())
+	}
+
+	app.Get("/users/{id:int}", cache.Handler(cacheDuration), h).Name = "user"
+	app.Get("/users/{id:int}/friends", cache.Handler(cacheDuration), h)
+	app.Get("/posts", cache.Handler(cacheDuration), h)
+	app.Post("/cache/purge", cache.PurgeHandler())
+
+	e := httptest.New(t, app)
+
+	// fill the cache.
+	all := []string{"/users/1", "/users/2", "/users/1/friends", "/posts"}
+	fill := func() {
+		for _, path := range all {
+			e.GET(path).Expect().Status(http.StatusOK).Body().Equal(path)
+		}
+	}
+
+	expectExecuted := func(expected uint32, paths ...string) {
+		t.Helper()
+		atomic.StoreUint32(&n, 0)
+		for _, path := range paths {
+			e.GET(path).Expect().Status(http.StatusOK).Body().Equal(path)
+		}
+		if counter := atomic.LoadUint32(&n); counter != expected {
+			t.Fatal(errTestFailed.Format(expected, counter))
+		}
+	}
+
+	fill()
+	expectExecuted(0, all...)
+
+	if purged := cache.PurgeURL("/users/2"); purged != 1 {
+		t.Fatalf("expected 1 purged entry but got: %d", purged)
+	}
+	expectExecuted(1, all...)
+
+	cache.PurgeTag("user:1")
+	expectExecuted(2, all...)
+
+	cache.PurgeRoute("user")
+	expectExecuted(2, all...)
+
+	cache.PurgePrefix("/users/")
+	expectExecuted(3, all...)
+
+	e.POST("/cache/purge").WithQuery("prefix", "/users/").WithQuery("url", "/posts").Expect().Status(http.StatusOK).
+		JSON().Object().ValueEqual("purged", 4)
+	expectExecuted(4, all...)
+
+	e.POST("/cache/purge").WithQuery("route", "notfound").Expect().Status(http.StatusNotFound)
+	e.POST("/cache/purge").Expect().Status(http.StatusBadRequest)
+}
@@ -0,0 +1,130 @@
+package client
+
+import (
+	stdContext "context"
+	"net/http"
+	"sync"
+
+	"github.com/hidevopsio/iris/context"
+)
+
+// flights coalesces the concurrent requests of the same key,
+// only one of them executes the handler and stores its response
+// while the rest wait for it.
+type flights struct {
+	mu sync.Mutex
+	// the keys of the requests which are executing the handler.
+	running map[string]chan struct{}
+	// the keys which are revalidated in the background.
+	revalidating map[string]struct{}
+}
+
+// begin returns true if the caller is the one which should execute the handler of the "key",
+// otherwise it returns a channel which is closed when the executing request finishes.
+func (f *flights) begin(key string) (<-chan struct{}, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if done, ok := f.running[key]; ok {
+		return done, false
+	}
+
+	if f.running == nil {
+		f.running = make(map[string]chan struct{})
+	}
+
+	f.running[key] = make(chan struct{})
+	return nil, true
+}
+
+// end releases the requests which wait for the "key".
+func (f *flights) end(key string) {
+	f.mu.Lock()
+	if done, ok := f.running[key]; ok {
+		delete(f.running, key)
+		close(done)
+	}
+	f.mu.Unlock()
+}
+
+// beginRevalidate returns false if the "key" is already revalidated in the background.
+func (f *flights) beginRevalidate(key string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok := f.revalidating[key]; ok {
+		return false
+	}
+
+	if f.revalidating == nil {
+		f.revalidating = make(map[string]struct{})
+	}
+
+	f.revalidating[key] = struct{}{}
+	return true
+}
+
+func (f *flights) endRevalidate(key string) {
+	f.mu.Lock()
+	delete(f.revalidating, key)
+	f.mu.Unlock()
+}
+
+type revalidateContextKey struct{}
+
+// isRevalidation reports whether the request is the background request of the `revalidate`.
+func isRevalidation(ctx context.Context) bool {
+	return ctx.Request().Context().Value(revalidateContextKey{}) != nil
+}
+
+// revalidate executes the route's handler in the background with a copy of the request,
+// the cache handler stores its new response.
+// The handlers before the cache handler, i.e the global middleware, are not executed again.
+func (h *Handler) revalidate(ctx context.Context, key string) {
+	if !h.flights.beginRevalidate(key) {
+		return
+	}
+
+	// the request's context is canceled when the request is served, it can't be used here,
+	// neither its body which is read by the request's handlers.
+	r := ctx.Request().Clone(stdContext.WithValue(stdContext.Background(), revalidateContextKey{}, true))
+	r.Body, r.GetBody, r.ContentLength = http.NoBody, nil, 0
+
+	pool := ctx.Application().GetContextPool()
+	rctx := pool.Acquire(&discardResponseWriter{header: make(http.Header)}, r)
+	if route := ctx.GetCurrentRoute(); route != nil {
+		rctx.SetCurrentRouteName(route.Name())
+	}
+	rctx.Params().Store = append(rctx.Params().Store[0:0], ctx.Params().Store...)
+	*rctx.Values() = append((*rctx.Values())[0:0], *ctx.Values()...)
+	rctx.SetHandlers(ctx.Handlers())
+	// the current handler is the route's handler, the cache handler is the previous one,
+	// see the `Skip` of the `ServeHTTP`.
+	rctx.HandlerIndex(ctx.HandlerIndex(-1) - 1)
+
+	go func() {
+		defer func() {
+			rctx.ResponseWriter().EndResponse()
+			pool.ReleaseLight(rctx)
+			h.flights.endRevalidate(key)
+		}()
+
+		h.ServeHTTP(rctx)
+	}()
+}
+
+// discardResponseWriter is the response writer of the background requests,
+// their response is recorded and stored by the cache handler.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header         { return w.header }
+func (w *discardResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardResponseWriter) WriteHeader(int)             {}
+
+// isServerError reports whether the response of the handler is a failure,
+// the stale response is served instead when the `entry#CanServeStaleIfError`.
+func isServerError(statusCode int) bool {
+	return statusCode >= http.StatusInternalServerError
+}
@@ -1,16 +1,18 @@
 package client
 
 import (
-	"sync"
+	"net/http"
+	"reflect"
 	"time"
 
 	"github.com/hidevopsio/iris/cache/client/rule"
 	"github.com/hidevopsio/iris/cache/entry"
+	"github.com/hidevopsio/iris/cache/store"
 	"github.com/hidevopsio/iris/context"
 )
 
 // Handler the local cache service handler contains
-// the original response, the memory cache entry and
+// the original response, the cache store and
 // the validator for each of the incoming requests and post responses
 type Handler struct {
 	// Rule optional validators for pre cache and post cache actions
@@ -19,19 +21,68 @@ type Handler struct {
 	rule rule.Rule
 	// when expires.
 	expiration time.Duration
-	// entries the memory cache stored responses.
-	entries map[string]*entry.Entry
-	mu      sync.RWMutex
+	// store the cached responses, defaults to an unbounded `store.Memory`.
+	store store.Store
+	// keyFunc returns the key of a request, defaults to the `DefaultKeyFunc`.
+	keyFunc KeyFunc
+	// keyParts are appended to the key, see `VaryByHeader`, `VaryByCookie` and `VaryBySession`.
+	keyParts []keyPart
+	// the stale durations when the response's "Cache-Control" has no
+	// "stale-while-revalidate" and "stale-if-error" extensions.
+	staleWhileRevalidate time.Duration
+	staleIfError         time.Duration
+	flights              flights
 }
 
 // NewHandler returns a new cached handler for the "bodyHandler"
 // which expires every "expiration".
+//
+// The responses are stored in memory, the expired ones are removed every `store.DefaultSweepInterval`,
+// use the `Store` to limit the memory store or to store the responses on disk or on redis.
 func NewHandler(expiration time.Duration) *Handler {
-	return &Handler{
+	h := &Handler{
 		rule:       DefaultRuleSet,
 		expiration: expiration,
-		entries:    make(map[string]*entry.Entry, 0),
+		store:      store.NewMemory(store.MemoryConfig{}),
+		keyFunc:    DefaultKeyFunc,
+	}
+
+	registerStore(h.store)
+	return h
+}
+
+// Store sets the storage of the cached responses,
+// i.e `store.NewMemory(store.MemoryConfig{MaxEntries: 1000, MaxBytes: 32 << 20})`
+// or one of the "badger", "boltdb" and "redis" stores of the "cache/store" sub-packages.
+//
+// The previous store is closed.
+// The `Purge` functions remove the responses of all the stores in use.
+//
+// returns itself.
+func (h *Handler) Store(s store.Store) *Handler {
+	if isNilStore(s) {
+		return h
 	}
+
+	if h.store != nil {
+		unregisterStore(h.store)
+		h.store.Close()
+	}
+
+	h.store = s
+	registerStore(s)
+	return h
+}
+
+// isNilStore reports whether the "s" is nil or a nil pointer,
+// i.e the store of a failed `New` call of the "cache/store" sub-packages.
+func isNilStore(s store.Store) bool {
+	if s == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(s)
+	return v.Kind() == reflect.Ptr && v.IsNil()
 }
 
 // Rule sets the ruleset for this handler.
@@ -71,6 +122,48 @@ func parseLifeChanger(ctx context.Context) entry.LifeChanger {
 	}
 }
 
+// StaleWhileRevalidate sets the duration after the expiration which the response
+// is served stale while a background request revalidates it,
+// a "stale-while-revalidate" directive of the response's "Cache-Control" header has priority.
+//
+// returns itself.
+func (h *Handler) StaleWhileRevalidate(d time.Duration) *Handler {
+	h.staleWhileRevalidate = d
+	return h
+}
+
+// StaleIfError sets the duration after the expiration which the response
+// is served stale when its revalidation fails with a server error, status code >= 500,
+// a "stale-if-error" directive of the response's "Cache-Control" header has priority.
+//
+// returns itself.
+func (h *Handler) StaleIfError(d time.Duration) *Handler {
+	h.staleIfError = d
+	return h
+}
+
+// parseStale returns the stale durations of the response.
+func (h *Handler) parseStale(header http.Header) (whileRevalidate, ifError time.Duration) {
+	whileRevalidate, ifError = h.staleWhileRevalidate, h.staleIfError
+
+	cacheControl := header.Get("Cache-Control")
+	if seconds := entry.ParseDirective(cacheControl, "stale-while-revalidate"); seconds >= 0 {
+		whileRevalidate = time.Duration(seconds) * time.Second
+	}
+
+	if seconds := entry.ParseDirective(cacheControl, "stale-if-error"); seconds >= 0 {
+		ifError = time.Duration(seconds) * time.Second
+	}
+
+	return
+}
+
+func (h *Handler) set(ctx context.Context, key string, e *entry.Entry) {
+	if err := h.store.Set(key, e); err != nil {
+		ctx.Application().Logger().Warnf("cache: store the response of %s: %v", key, err)
+	}
+}
+
 // /TODO: debug this and re-run the parallel tests on larger scale,
 // because I think we have a bug here when `core/router#StaticWeb` is used after this middleware.
 func (h *Handler) ServeHTTP(ctx context.Context) {
@@ -90,85 +183,149 @@ func (h *Handler) ServeHTTP(ctx context.Context) {
 		return
 	}
 
-	scheme := "http"
-	if ctx.Request().TLS != nil {
-		scheme = "https"
-	}
+	key := h.getKey(ctx)
 
-	var (
-		response *entry.Response
-		valid    = false
-		// unique per subdomains and paths with different url query.
-		key = scheme + ctx.Host() + ctx.Request().URL.RequestURI()
-	)
+	var e *entry.Entry
+	if !isRevalidation(ctx) {
+		e = h.get(key, ctx.Request().Header)
+		if e != nil {
+			if response, valid := e.Response(); valid {
+				h.write(ctx, e, response)
+				return
+			}
 
-	h.mu.RLock()
-	e, found := h.entries[key]
-	h.mu.RUnlock()
-
-	if found {
-		// the entry is here, .Response will give us
-		// if it's expired or no
-		response, valid = e.Response()
-	} else {
-		// create the entry now.
-		// fmt.Printf("create new cache entry\n")
-		// fmt.Printf("key: %s\n", key)
-
-		e = entry.NewEntry(h.expiration)
-		h.mu.Lock()
-		h.entries[key] = e
-		h.mu.Unlock()
-	}
-
-	if !valid {
-		// if it's expired, then execute the original handler
-		// with our custom response recorder response writer
-		// because the net/http doesn't give us
-		// a built'n way to get the status code & body
-		recorder := ctx.Recorder()
-		bodyHandler(ctx)
+			if e.CanServeStale() {
+				// serve the expired response and fetch the new one in the background.
+				h.revalidate(ctx, key)
+				h.write(ctx, e, e.StaleResponse())
+				return
+			}
+		}
 
-		// now that we have recordered the response,
-		// we are ready to check if that specific response is valid to be stored.
+		// only one of the concurrent requests executes the handler,
+		// the rest wait for its response.
+		if done, leader := h.flights.begin(key); !leader {
+			select {
+			case <-done:
+			case <-ctx.Request().Context().Done():
+				return
+			}
 
-		// check if it's a valid response, if it's not then just return.
-		if !h.rule.Valid(ctx) {
-			return
+			if cached := h.get(key, ctx.Request().Header); cached != nil {
+				if response, valid := cached.Response(); valid {
+					h.write(ctx, cached, response)
+					return
+				}
+			}
+			// the response was not stored, i.e it varies, execute the handler.
+		} else {
+			defer h.flights.end(key)
 		}
-
-		// no need to copy the body, its already done inside
-		body := recorder.Body()
-		if len(body) == 0 {
-			// if no body then just exit.
+	} else {
+		// it's the background request of a stale entry,
+		// it waits for the requests which execute the handler already.
+		done, leader := h.flights.begin(key)
+		if !leader {
+			<-done
 			return
 		}
+		defer h.flights.end(key)
+	}
+
+	// if it's expired, then execute the original handler
+	// with our custom response recorder response writer
+	// because the net/http doesn't give us
+	// a built'n way to get the status code & body
+	recorder := ctx.Recorder()
+	bodyHandler(ctx)
+
+	if e != nil && isServerError(recorder.StatusCode()) && e.CanServeStaleIfError() {
+		// the handler failed, serve the expired response instead (stale-if-error).
+		recorder.Reset()
+		recorder.ResetHeaders()
+		h.write(ctx, e, e.StaleResponse())
+		return
+	}
+
+	if isServerError(recorder.StatusCode()) && isRevalidation(ctx) {
+		// keep the stale response, it's served until its stale durations pass.
+		return
+	}
 
-		// check for an expiration time if the
-		// given expiration was not valid then check for GetMaxAge &
-		// update the response & release the recorder
-		e.Reset(
-			recorder.StatusCode(),
-			recorder.Header(),
-			body,
-			parseLifeChanger(ctx),
-		)
-
-		// fmt.Printf("reset cache entry\n")
-		// fmt.Printf("key: %s\n", key)
-		// fmt.Printf("content type: %s\n", recorder.Header().Get(cfg.ContentTypeHeader))
-		// fmt.Printf("body len: %d\n", len(body))
+	// now that we have recordered the response,
+	// we are ready to check if that specific response is valid to be stored.
+
+	// check if it's a valid response, if it's not then just return.
+	if !h.rule.Valid(ctx) {
 		return
 	}
 
-	// if it's valid then just write the cached results
+	if len(recorder.Body()) == 0 {
+		// if no body then just exit.
+		return
+	}
+	// copy the body, the recorder is reused by the next requests.
+	body := append([]byte(nil), recorder.Body()...)
+
+	names, ok := parseVary(recorder.Header())
+	if !ok {
+		// "Vary: *", the response can't be served to any other request.
+		return
+	}
+
+	staleWhileRevalidate, staleIfError := h.parseStale(recorder.Header())
+
+	// check for an expiration time if the
+	// given expiration was not valid then check for GetMaxAge &
+	// update the response & release the recorder.
+	// A new entry is created so the stored one is never modified while it's served.
+	e = entry.NewEntry(h.expiration)
+	e.Reset(
+		recorder.StatusCode(),
+		recorder.Header(),
+		body,
+		parseLifeChanger(ctx),
+	)
+	e.SetStale(staleWhileRevalidate, staleIfError)
+	e.Meta = getMeta(ctx)
+
+	if len(names) > 0 {
+		// store the "Vary" header names under the request's key
+		// and the response under the key of the request headers' values.
+		varyEntry := entry.NewEntry(h.expiration)
+		varyEntry.Reset(0, map[string][]string{"Vary": names}, nil, parseLifeChanger(ctx))
+		varyEntry.SetStale(staleWhileRevalidate, staleIfError)
+		varyEntry.Meta = e.Meta
+		h.set(ctx, key, varyEntry)
+		h.set(ctx, variantKey(key, names, ctx.Request().Header), e)
+		return
+	}
+
+	h.set(ctx, key, e)
+}
+
+// get returns the stored entry of the "key",
+// if the response varies then it returns the entry of the request headers' values.
+func (h *Handler) get(key string, header http.Header) *entry.Entry {
+	e, ok := h.store.Get(key)
+	if !ok {
+		return nil
+	}
+
+	if names, ok := varyNames(e); ok {
+		// the response varies based on the request headers.
+		if e, ok = h.store.Get(variantKey(key, names, header)); !ok {
+			return nil
+		}
+	}
+
+	return e
+}
+
+// write writes the cached response.
+func (h *Handler) write(ctx context.Context, e *entry.Entry, response *entry.Response) {
 	entry.CopyHeaders(ctx.ResponseWriter().Header(), response.Headers())
 	ctx.SetLastModified(e.LastModified)
 	ctx.StatusCode(response.StatusCode())
 	ctx.Write(response.Body())
-
-	// fmt.Printf("key: %s\n", key)
-	// fmt.Printf("write content type: %s\n", response.Headers()["ContentType"])
-	// fmt.Printf("write body len: %d\n", len(response.Body()))
-
 }
@@ -0,0 +1,169 @@
+package client
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hidevopsio/iris/cache/entry"
+	"github.com/hidevopsio/iris/context"
+)
+
+// KeyFunc returns the key which the response of a request is cached with.
+type KeyFunc func(ctx context.Context) string
+
+// DefaultKeyFunc is the default `KeyFunc`,
+// it's unique per scheme, subdomains and paths with different url query.
+func DefaultKeyFunc(ctx context.Context) string {
+	scheme := "http"
+	if ctx.Request().TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + ctx.Host() + ctx.Request().URL.RequestURI()
+}
+
+// SessionValues is the minimum interface of a session,
+// completed by the `*sessions.Session`.
+//
+// See `Handler#VaryBySession`.
+type SessionValues interface {
+	GetString(key string) string
+}
+
+// keyPart returns a part of the key, which is appended to the result of the `KeyFunc`.
+type keyPart func(ctx context.Context) string
+
+// Key sets the function which returns the key of a request's response,
+// defaults to the `DefaultKeyFunc`.
+//
+// The `VaryByHeader`, `VaryByCookie`, `VaryBySession`
+// and the response's "Vary" header are applied to the result of it.
+//
+// returns itself.
+func (h *Handler) Key(fn KeyFunc) *Handler {
+	if fn == nil {
+		fn = DefaultKeyFunc
+	}
+
+	h.keyFunc = fn
+	return h
+}
+
+// VaryByHeader adds the values of the request headers to the cache key,
+// the responses of requests with different values are cached separately.
+//
+// Note that the response's "Vary" header is always respected,
+// use that when the response is not always different based on these headers.
+//
+// returns itself.
+func (h *Handler) VaryByHeader(names ...string) *Handler {
+	for _, name := range names {
+		name := http.CanonicalHeaderKey(name)
+		h.keyParts = append(h.keyParts, func(ctx context.Context) string {
+			return "h:" + name + "=" + strings.Join(ctx.Request().Header[name], ",")
+		})
+	}
+
+	return h
+}
+
+// VaryByCookie adds the values of the request cookies to the cache key,
+// the responses of requests with different values are cached separately.
+//
+// returns itself.
+func (h *Handler) VaryByCookie(names ...string) *Handler {
+	for _, name := range names {
+		name := name
+		h.keyParts = append(h.keyParts, func(ctx context.Context) string {
+			return "c:" + name + "=" + ctx.GetCookie(name)
+		})
+	}
+
+	return h
+}
+
+// VaryBySession adds the session's values of the "keys" to the cache key,
+// i.e a response per authenticated user.
+// The "start" should return the session of the request, i.e
+//
+//	func(ctx context.Context) client.SessionValues { return sess.Start(ctx) }
+//
+// returns itself.
+func (h *Handler) VaryBySession(start func(ctx context.Context) SessionValues, keys ...string) *Handler {
+	for _, key := range keys {
+		key := key
+		h.keyParts = append(h.keyParts, func(ctx context.Context) string {
+			return "s:" + key + "=" + start(ctx).GetString(key)
+		})
+	}
+
+	return h
+}
+
+func (h *Handler) getKey(ctx context.Context) string {
+	key := h.keyFunc(ctx)
+	for _, part := range h.keyParts {
+		key += "|" + part(ctx)
+	}
+
+	return key
+}
+
+// parseVary returns the sorted, canonical, request header names of the response's "Vary" header,
+// if it's "*" then it returns false as the response should not be cached at all.
+func parseVary(header http.Header) ([]string, bool) {
+	var names []string
+
+	for _, v := range header["Vary"] {
+		for _, name := range strings.Split(v, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+
+			if name == "*" {
+				return nil, false
+			}
+
+			name = http.CanonicalHeaderKey(name)
+			exists := false
+			for _, n := range names {
+				if n == name {
+					exists = true
+					break
+				}
+			}
+
+			if !exists {
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names, true
+}
+
+// variantKey returns the key of the response of the request's "Vary" headers' values.
+func variantKey(key string, names []string, header http.Header) string {
+	for _, name := range names {
+		key += "|v:" + name + "=" + strings.Join(header[name], ",")
+	}
+
+	return key
+}
+
+// varyNames returns the "Vary" header names if the "e" is the entry which is stored under the request's key
+// when the response varies, the actual response is stored under the `variantKey`.
+// These entries have no body, the responses with empty body are never cached.
+// The entry is checked even if it's expired, its stale variant can be served too.
+func varyNames(e *entry.Entry) ([]string, bool) {
+	response := e.StaleResponse()
+	if response == nil || len(response.Body()) > 0 {
+		return nil, false
+	}
+
+	names := response.Headers()["Vary"]
+	return names, len(names) > 0
+}
@@ -0,0 +1,166 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/hidevopsio/iris/cache/entry"
+	"github.com/hidevopsio/iris/cache/store"
+	"github.com/hidevopsio/iris/context"
+)
+
+// stores are the stores of the handlers,
+// the same store may be used by many handlers.
+var stores = struct {
+	mu   sync.RWMutex
+	refs map[store.Store]int
+}{refs: make(map[store.Store]int)}
+
+func registerStore(s store.Store) {
+	stores.mu.Lock()
+	stores.refs[s]++
+	stores.mu.Unlock()
+}
+
+func unregisterStore(s store.Store) {
+	stores.mu.Lock()
+	if stores.refs[s]--; stores.refs[s] <= 0 {
+		delete(stores.refs, s)
+	}
+	stores.mu.Unlock()
+}
+
+// Purge removes the stored responses, of all handlers, which the "match" reports true
+// and returns the number of the removed entries.
+func Purge(match func(key string, e *entry.Entry) bool) (n int) {
+	stores.mu.RLock()
+	list := make([]store.Store, 0, len(stores.refs))
+	for s := range stores.refs {
+		list = append(list, s)
+	}
+	stores.mu.RUnlock()
+
+	for _, s := range list {
+		s.Visit(func(key string, e *entry.Entry) {
+			if match(key, e) && s.Delete(key) == nil {
+				n++
+			}
+		})
+	}
+
+	return
+}
+
+// PurgeURL removes the stored responses of the "rawurl",
+// it's the request path and query, i.e "/users/42?lang=en",
+// or a full url to remove only the responses of a specific host, i.e "http://admin.mydomain.com/users/42".
+func PurgeURL(rawurl string) int {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return 0
+	}
+
+	uri := u.RequestURI()
+	return Purge(func(_ string, e *entry.Entry) bool {
+		return e.Meta.URI == uri && (u.Host == "" || e.Meta.Host == u.Host)
+	})
+}
+
+// PurgePrefix removes the stored responses of the request paths which start with the "prefix",
+// i.e "/users/" removes the responses of "/users/42" and "/users/42/friends".
+func PurgePrefix(prefix string) int {
+	return Purge(func(_ string, e *entry.Entry) bool {
+		return strings.HasPrefix(e.Meta.URI, prefix)
+	})
+}
+
+// PurgeRoute removes the stored responses of the route, see `APIBuilder#GetRoute`.
+func PurgeRoute(routeName string) int {
+	return Purge(func(_ string, e *entry.Entry) bool {
+		return e.Meta.Route == routeName
+	})
+}
+
+// PurgeTag removes the stored responses which have at least one of the "tags", see `Tag`.
+func PurgeTag(tags ...string) int {
+	return Purge(func(_ string, e *entry.Entry) bool {
+		for _, tag := range tags {
+			if e.Meta.HasTag(tag) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// TagsContextKey is the context's values key
+// which the tags of the current response are stored.
+const TagsContextKey = "iris.cache.tags"
+
+// Tag attaches the "tags" to the response, i.e "user:42",
+// the `PurgeTag` removes the stored responses of a tag.
+func Tag(ctx context.Context, tags ...string) {
+	existing, _ := ctx.Values().Get(TagsContextKey).([]string)
+	ctx.Values().Set(TagsContextKey, append(existing, tags...))
+}
+
+func getMeta(ctx context.Context) entry.Meta {
+	meta := entry.Meta{
+		Host: ctx.Host(),
+		URI:  ctx.Request().URL.RequestURI(),
+	}
+
+	if route := ctx.GetCurrentRoute(); route != nil {
+		meta.Route = route.Name()
+	}
+
+	meta.Tags, _ = ctx.Values().Get(TagsContextKey).([]string)
+	return meta
+}
+
+// PurgeHandler returns a handler which removes the stored responses,
+// based on the "url", "prefix", "route" and "tag" url query or form values, i.e
+// "/cache/purge?tag=user:42&tag=user:43" and "/cache/purge?route=GET/users/{id:int}".
+//
+// It writes the number of the removed entries as JSON, i.e {"purged": 2}.
+// Register it behind an authentication middleware.
+func PurgeHandler() context.Handler {
+	return func(ctx context.Context) {
+		values := ctx.FormValues()
+		if len(values["url"])+len(values["prefix"])+len(values["route"])+len(values["tag"]) == 0 {
+			ctx.StatusCode(http.StatusBadRequest)
+			ctx.WriteString("cache: one of the url, prefix, route and tag is required")
+			return
+		}
+
+		for _, routeName := range values["route"] {
+			if ctx.Application().GetRouteReadOnly(routeName) == nil {
+				ctx.StatusCode(http.StatusNotFound)
+				ctx.Writef("cache: route %s not found", routeName)
+				return
+			}
+		}
+
+		n := 0
+		for _, rawurl := range values["url"] {
+			n += PurgeURL(rawurl)
+		}
+
+		for _, prefix := range values["prefix"] {
+			n += PurgePrefix(prefix)
+		}
+
+		for _, routeName := range values["route"] {
+			n += PurgeRoute(routeName)
+		}
+
+		if tags := values["tag"]; len(tags) > 0 {
+			n += PurgeTag(tags...)
+		}
+
+		ctx.JSON(context.Map{"purged": n})
+	}
+}
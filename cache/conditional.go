@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hidevopsio/iris/context"
+)
+
+// ConditionalOptions are the options for the `Conditional` middleware.
+type ConditionalOptions struct {
+	// Weak generates weak entity tags, i.e W/"2c-8a3f", instead of strong ones,
+	// use it when the responses are semantically equivalent but not byte-for-byte identical,
+	// i.e compressed differently. Weak entity tags never satisfy an "If-Match" precondition.
+	Weak bool
+	// State returns the current entity tag and the last modification time of the requested resource,
+	// it's used to evaluate the "If-Match", "If-Unmodified-Since" and "If-None-Match"
+	// preconditions of the POST, PUT, PATCH and DELETE requests before their handlers are executed,
+	// i.e to prevent lost updates.
+	//
+	// Optional, if nil then these requests are not checked.
+	State func(ctx context.Context) (etag string, modtime time.Time)
+}
+
+// Conditional is a middleware which adds HTTP conditional requests support (RFC 7232) to dynamic responses.
+//
+// For GET and HEAD requests it records the response, generates its "ETag" from the body
+// (unless the handler has already set one), and evaluates the "If-None-Match", "If-Modified-Since",
+// "If-Match" and "If-Unmodified-Since" headers against it and the "Last-Modified" response header.
+// The body is replaced by a 304 Not Modified or a 412 Precondition Failed response when needed.
+//
+// For the rest of the methods, the `ConditionalOptions#State` is checked before the handler.
+//
+// Usage:
+// app.Get("/users/{id:int}", cache.Conditional(cache.ConditionalOptions{}), getUser)
+//
+// It's the dynamic version of the checks done by `StaticWeb` and `ServeContent`,
+// `ETag` and `Cache304` are simpler alternatives.
+func Conditional(options ConditionalOptions) context.Handler {
+	return func(ctx context.Context) {
+		if method := ctx.Method(); method != http.MethodGet && method != http.MethodHead {
+			if options.State != nil {
+				etag, modtime := options.State(ctx)
+				if etag != "" {
+					ctx.Header(context.ETagHeaderKey, etag)
+				}
+
+				if done, _ := context.CheckPreconditions(ctx, modtime); done {
+					return
+				}
+
+				// the handler's response is not the current state of the resource.
+				ctx.ResponseWriter().Header().Del(context.ETagHeaderKey)
+			}
+
+			ctx.Next()
+			return
+		}
+
+		recorder := ctx.Recorder()
+		ctx.Next()
+
+		if statusCode := recorder.StatusCode(); statusCode < 200 || statusCode >= 300 {
+			return
+		}
+
+		header := recorder.Header()
+		if header.Get(context.ETagHeaderKey) == "" {
+			header.Set(context.ETagHeaderKey, GenerateETag(recorder.Body(), options.Weak))
+		}
+
+		var modtime time.Time
+		if lastModified := header.Get(context.LastModifiedHeaderKey); lastModified != "" {
+			modtime, _ = context.ParseTime(ctx, lastModified)
+		}
+
+		if done, _ := context.CheckPreconditions(ctx, modtime); done {
+			// 304 or 412, the body is not sent.
+			recorder.ResetBody()
+		}
+	}
+}
+
+// GenerateETag returns a strong, or a weak if "weak" is true, entity tag of the "body",
+// it's its length and its 64-bit FNV-1a hash, i.e "2c-8a3f69b3d6a3e4f1".
+func GenerateETag(body []byte, weak bool) string {
+	h := fnv.New64a()
+	h.Write(body)
+
+	etag := `"` + strconv.FormatInt(int64(len(body)), 16) + "-" + strconv.FormatUint(h.Sum64(), 16) + `"`
+	if weak {
+		etag = "W/" + etag
+	}
+
+	return etag
+}
@@ -0,0 +1,64 @@
+package cache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hidevopsio/iris/cache"
+
+	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/context"
+	"github.com/hidevopsio/iris/httptest"
+)
+
+func TestConditional(t *testing.T) {
+	t.Parallel()
+	app := iris.New()
+
+	body := "conditional"
+	modtime := time.Date(2018, time.November, 1, 0, 0, 0, 0, time.UTC)
+	etag := cache.GenerateETag([]byte(body), false)
+
+	app.Get("/", cache.Conditional(cache.ConditionalOptions{}), func(ctx iris.Context) {
+		ctx.SetLastModified(modtime)
+		ctx.WriteString(body)
+	})
+
+	app.Get("/weak", cache.Conditional(cache.ConditionalOptions{Weak: true}), func(ctx iris.Context) {
+		ctx.WriteString(body)
+	})
+
+	app.Put("/", cache.Conditional(cache.ConditionalOptions{
+		State: func(ctx context.Context) (string, time.Time) { return etag, modtime },
+	}), func(ctx iris.Context) {
+		ctx.WriteString("updated")
+	})
+
+	e := httptest.New(t, app)
+	lastModified := modtime.Format(app.ConfigurationReadOnly().GetTimeFormat())
+
+	e.GET("/").Expect().Status(httptest.StatusOK).Header(context.ETagHeaderKey).Equal(etag)
+
+	// If-None-Match.
+	e.GET("/").WithHeader("If-None-Match", etag).Expect().Status(httptest.StatusNotModified).Body().Empty()
+	e.GET("/").WithHeader("If-None-Match", `"other", `+etag).Expect().Status(httptest.StatusNotModified)
+	e.GET("/").WithHeader("If-None-Match", `"other"`).Expect().Status(httptest.StatusOK).Body().Equal(body)
+	e.GET("/weak").WithHeader("If-None-Match", etag).Expect().Status(httptest.StatusNotModified).
+		Header(context.ETagHeaderKey).Equal("W/" + etag)
+
+	// If-Modified-Since.
+	e.GET("/").WithHeader("If-Modified-Since", lastModified).Expect().Status(httptest.StatusNotModified)
+	e.GET("/").WithHeader("If-Modified-Since", modtime.Add(-time.Hour).Format(app.ConfigurationReadOnly().GetTimeFormat())).
+		Expect().Status(httptest.StatusOK).Body().Equal(body)
+
+	// If-Match and If-Unmodified-Since.
+	e.GET("/").WithHeader("If-Match", `"other"`).Expect().Status(httptest.StatusPreconditionFailed)
+	e.GET("/weak").WithHeader("If-Match", "W/"+etag).Expect().Status(httptest.StatusPreconditionFailed)
+	e.GET("/").WithHeader("If-Unmodified-Since", modtime.Add(-time.Hour).Format(app.ConfigurationReadOnly().GetTimeFormat())).
+		Expect().Status(httptest.StatusPreconditionFailed)
+
+	// unsafe methods are checked against the state before the handler.
+	e.PUT("/").WithHeader("If-Match", etag).Expect().Status(httptest.StatusOK).Body().Equal("updated")
+	e.PUT("/").WithHeader("If-Match", `"stale"`).Expect().Status(httptest.StatusPreconditionFailed)
+	e.PUT("/").WithHeader("If-None-Match", "*").Expect().Status(httptest.StatusPreconditionFailed)
+}
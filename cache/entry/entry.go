@@ -1,6 +1,9 @@
 package entry
 
 import (
+	"bytes"
+	"encoding/gob"
+	"net/http"
 	"time"
 
 	"github.com/hidevopsio/iris/cache/cfg"
@@ -18,6 +21,15 @@ type Entry struct {
 	// some clients may need it.
 	LastModified time.Time
 
+	// the durations after the expiration which the response can be served stale,
+	// see `SetStale`.
+	staleWhileRevalidate time.Duration
+	staleIfError         time.Duration
+
+	// Meta describes the request of the response,
+	// it's used to find the entries to be purged.
+	Meta Meta
+
 	// Response the response should be served to the client
 	response *Response
 	// but we need the key to invalidate manually...xmm
@@ -25,6 +37,29 @@ type Entry struct {
 	// of store map
 }
 
+// Meta describes the request of a cached response.
+type Meta struct {
+	// Host is the request's host.
+	Host string
+	// URI is the request's path and query.
+	URI string
+	// Route is the name of the route which served the response.
+	Route string
+	// Tags are the tags of the response, see `cache#Tag`.
+	Tags []string
+}
+
+// HasTag reports whether the "tag" is one of the `Tags`.
+func (m Meta) HasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
 // NewEntry returns a new cache entry
 // it doesn't sets the expiresAt & the response
 // because these are setting each time on Reset
@@ -52,6 +87,73 @@ func (e *Entry) Response() (*Response, bool) {
 	return e.response, true
 }
 
+// ExpiresAt returns the time which this entry's response will not be valid anymore.
+func (e *Entry) ExpiresAt() time.Time {
+	return e.expiresAt
+}
+
+// Size returns the approximate size, in bytes, of the entry's response,
+// it's the body's length plus the headers' keys and values length.
+func (e *Entry) Size() int {
+	if e.response == nil {
+		return 0
+	}
+
+	n := len(e.response.body)
+	for k, vv := range e.response.headers {
+		n += len(k)
+		for _, v := range vv {
+			n += len(v)
+		}
+	}
+
+	return n
+}
+
+// SetStale sets the durations after the expiration time
+// which the response can be served stale, while it's revalidated
+// and when the revalidation fails, see the "stale-while-revalidate"
+// and "stale-if-error" Cache-Control extensions (RFC 5861).
+func (e *Entry) SetStale(whileRevalidate, ifError time.Duration) {
+	e.staleWhileRevalidate = whileRevalidate
+	e.staleIfError = ifError
+}
+
+// StaleResponse returns the response even if it's expired,
+// use it when the `CanServeStale` or `CanServeStaleIfError` reports true.
+func (e *Entry) StaleResponse() *Response {
+	return e.response
+}
+
+// CanServeStale reports whether the expired response
+// can be served while it's revalidated in the background.
+func (e *Entry) CanServeStale() bool {
+	return !time.Now().After(e.expiresAt.Add(e.staleWhileRevalidate))
+}
+
+// CanServeStaleIfError reports whether the expired response
+// can be served when its revalidation fails.
+func (e *Entry) CanServeStaleIfError() bool {
+	return !time.Now().After(e.expiresAt.Add(e.staleIfError))
+}
+
+// KeepUntil returns the time which this entry can be removed from the stores,
+// it's the expiration time plus the longest of the stale durations.
+func (e *Entry) KeepUntil() time.Time {
+	stale := e.staleWhileRevalidate
+	if e.staleIfError > stale {
+		stale = e.staleIfError
+	}
+
+	return e.expiresAt.Add(stale)
+}
+
+// Expired reports whether the `KeepUntil` time passed,
+// the response can't be served, not even stale.
+func (e *Entry) Expired() bool {
+	return time.Now().After(e.KeepUntil())
+}
+
 // valid returns true if this entry's response is still valid
 // or false if the expiration time passed
 func (e *Entry) valid() bool {
@@ -126,3 +228,62 @@ func (e *Entry) Reset(statusCode int, headers map[string][]string,
 	e.expiresAt = now.Add(e.life)
 	e.LastModified = now
 }
+
+// entryData is the encoded form of an Entry,
+// used by the stores which keep the entries outside of the memory.
+type entryData struct {
+	Life                 time.Duration
+	ExpiresAt            time.Time
+	LastModified         time.Time
+	StaleWhileRevalidate time.Duration
+	StaleIfError         time.Duration
+	Meta                 Meta
+	StatusCode           int
+	Headers              http.Header
+	Body                 []byte
+}
+
+// MarshalBinary encodes the entry, it completes the `encoding.BinaryMarshaler` interface.
+func (e *Entry) MarshalBinary() ([]byte, error) {
+	data := entryData{
+		Life:                 e.life,
+		ExpiresAt:            e.expiresAt,
+		LastModified:         e.LastModified,
+		StaleWhileRevalidate: e.staleWhileRevalidate,
+		StaleIfError:         e.staleIfError,
+		Meta:                 e.Meta,
+	}
+
+	if e.response != nil {
+		data.StatusCode = e.response.statusCode
+		data.Headers = e.response.headers
+		data.Body = e.response.body
+	}
+
+	var b bytes.Buffer
+	err := gob.NewEncoder(&b).Encode(data)
+	return b.Bytes(), err
+}
+
+// UnmarshalBinary decodes an entry encoded by the `MarshalBinary`,
+// it completes the `encoding.BinaryUnmarshaler` interface.
+func (e *Entry) UnmarshalBinary(b []byte) error {
+	var data entryData
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&data); err != nil {
+		return err
+	}
+
+	e.life = data.Life
+	e.expiresAt = data.ExpiresAt
+	e.LastModified = data.LastModified
+	e.staleWhileRevalidate = data.StaleWhileRevalidate
+	e.staleIfError = data.StaleIfError
+	e.Meta = data.Meta
+	e.response = &Response{
+		statusCode: data.StatusCode,
+		headers:    data.Headers,
+		body:       data.Body,
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package entry
 import (
 	"regexp"
 	"strconv"
+	"strings"
 )
 
 var maxAgeExp = regexp.MustCompile(`maxage=(\d+)`)
@@ -22,3 +23,21 @@ func ParseMaxAge(header string) int64 {
 	}
 	return -1
 }
+
+// ParseDirective parses the seconds of a "directive=seconds" of the "cache-control" header,
+// i.e "stale-while-revalidate" and "stale-if-error".
+// If the directive not found or parse failed then it returns -1.
+func ParseDirective(header, directive string) int64 {
+	for _, d := range strings.Split(header, ",") {
+		d = strings.TrimSpace(d)
+		if !strings.HasPrefix(d, directive+"=") {
+			continue
+		}
+
+		if v, err := strconv.ParseInt(strings.Trim(d[len(directive)+1:], `"`), 10, 64); err == nil {
+			return v
+		}
+	}
+
+	return -1
+}
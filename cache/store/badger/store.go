@@ -0,0 +1,185 @@
+package badger
+
+import (
+	"os"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/hidevopsio/iris/cache/entry"
+	"github.com/hidevopsio/iris/cache/store"
+	"github.com/hidevopsio/iris/core/errors"
+
+	"github.com/dgraph-io/badger"
+	"github.com/hidevopsio/golog"
+)
+
+// DefaultFileMode used as the default database's "fileMode"
+// for creating the cache directory path.
+var (
+	DefaultFileMode = 0755
+)
+
+// Store the badger(key-value file-based) cache storage.
+// The entries are expired by badger itself.
+type Store struct {
+	// Service is the underline badger database connection,
+	// it's initialized at `New` or `NewFromDB`.
+	// Can be used to get stats.
+	Service *badger.DB
+
+	closed uint32 // if 1 is closed.
+}
+
+var _ store.Store = (*Store)(nil)
+
+// New creates and returns a new badger(key-value file-based) cache storage
+// instance based on the "directoryPath".
+// DirectoryPath is the directory which the badger database will store the cached responses,
+// i.e ./cache
+func New(directoryPath string) (*Store, error) {
+	if directoryPath == "" {
+		return nil, errors.New("directoryPath is missing")
+	}
+
+	lindex := directoryPath[len(directoryPath)-1]
+	if lindex != os.PathSeparator && lindex != '/' {
+		directoryPath += string(os.PathSeparator)
+	}
+	// create directories if necessary
+	if err := os.MkdirAll(directoryPath, os.FileMode(DefaultFileMode)); err != nil {
+		return nil, err
+	}
+
+	opts := badger.DefaultOptions
+	opts.Dir = directoryPath
+	opts.ValueDir = directoryPath
+
+	service, err := badger.Open(opts)
+
+	if err != nil {
+		golog.Errorf("unable to initialize the badger-based cache storage: %v", err)
+		return nil, err
+	}
+
+	return NewFromDB(service), nil
+}
+
+// NewFromDB same as `New` but accepts an already-created custom badger connection instead.
+func NewFromDB(service *badger.DB) *Store {
+	s := &Store{Service: service}
+
+	runtime.SetFinalizer(s, closeDB)
+	return s
+}
+
+// Get returns the entry of the "key", if it's missing or it's expired then it returns false.
+func (s *Store) Get(key string) (*entry.Entry, bool) {
+	e := new(entry.Entry)
+
+	err := s.Service.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			return err
+		}
+
+		b, err := item.Value()
+		if err != nil {
+			return err
+		}
+
+		return e.UnmarshalBinary(b)
+	})
+
+	if err != nil {
+		if err != badger.ErrKeyNotFound {
+			golog.Error(err)
+		}
+		return nil, false
+	}
+
+	if e.Expired() {
+		return nil, false
+	}
+
+	return e, true
+}
+
+// Set saves the entry "e" under the "key" until the entry's `KeepUntil` time.
+func (s *Store) Set(key string, e *entry.Entry) error {
+	b, err := e.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	return s.Service.Update(func(txn *badger.Txn) error {
+		return txn.SetWithTTL([]byte(key), b, time.Until(e.KeepUntil()))
+	})
+}
+
+// Delete removes the entry of the "key".
+func (s *Store) Delete(key string) error {
+	return s.Service.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(key))
+	})
+}
+
+// Visit calls the "visitor" for each one of the stored entries.
+func (s *Store) Visit(visitor func(key string, e *entry.Entry)) error {
+	var (
+		keys    []string
+		entries []*entry.Entry
+	)
+
+	err := s.Service.View(func(txn *badger.Txn) error {
+		iter := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer iter.Close()
+
+		for iter.Rewind(); iter.Valid(); iter.Next() {
+			item := iter.Item()
+			b, err := item.Value()
+			if err != nil {
+				return err
+			}
+
+			e := new(entry.Entry)
+			if err = e.UnmarshalBinary(b); err != nil || e.Expired() {
+				continue
+			}
+
+			keys = append(keys, string(item.KeyCopy(nil)))
+			entries = append(entries, e)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// visit after the transaction, so the visitor can modify the store.
+	for i := range keys {
+		visitor(keys[i], entries[i])
+	}
+
+	return nil
+}
+
+// Close shutdowns the badger connection.
+func (s *Store) Close() error {
+	return closeDB(s)
+}
+
+func closeDB(s *Store) error {
+	if atomic.LoadUint32(&s.closed) > 0 {
+		return nil
+	}
+	err := s.Service.Close()
+	if err != nil {
+		golog.Warnf("closing the badger connection: %v", err)
+	} else {
+		atomic.StoreUint32(&s.closed, 1)
+	}
+	return err
+}
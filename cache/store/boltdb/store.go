@@ -0,0 +1,214 @@
+package boltdb
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hidevopsio/iris/cache/entry"
+	"github.com/hidevopsio/iris/cache/store"
+	"github.com/hidevopsio/iris/core/errors"
+
+	bolt "github.com/etcd-io/bbolt"
+	"github.com/hidevopsio/golog"
+)
+
+// DefaultFileMode used as the default database's "fileMode"
+// for creating the cache directory path, opening and write
+// the cache boltdb(file-based) storage.
+var (
+	DefaultFileMode = 0755
+)
+
+// Store the BoltDB(file-based) cache storage.
+// BoltDB has no expiration support,
+// the expired entries are removed every `store.DefaultSweepInterval`.
+type Store struct {
+	table []byte
+	// Service is the underline BoltDB database connection,
+	// it's initialized at `New` or `NewFromDB`.
+	// Can be used to get stats.
+	Service *bolt.DB
+
+	stopSweep func()
+}
+
+var _ store.Store = (*Store)(nil)
+
+var errPathMissing = errors.New("path is required")
+
+// New creates and returns a new BoltDB(file-based) cache storage
+// instance based on the "path".
+// Path should include the filename and the directory(aka fullpath), i.e cache/store.db.
+func New(path string, fileMode os.FileMode) (*Store, error) {
+	if path == "" {
+		golog.Error(errPathMissing)
+		return nil, errPathMissing
+	}
+
+	if fileMode <= 0 {
+		fileMode = os.FileMode(DefaultFileMode)
+	}
+
+	// create directories if necessary
+	if err := os.MkdirAll(filepath.Dir(path), fileMode); err != nil {
+		golog.Errorf("error while trying to create the necessary directories for %s: %v", path, err)
+		return nil, err
+	}
+
+	service, err := bolt.Open(path, fileMode,
+		&bolt.Options{Timeout: 20 * time.Second},
+	)
+
+	if err != nil {
+		golog.Errorf("unable to initialize the BoltDB-based cache storage: %v", err)
+		return nil, err
+	}
+
+	return NewFromDB(service, "cache")
+}
+
+// NewFromDB same as `New` but accepts an already-created custom boltdb connection instead.
+func NewFromDB(service *bolt.DB, bucketName string) (*Store, error) {
+	bucket := []byte(bucketName)
+
+	err := service.Update(func(tx *bolt.Tx) (err error) {
+		_, err = tx.CreateBucketIfNotExists(bucket)
+		return
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{table: bucket, Service: service}
+	s.stopSweep = store.Sweep(store.DefaultSweepInterval, s.Sweep)
+	return s, nil
+}
+
+// Get returns the entry of the "key", if it's missing or it's expired then it returns false.
+func (s *Store) Get(key string) (*entry.Entry, bool) {
+	var e *entry.Entry
+
+	err := s.Service.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.table).Get([]byte(key))
+		if b == nil {
+			return nil
+		}
+
+		e = new(entry.Entry)
+		return e.UnmarshalBinary(b)
+	})
+
+	if err != nil {
+		golog.Error(err)
+		return nil, false
+	}
+
+	if e == nil {
+		return nil, false
+	}
+
+	if e.Expired() {
+		return nil, false
+	}
+
+	return e, true
+}
+
+// Set saves the entry "e" under the "key" until the entry's `KeepUntil` time.
+func (s *Store) Set(key string, e *entry.Entry) error {
+	b, err := e.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	return s.Service.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.table).Put([]byte(key), b)
+	})
+}
+
+// Delete removes the entry of the "key".
+func (s *Store) Delete(key string) error {
+	return s.Service.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.table).Delete([]byte(key))
+	})
+}
+
+// Visit calls the "visitor" for each one of the stored entries.
+func (s *Store) Visit(visitor func(key string, e *entry.Entry)) error {
+	var (
+		keys    []string
+		entries []*entry.Entry
+	)
+
+	err := s.Service.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.table).ForEach(func(k, v []byte) error {
+			e := new(entry.Entry)
+			if err := e.UnmarshalBinary(v); err != nil || e.Expired() {
+				return nil
+			}
+
+			keys = append(keys, string(k))
+			entries = append(entries, e)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// visit after the transaction, so the visitor can modify the store.
+	for i := range keys {
+		visitor(keys[i], entries[i])
+	}
+
+	return nil
+}
+
+// Sweep removes the expired entries,
+// it's called automatically every `store.DefaultSweepInterval`.
+func (s *Store) Sweep() {
+	err := s.Service.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.table)
+
+		var expired [][]byte
+		err := bucket.ForEach(func(k, v []byte) error {
+			e := new(entry.Entry)
+			if err := e.UnmarshalBinary(v); err == nil && !e.Expired() {
+				return nil
+			}
+
+			expired = append(expired, append([]byte(nil), k...))
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+
+		for _, k := range expired {
+			if err = bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		golog.Warnf("removing the expired entries of the BoltDB cache storage: %v", err)
+	}
+}
+
+// Close stops the sweeping of the expired entries and shutdowns the BoltDB connection.
+func (s *Store) Close() error {
+	s.stopSweep()
+	err := s.Service.Close()
+	if err != nil {
+		golog.Warnf("closing the BoltDB connection: %v", err)
+	}
+
+	return err
+}
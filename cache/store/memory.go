@@ -0,0 +1,246 @@
+package store
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/hidevopsio/iris/cache/entry"
+)
+
+// EvictionPolicy describes which entry the `Memory` store removes
+// when one of its limits is reached.
+type EvictionPolicy uint8
+
+const (
+	// LRU evicts the least recently used entry. The default policy.
+	LRU EvictionPolicy = iota
+	// LFU evicts the least frequently used entry,
+	// between entries with the same hits the least recently used is evicted.
+	LFU
+)
+
+// MemoryConfig is the configuration for the `Memory` store.
+type MemoryConfig struct {
+	// MaxEntries is the maximum number of the stored entries,
+	// zero means no limit.
+	MaxEntries int
+	// MaxBytes is the maximum size of the stored responses,
+	// zero means no limit. Responses larger than it are never stored.
+	MaxBytes int
+	// Policy is the eviction policy when one of the limits is reached,
+	// defaults to the `LRU`.
+	Policy EvictionPolicy
+	// SweepInterval is the interval which the expired entries are removed,
+	// defaults to the `DefaultSweepInterval`.
+	SweepInterval time.Duration
+}
+
+type memoryItem struct {
+	key   string
+	entry *entry.Entry
+	size  int
+	hits  uint64
+	tick  uint64 // the last access.
+	index int    // the index in the heap.
+}
+
+// Memory is the in-memory, bounded, `Store`.
+// The least recently or frequently used entries are evicted
+// when the `MemoryConfig#MaxEntries` or the `MemoryConfig#MaxBytes` is reached.
+type Memory struct {
+	config MemoryConfig
+
+	mu    sync.Mutex
+	items map[string]*memoryItem
+	queue memoryQueue
+	bytes int
+	tick  uint64
+
+	stopSweep func()
+}
+
+var _ Store = (*Memory)(nil)
+
+// NewMemory returns a new in-memory store based on the "config".
+// Call its `Close` to stop the background sweeping of the expired entries.
+func NewMemory(config MemoryConfig) *Memory {
+	m := &Memory{
+		config: config,
+		items:  make(map[string]*memoryItem),
+	}
+	m.queue.policy = config.Policy
+	m.stopSweep = Sweep(config.SweepInterval, m.Sweep)
+	return m
+}
+
+// Get returns the entry of the "key", if it's missing or it's expired then it returns false.
+func (m *Memory) Get(key string) (*entry.Entry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	if item.entry.Expired() {
+		m.remove(item)
+		return nil, false
+	}
+
+	m.tick++
+	item.tick = m.tick
+	item.hits++
+	heap.Fix(&m.queue, item.index)
+
+	return item.entry, true
+}
+
+// Set saves the entry "e" under the "key", the least used entries
+// are evicted if the store's limits are reached.
+func (m *Memory) Set(key string, e *entry.Entry) error {
+	size := len(key) + e.Size()
+	if m.config.MaxBytes > 0 && size > m.config.MaxBytes {
+		// it can't fit, keep the rest of the entries instead.
+		return m.Delete(key)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var hits uint64
+	if item, ok := m.items[key]; ok {
+		hits = item.hits
+		m.remove(item)
+	}
+
+	// make room before the new entry is added, so it's not the one to be evicted.
+	for len(m.items) > 0 && m.overflows(1, size) {
+		m.remove(m.queue.items[0])
+	}
+
+	m.tick++
+	item := &memoryItem{key: key, entry: e, size: size, hits: hits, tick: m.tick}
+	m.items[key] = item
+	m.bytes += size
+	heap.Push(&m.queue, item)
+
+	return nil
+}
+
+// overflows reports whether adding "n" entries of "size" bytes exceeds the limits.
+func (m *Memory) overflows(n, size int) bool {
+	return (m.config.MaxEntries > 0 && len(m.items)+n > m.config.MaxEntries) ||
+		(m.config.MaxBytes > 0 && m.bytes+size > m.config.MaxBytes)
+}
+
+// Delete removes the entry of the "key".
+func (m *Memory) Delete(key string) error {
+	m.mu.Lock()
+	if item, ok := m.items[key]; ok {
+		m.remove(item)
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+// Visit calls the "visitor" for each one of the stored entries.
+func (m *Memory) Visit(visitor func(key string, e *entry.Entry)) error {
+	m.mu.Lock()
+	items := make([]*memoryItem, 0, len(m.items))
+	for _, item := range m.items {
+		items = append(items, item)
+	}
+	m.mu.Unlock()
+
+	for _, item := range items {
+		if !item.entry.Expired() {
+			visitor(item.key, item.entry)
+		}
+	}
+
+	return nil
+}
+
+func (m *Memory) remove(item *memoryItem) {
+	heap.Remove(&m.queue, item.index)
+	delete(m.items, item.key)
+	m.bytes -= item.size
+}
+
+// Sweep removes the expired entries,
+// it's called automatically every `MemoryConfig#SweepInterval`.
+func (m *Memory) Sweep() {
+	now := time.Now()
+
+	m.mu.Lock()
+	for _, item := range m.items {
+		if now.After(item.entry.KeepUntil()) {
+			m.remove(item)
+		}
+	}
+	m.mu.Unlock()
+}
+
+// Len returns the number of the stored entries.
+func (m *Memory) Len() int {
+	m.mu.Lock()
+	n := len(m.items)
+	m.mu.Unlock()
+	return n
+}
+
+// Bytes returns the size of the stored entries.
+func (m *Memory) Bytes() int {
+	m.mu.Lock()
+	n := m.bytes
+	m.mu.Unlock()
+	return n
+}
+
+// Close stops the background sweeping of the expired entries.
+func (m *Memory) Close() error {
+	m.mu.Lock()
+	m.stopSweep()
+	m.mu.Unlock()
+	return nil
+}
+
+// memoryQueue is a min-heap of the memory items,
+// its first item is the next one to be evicted based on the policy.
+type memoryQueue struct {
+	policy EvictionPolicy
+	items  []*memoryItem
+}
+
+func (q memoryQueue) Len() int { return len(q.items) }
+
+func (q memoryQueue) Less(i, j int) bool {
+	a, b := q.items[i], q.items[j]
+	if q.policy == LFU && a.hits != b.hits {
+		return a.hits < b.hits
+	}
+
+	return a.tick < b.tick
+}
+
+func (q memoryQueue) Swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.items[i].index = i
+	q.items[j].index = j
+}
+
+func (q *memoryQueue) Push(x interface{}) {
+	item := x.(*memoryItem)
+	item.index = len(q.items)
+	q.items = append(q.items, item)
+}
+
+func (q *memoryQueue) Pop() interface{} {
+	n := len(q.items)
+	item := q.items[n-1]
+	q.items[n-1] = nil
+	q.items = q.items[0 : n-1]
+	return item
+}
@@ -0,0 +1,100 @@
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hidevopsio/iris/cache/entry"
+	"github.com/hidevopsio/iris/cache/store"
+)
+
+func newEntry(body string, expiration time.Duration) *entry.Entry {
+	e := entry.NewEntry(expiration)
+	e.Reset(200, nil, []byte(body), nil)
+	return e
+}
+
+func expectKeys(t *testing.T, s store.Store, found []string, missing []string) {
+	t.Helper()
+
+	for _, key := range found {
+		if _, ok := s.Get(key); !ok {
+			t.Fatalf("expected key: %s to be stored", key)
+		}
+	}
+
+	for _, key := range missing {
+		if _, ok := s.Get(key); ok {
+			t.Fatalf("expected key: %s to be evicted", key)
+		}
+	}
+}
+
+func TestMemoryLRU(t *testing.T) {
+	s := store.NewMemory(store.MemoryConfig{MaxEntries: 2})
+	defer s.Close()
+
+	s.Set("a", newEntry("a", time.Minute))
+	s.Set("b", newEntry("b", time.Minute))
+	s.Get("a") // "b" is the least recently used now.
+	s.Set("c", newEntry("c", time.Minute))
+
+	expectKeys(t, s, []string{"a", "c"}, []string{"b"})
+	if expected, got := 2, s.Len(); expected != got {
+		t.Fatalf("expected length: %d but got: %d", expected, got)
+	}
+}
+
+func TestMemoryLFU(t *testing.T) {
+	s := store.NewMemory(store.MemoryConfig{MaxEntries: 2, Policy: store.LFU})
+	defer s.Close()
+
+	s.Set("a", newEntry("a", time.Minute))
+	s.Set("b", newEntry("b", time.Minute))
+	s.Get("a")
+	s.Get("a")
+	s.Get("b") // "b" is the most recently used but the least frequently used.
+	s.Set("c", newEntry("c", time.Minute))
+
+	expectKeys(t, s, []string{"a", "c"}, []string{"b"})
+}
+
+func TestMemoryMaxBytes(t *testing.T) {
+	s := store.NewMemory(store.MemoryConfig{MaxBytes: 10})
+	defer s.Close()
+
+	s.Set("a", newEntry("1234", time.Minute)) // 5 bytes.
+	s.Set("b", newEntry("1234", time.Minute)) // 10 bytes.
+	s.Set("c", newEntry("1234", time.Minute)) // 15 bytes, "a" is evicted.
+	s.Set("d", newEntry("1234567890", time.Minute))
+
+	expectKeys(t, s, []string{"b", "c"}, []string{"a", "d"})
+	if expected, got := 10, s.Bytes(); expected != got {
+		t.Fatalf("expected bytes: %d but got: %d", expected, got)
+	}
+
+	// replace with a larger response.
+	s.Set("b", newEntry("12345678", time.Minute))
+	expectKeys(t, s, []string{"b"}, []string{"c"})
+	if expected, got := 9, s.Bytes(); expected != got {
+		t.Fatalf("expected bytes: %d but got: %d", expected, got)
+	}
+}
+
+func TestMemorySweep(t *testing.T) {
+	s := store.NewMemory(store.MemoryConfig{SweepInterval: 10 * time.Millisecond})
+	defer s.Close()
+
+	s.Set("expired", newEntry("expired", -time.Second))
+	s.Set("valid", newEntry("valid", time.Minute))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for s.Len() != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the expired entry to be removed but the store has %d entries", s.Len())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	expectKeys(t, s, []string{"valid"}, []string{"expired"})
+}
@@ -0,0 +1,113 @@
+package redis
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hidevopsio/iris/cache/entry"
+	"github.com/hidevopsio/iris/cache/store"
+	"github.com/hidevopsio/iris/sessions/sessiondb/redis/service"
+
+	"github.com/hidevopsio/golog"
+)
+
+// Store the redis cache storage,
+// the same redis database can be shared between many instances of the application.
+// The entries are expired by redis itself.
+type Store struct {
+	redis *service.Service
+}
+
+var _ store.Store = (*Store)(nil)
+
+// New returns a new redis cache storage,
+// it returns an error if the redis server can't be reached.
+// Use the `Config#Prefix` to separate the cached responses
+// from other data that may be stored in the same redis database.
+func New(cfg ...service.Config) (*Store, error) {
+	s := service.New(cfg...)
+	s.Connect()
+	if _, err := s.PingPong(); err != nil {
+		return nil, fmt.Errorf("cache: connect to redis: %v", err)
+	}
+
+	return &Store{redis: s}, nil
+}
+
+// Config returns the configuration for the redis server bridge, you can change them.
+func (s *Store) Config() *service.Config {
+	return s.redis.Config
+}
+
+// Get returns the entry of the "key", if it's missing or it's expired then it returns false.
+func (s *Store) Get(key string) (*entry.Entry, bool) {
+	b, err := s.redis.GetBytes(key)
+	if err != nil {
+		return nil, false
+	}
+
+	e := new(entry.Entry)
+	if err = e.UnmarshalBinary(b); err != nil {
+		golog.Error(err)
+		return nil, false
+	}
+
+	if e.Expired() {
+		return nil, false
+	}
+
+	return e, true
+}
+
+// Set saves the entry "e" under the "key" until the entry's `KeepUntil` time.
+func (s *Store) Set(key string, e *entry.Entry) error {
+	b, err := e.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	// redis expires by seconds, round it up, the entry checks its expiration on `Get` anyway.
+	secondsLifetime := int64(math.Ceil(time.Until(e.KeepUntil()).Seconds()))
+	if secondsLifetime <= 0 {
+		return s.redis.Delete(key)
+	}
+
+	return s.redis.Set(key, b, secondsLifetime)
+}
+
+// Delete removes the entry of the "key".
+func (s *Store) Delete(key string) error {
+	return s.redis.Delete(key)
+}
+
+// Visit calls the "visitor" for each one of the stored entries,
+// all the keys of the redis database which start with the `Config#Prefix` are visited.
+func (s *Store) Visit(visitor func(key string, e *entry.Entry)) error {
+	keys, err := s.redis.GetKeys("")
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		b, err := s.redis.GetBytes(key)
+		if err != nil {
+			continue
+		}
+
+		// skip the values which are not cache entries.
+		e := new(entry.Entry)
+		if err = e.UnmarshalBinary(b); err != nil || e.Expired() {
+			continue
+		}
+
+		visitor(key, e)
+	}
+
+	return nil
+}
+
+// Close terminates the redis connection.
+func (s *Store) Close() error {
+	return s.redis.CloseConnection()
+}
@@ -0,0 +1,69 @@
+// Package store provides the storages of the server-side cached responses.
+//
+// The `Memory` store lives in this package, the disk-based and the redis stores
+// live in their own sub-packages: "badger", "boltdb" and "redis".
+package store
+
+import (
+	"time"
+
+	"github.com/hidevopsio/iris/cache/entry"
+)
+
+// DefaultSweepInterval is the default interval which the stores
+// remove their expired entries.
+var DefaultSweepInterval = time.Minute
+
+// Store is the storage of the cached responses.
+//
+// Implementations should be safe for concurrent use
+// and should never return an `Expired` entry.
+type Store interface {
+	// Get returns the entry of the "key",
+	// if it's missing or it's `Expired` then it returns false.
+	Get(key string) (*entry.Entry, bool)
+	// Set saves the entry "e" under the "key" until the entry's `KeepUntil` time,
+	// which may be later than its expiration time when the entry can be served stale.
+	// The entry should not be modified after `Set`.
+	Set(key string, e *entry.Entry) error
+	// Delete removes the entry of the "key".
+	Delete(key string) error
+	// Visit calls the "visitor" for each one of the stored entries,
+	// the "visitor" can `Delete` the visited entry.
+	Visit(visitor func(key string, e *entry.Entry)) error
+	// Close stops the background work of the store and releases its resources.
+	Close() error
+}
+
+// Sweep calls the "sweep" every "interval" until the returned function is called,
+// it's a helper for the stores which have to remove their expired entries manually.
+//
+// If "interval" <= 0 then the `DefaultSweepInterval` is used instead.
+func Sweep(interval time.Duration, sweep func()) (stop func()) {
+	if interval <= 0 {
+		interval = DefaultSweepInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				sweep()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	var stopped bool
+	return func() {
+		if !stopped {
+			stopped = true
+			close(done)
+		}
+	}
+}
@@ -242,6 +242,13 @@ var WithoutAutoFireStatusCode = func(app *Application) {
 	app.config.DisableAutoFireStatusCode = true
 }
 
+// WithProblemDetails enables the ProblemDetails setting.
+//
+// See `Configuration`.
+var WithProblemDetails = func(app *Application) {
+	app.config.EnableProblemDetails = true
+}
+
 // WithPathEscape enanbles the PathEscape setting.
 //
 // See `Configuration`.
@@ -382,6 +389,7 @@ type Configuration struct {
 	// for example, if /home/ path is requested but no handler for this Route found,
 	// then the Router checks if /home handler exists, if yes,
 	// (permant)redirects the client to the correct path /home
+	// or serves it, depending on the route's `PathPolicy`, see `Party#SetPathPolicy`.
 	//
 	// Defaults to false.
 	DisablePathCorrection bool `json:"disablePathCorrection,omitempty" yaml:"DisablePathCorrection" toml:"DisablePathCorrection"`
@@ -433,6 +441,16 @@ type Configuration struct {
 	// Defaults to false.
 	DisableAutoFireStatusCode bool `json:"disableAutoFireStatusCode,omitempty" yaml:"DisableAutoFireStatusCode" toml:"DisableAutoFireStatusCode"`
 
+	// EnableProblemDetails if true then the errors are sent as problem details (RFC 7807),
+	// with the "application/problem+json" content type, instead of plain text.
+	// It's respected by the default http error code handlers, the `context#ReadJSON`
+	// and the hero and mvc error results, see `context.Problem` for more.
+	//
+	// The custom http error handlers can read the original error via "context#GetErr()".
+	//
+	// Defaults to false.
+	EnableProblemDetails bool `json:"enableProblemDetails,omitempty" yaml:"EnableProblemDetails" toml:"EnableProblemDetails"`
+
 	// TimeFormat time format for any kind of datetime parsing
 	// Defaults to  "Mon, 02 Jan 2006 15:04:05 GMT".
 	TimeFormat string `json:"timeFormat,omitempty" yaml:"TimeFormat" toml:"TimeFormat"`
@@ -561,6 +579,12 @@ func (c Configuration) GetDisableAutoFireStatusCode() bool {
 	return c.DisableAutoFireStatusCode
 }
 
+// GetEnableProblemDetails returns the Configuration#EnableProblemDetails.
+// Returns true when the errors are sent as problem details (RFC 7807).
+func (c Configuration) GetEnableProblemDetails() bool {
+	return c.EnableProblemDetails
+}
+
 // GetTimeFormat returns the Configuration#TimeFormat,
 // format for any kind of datetime parsing.
 func (c Configuration) GetTimeFormat() string {
@@ -687,6 +711,10 @@ func WithConfiguration(c Configuration) Configurator {
 			main.DisableAutoFireStatusCode = v
 		}
 
+		if v := c.EnableProblemDetails; v {
+			main.EnableProblemDetails = v
+		}
+
 		if v := c.TimeFormat; v != "" {
 			main.TimeFormat = v
 		}
@@ -745,6 +773,7 @@ func DefaultConfiguration() Configuration {
 		FireMethodNotAllowed:              false,
 		DisableBodyConsumptionOnUnmarshal: false,
 		DisableAutoFireStatusCode:         false,
+		EnableProblemDetails:              false,
 		TimeFormat:                        "Mon, Jan 02 2006 15:04:05 GMT",
 		Charset:                           "UTF-8",
 
@@ -23,6 +23,12 @@ type Application interface {
 	// Returns an error on failure, otherwise nil.
 	View(writer io.Writer, filename string, layout string, bindingData interface{}) error
 
+	// ViewFragment executes and write the result of a template file's named block to the writer.
+	//
+	// Use context.ViewFragment to render template fragments to the client instead.
+	// Returns an error on failure, otherwise nil.
+	ViewFragment(writer io.Writer, filename string, block string, bindingData interface{}) error
+
 	// ServeHTTPC is the internal router, it's visible because it can be used for advanced use cases,
 	// i.e: routing within a foreign context.
 	//
@@ -54,6 +60,10 @@ type Application interface {
 	// then it creates & registers a new trivial handler on the-fly.
 	FireErrorCode(ctx Context)
 
+	// GetContextPool returns the pool of the contexts,
+	// it's used to acquire the contexts of the handlers which run by the `Timeout` middleware.
+	GetContextPool() *Pool
+
 	// RouteExists reports whether a particular route exists
 	// It will search from the current subdomain of context's host, if not inside the root domain.
 	RouteExists(ctx Context, method, path string) bool
@@ -45,6 +45,10 @@ type ConfigurationReadOnly interface {
 	// Returns true when the http error status code handler automatic execution turned off.
 	GetDisableAutoFireStatusCode() bool
 
+	// GetEnableProblemDetails returns the configuration.EnableProblemDetails.
+	// Returns true when the errors are sent as problem details (RFC 7807).
+	GetEnableProblemDetails() bool
+
 	// GetTimeFormat returns the configuration.TimeFormat,
 	// format for any kind of datetime parsing.
 	GetTimeFormat() string
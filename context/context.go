@@ -2,6 +2,7 @@ package context
 
 import (
 	"bytes"
+	stdContext "context"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
@@ -16,6 +17,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"runtime/debug"
 	"strconv"
 	"strings"
 	"sync/atomic"
@@ -114,6 +116,18 @@ type Context interface {
 
 	// Request returns the original *http.Request, as expected.
 	Request() *http.Request
+	// ResetRequest sets the Context's Request,
+	// It is useful to store the new request created by a std *http.Request#WithContext() into Iris' Context.
+	// Use `ResetRequest` when for some reason you want to make a full
+	// override of the *http.Request.
+	// Note that: when you just want to change one of each fields you can use the Request() which returns a pointer to Request,
+	// so the changes will have affect without a full override.
+	// Usage: you use a native http handler which uses the standard "context" package
+	// to get values instead of the Iris' Context#Values():
+	// r := ctx.Request()
+	// stdCtx := context.WithValue(r.Context(), key, val)
+	// ctx.ResetRequest(r.WithContext(stdCtx)).
+	ResetRequest(r *http.Request)
 
 	// SetCurrentRouteName sets the route's name internally,
 	// in order to be able to find the correct current "read-only" Route when
@@ -279,6 +293,12 @@ type Context interface {
 	//
 	// Example: https://github.com/hidevopsio/iris/tree/master/_examples/miscellaneous/i18n
 	Translate(format string, args ...interface{}) string
+	// SetErr sets the error of the request, i.e the reason of an http error status code,
+	// the http error code handlers can read it via the `GetErr`.
+	// This storage, as the whole Context, is per-request lifetime.
+	SetErr(err error)
+	// GetErr returns the error of the request, if any, see `SetErr`.
+	GetErr() error
 
 	//  +------------------------------------------------------------+
 	//  | Path, Host, Subdomain, IP, Headers etc...                  |
@@ -554,6 +574,9 @@ type Context interface {
 	// ReadJSON reads JSON from request's body and binds it to a pointer of a value of any json-valid type.
 	//
 	// Example: https://github.com/hidevopsio/iris/blob/master/_examples/http_request/read-json/main.go
+	//
+	// If the `Configuration.EnableProblemDetails` is true then its errors are `Problem`s
+	// of the 400 Bad Request status code.
 	ReadJSON(jsonObjectPtr interface{}) error
 	// ReadXML reads XML from request's body and binds it to a pointer of a value of any xml-valid type.
 	//
@@ -732,6 +755,16 @@ type Context interface {
 	//
 	// Examples: https://github.com/hidevopsio/iris/tree/master/_examples/view
 	View(filename string, optionalViewModel ...interface{}) error
+	// ViewFragment renders a single named block (fragment) of a template to the client,
+	// without its layout, i.e for partial page updates of libraries like the htmx.
+	// The view engine of the template file should support fragments,
+	// the html, django, handlebars and amber ones do.
+	//
+	// First argument accepts the filename, like the `View`.
+	// Second argument accepts the name of the template's block.
+	// The third optional argument can receive a single "view model",
+	// otherwise the view data stored by the `ViewData` are used, like the `View`.
+	ViewFragment(filename string, block string, optionalViewModel ...interface{}) error
 
 	// Binary writes out the raw bytes as binary data.
 	Binary(data []byte) (int, error)
@@ -749,6 +782,11 @@ type Context interface {
 	Markdown(markdownB []byte, options ...Markdown) (int, error)
 	// YAML parses the "v" using the yaml parser and renders its result to the client.
 	YAML(v interface{}) (int, error)
+	// Problem writes out the problem details (RFC 7807) as "application/problem+json",
+	// its status code defaults to 500 and its title to the status code's text.
+	//
+	// See `Problem` type for more.
+	Problem(p Problem) (int, error)
 	//  +------------------------------------------------------------+
 	//  | Serve files                                                |
 	//  +------------------------------------------------------------+
@@ -932,6 +970,134 @@ var LimitRequestBodySize = func(maxRequestBodySizeBytes int64) Handler {
 	}
 }
 
+// DefaultTimeoutStatusCode is the http error code which is fired
+// by the `Timeout` middleware when a zero status code is given.
+const DefaultTimeoutStatusCode = http.StatusServiceUnavailable
+
+// Timeout is a middleware which runs the next handlers in the chain with a deadline.
+// The request's std context, `ctx.Request().Context()`, is canceled when the "timeout" is exceeded,
+// handlers that do slow work (database queries, downstream http calls...) should use that
+// in order to stop as soon as possible.
+//
+// Like the `http.TimeoutHandler`, the next handlers run in a new goroutine,
+// on a Context of the application's pool which records their response,
+// the response is copied to this Context's recorder when they are returned.
+// If they did not return before the deadline then the recorder is reset
+// and the "statusCode" error (`DefaultTimeoutStatusCode` if zero, 503) is fired immediately instead,
+// so it can be customized through the `OnErrorCode` handlers, i.e 503 or 504,
+// anything they write after that is discarded.
+//
+// Note that the response of the next handlers is not streamed, the `Flush` and `Hijack` are not supported.
+var Timeout = func(timeout time.Duration, statusCode int) Handler {
+	if statusCode <= 0 {
+		statusCode = DefaultTimeoutStatusCode
+	}
+
+	return func(ctx Context) {
+		stdCtx, cancel := stdContext.WithTimeout(ctx.Request().Context(), timeout)
+		defer cancel()
+
+		pool := ctx.Application().GetContextPool()
+		// the response of the next handlers is recorded, their headers are kept by the headerWriter.
+		tctx := pool.Acquire(make(headerWriter), ctx.Request().Clone(stdCtx))
+		tctx.Record()
+		copyRequestState(tctx, ctx)
+		tctx.SetHandlers(ctx.Handlers())
+		tctx.HandlerIndex(ctx.HandlerIndex(-1))
+
+		release := func() {
+			// their response is copied to this Context, nothing is flushed or fired.
+			tctx.ResponseWriter().EndResponse()
+			pool.ReleaseLight(tctx)
+		}
+
+		done := make(chan struct{})
+		expired := make(chan struct{})
+		panicChan := make(chan interface{}, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicChan <- &TimeoutPanic{Value: p, Stack: debug.Stack()}
+					release()
+				}
+			}()
+
+			tctx.Next()
+
+			select {
+			case done <- struct{}{}:
+				// the Timeout copies the response and releases the tctx.
+			case <-expired:
+				release()
+			}
+		}()
+
+		ctx.Record()
+		rec, recording := ctx.IsRecording()
+
+		select {
+		case p := <-panicChan:
+			panic(p)
+		case <-done:
+			copyRequestState(ctx, tctx)
+			ctx.HandlerIndex(tctx.HandlerIndex(-1))
+
+			h := ctx.ResponseWriter().Header()
+			for key, values := range tctx.ResponseWriter().Header() {
+				h[key] = values
+			}
+
+			ctx.StatusCode(tctx.GetStatusCode())
+			if trec, ok := tctx.IsRecording(); ok && len(trec.Body()) > 0 {
+				ctx.Write(trec.Body())
+			}
+
+			release()
+		case <-stdCtx.Done():
+			close(expired)
+
+			if recording {
+				// discard anything that was written before, i.e headers.
+				rec.Reset()
+			}
+
+			if stdCtx.Err() == stdContext.DeadlineExceeded {
+				ctx.StatusCode(statusCode)
+			}
+		}
+	}
+}
+
+// TimeoutPanic is the value of the panic which is raised by the `Timeout` middleware
+// when one of its handlers panics, it contains the stack of the handler's goroutine.
+type TimeoutPanic struct {
+	Value interface{}
+	Stack []byte
+}
+
+// String returns the panic value and the stack trace of the handler.
+func (p *TimeoutPanic) String() string {
+	return fmt.Sprintf("%v\n\n%s", p.Value, p.Stack)
+}
+
+// copyRequestState copies the route, path parameters and values of the "src" to the "dst" Context.
+func copyRequestState(dst, src Context) {
+	if route := src.GetCurrentRoute(); route != nil {
+		dst.SetCurrentRouteName(route.Name())
+	}
+
+	dst.Params().Store = append(dst.Params().Store[0:0], src.Params().Store...)
+	*dst.Values() = append((*dst.Values())[0:0], *src.Values()...)
+}
+
+// headerWriter is the http.ResponseWriter of the `Timeout` handlers' Context,
+// their response is recorded, it only keeps their headers apart of the client's ones.
+type headerWriter http.Header
+
+func (h headerWriter) Header() http.Header         { return http.Header(h) }
+func (h headerWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (h headerWriter) WriteHeader(statusCode int)  {}
+
 // Gzip is a middleware which enables writing
 // using gzip compression, if client supports.
 var Gzip = func(ctx Context) {
@@ -968,6 +1134,8 @@ type context struct {
 	handlers Handlers
 	// the current position of the handler's chain
 	currentHandlerIndex int
+	// the error of the request, see `SetErr`.
+	err error
 }
 
 // NewContext returns the default, internal, context implementation.
@@ -995,6 +1163,7 @@ func (ctx *context) BeginRequest(w http.ResponseWriter, r *http.Request) {
 	ctx.params.Store = ctx.params.Store[0:0]
 	ctx.request = r
 	ctx.currentHandlerIndex = 0
+	ctx.err = nil
 	ctx.writer = AcquireResponseWriter()
 	ctx.writer.BeginResponse(w)
 }
@@ -1065,6 +1234,21 @@ func (ctx *context) Request() *http.Request {
 	return ctx.request
 }
 
+// ResetRequest sets the Context's Request,
+// It is useful to store the new request created by a std *http.Request#WithContext() into Iris' Context.
+// Use `ResetRequest` when for some reason you want to make a full
+// override of the *http.Request.
+// Note that: when you just want to change one of each fields you can use the Request() which returns a pointer to Request,
+// so the changes will have affect without a full override.
+// Usage: you use a native http handler which uses the standard "context" package
+// to get values instead of the Iris' Context#Values():
+// r := ctx.Request()
+// stdCtx := context.WithValue(r.Context(), key, val)
+// ctx.ResetRequest(r.WithContext(stdCtx)).
+func (ctx *context) ResetRequest(r *http.Request) {
+	ctx.request = r
+}
+
 // SetCurrentRouteName sets the route's name internally,
 // in order to be able to find the correct current "read-only" Route when
 // end-developer calls the `GetCurrentRoute()` function.
@@ -1391,6 +1575,18 @@ func (ctx *context) Translate(format string, args ...interface{}) string {
 	return ""
 }
 
+// SetErr sets the error of the request, i.e the reason of an http error status code,
+// the http error code handlers can read it via the `GetErr`.
+// This storage, as the whole context, is per-request lifetime.
+func (ctx *context) SetErr(err error) {
+	ctx.err = err
+}
+
+// GetErr returns the error of the request, if any, see `SetErr`.
+func (ctx *context) GetErr() error {
+	return ctx.err
+}
+
 //  +------------------------------------------------------------+
 //  | Path, Host, Subdomain, IP, Headers etc...                  |
 //  +------------------------------------------------------------+
@@ -2274,12 +2470,20 @@ func (ctx *context) shouldOptimize() bool {
 // ReadJSON reads JSON from request's body and binds it to a value of any json-valid type.
 //
 // Example: https://github.com/hidevopsio/iris/blob/master/_examples/http_request/read-json/main.go
+//
+// If the `Configuration.EnableProblemDetails` is true then its errors are `Problem`s
+// of the 400 Bad Request status code.
 func (ctx *context) ReadJSON(jsonObject interface{}) error {
 	var unmarshaler = json.Unmarshal
 	if ctx.shouldOptimize() {
 		unmarshaler = jsoniter.Unmarshal
 	}
-	return ctx.UnmarshalBody(jsonObject, UnmarshalerFunc(unmarshaler))
+	err := ctx.UnmarshalBody(jsonObject, UnmarshalerFunc(unmarshaler))
+	if err != nil && ctx.Application().ConfigurationReadOnly().GetEnableProblemDetails() {
+		return NewProblem(http.StatusBadRequest, err)
+	}
+
+	return err
 }
 
 // ReadXML reads XML from request's body and binds it to a value of any xml-valid type.
@@ -2732,6 +2936,34 @@ func (ctx *context) View(filename string, optionalViewModel ...interface{}) erro
 	return err
 }
 
+// ViewFragment renders a single named block (fragment) of a template to the client,
+// without its layout, i.e for partial page updates of libraries like the htmx.
+// The view engine of the template file should support fragments,
+// the html, django, handlebars and amber ones do.
+//
+// First argument accepts the filename, like the `View`.
+// Second argument accepts the name of the template's block.
+// The third optional argument can receive a single "view model",
+// otherwise the view data stored by the `ViewData` are used, like the `View`.
+func (ctx *context) ViewFragment(filename string, block string, optionalViewModel ...interface{}) error {
+	ctx.ContentType(ContentHTMLHeaderValue)
+
+	var bindingData interface{}
+	if len(optionalViewModel) > 0 {
+		bindingData = optionalViewModel[0]
+	} else {
+		bindingData = ctx.values.Get(ctx.Application().ConfigurationReadOnly().GetViewDataContextKey())
+	}
+
+	err := ctx.Application().ViewFragment(ctx.writer, filename, block, bindingData)
+	if err != nil {
+		ctx.StatusCode(http.StatusInternalServerError)
+		ctx.StopExecution()
+	}
+
+	return err
+}
+
 const (
 	// ContentBinaryHeaderValue header value for binary data.
 	ContentBinaryHeaderValue = "application/octet-stream"
@@ -2749,6 +2981,8 @@ const (
 	ContentMarkdownHeaderValue = "text/markdown"
 	// ContentYAMLHeaderValue header value for YAML data.
 	ContentYAMLHeaderValue = "application/x-yaml"
+	// ContentProblemHeaderValue header value for the problem details (RFC 7807).
+	ContentProblemHeaderValue = "application/problem+json"
 )
 
 // Binary writes out the raw bytes as binary data.
@@ -3051,6 +3285,30 @@ func (ctx *context) YAML(v interface{}) (int, error) {
 	return ctx.Write(out)
 }
 
+// Problem writes out the problem details (RFC 7807) as "application/problem+json",
+// its status code defaults to 500 and its title to the status code's text.
+//
+// See `Problem` type for more.
+func (ctx *context) Problem(p Problem) (int, error) {
+	if p.Status == 0 {
+		p.Status = http.StatusInternalServerError
+	}
+
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	out, err := json.MarshalIndent(p, "", " ")
+	if err != nil {
+		ctx.StatusCode(http.StatusInternalServerError)
+		return 0, err
+	}
+
+	ctx.ContentType(ContentProblemHeaderValue)
+	ctx.StatusCode(p.Status)
+	return ctx.Write(out)
+}
+
 //  +------------------------------------------------------------+
 //  | Serve files                                                |
 //  +------------------------------------------------------------+
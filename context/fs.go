@@ -0,0 +1,357 @@
+package context
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// The file systems below are `http.FileSystem`s, they are accepted by the view engines' `FS`
+// and the router's `StaticFS` and they can be combined with each other.
+// An `io/fs#FS`, i.e an `embed.FS`, can be used through the `http.FS` adapter.
+
+// cleanPath returns the "name" as a rooted, slash-separated and clean path.
+func cleanPath(name string) string {
+	return path.Clean("/" + filepath.ToSlash(name))
+}
+
+// BindataFS returns a read-only `http.FileSystem` of the files which are embedded
+// with the go-bindata tool, the "assetFn" and "namesFn" are its `Asset` and `AssetNames` functions.
+//
+// The files are opened by their asset names, i.e "/assets/css/main.css"
+// for the "assets/css/main.css" asset, see `SubFS` too.
+func BindataFS(assetFn func(name string) ([]byte, error), namesFn func() []string) http.FileSystem {
+	fs := &bindataFS{
+		assetFn: assetFn,
+		names:   make(map[string]string),
+		dirs:    map[string][]string{"/": nil},
+	}
+
+	for _, name := range namesFn() {
+		p := cleanPath(name)
+		fs.names[p] = name
+
+		// register the file to its parent directories.
+		for child, dir := p, path.Dir(p); ; child, dir = dir, path.Dir(dir) {
+			children, exists := fs.dirs[dir]
+			fs.dirs[dir] = append(children, path.Base(child))
+			if exists || dir == "/" {
+				break
+			}
+		}
+	}
+
+	return fs
+}
+
+type bindataFS struct {
+	assetFn func(name string) ([]byte, error)
+	// the asset names by their clean paths.
+	names map[string]string
+	// the names of the directories' entries.
+	dirs map[string][]string
+}
+
+func (fs *bindataFS) Open(name string) (http.File, error) {
+	name = cleanPath(name)
+
+	if children, ok := fs.dirs[name]; ok {
+		entries := make([]os.FileInfo, 0, len(children))
+		for _, child := range children {
+			p := path.Join(name, child)
+			if _, isDir := fs.dirs[p]; isDir {
+				entries = append(entries, &fileInfo{name: child, dir: true})
+				continue
+			}
+
+			b, err := fs.assetFn(fs.names[p])
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, &fileInfo{name: child, size: int64(len(b))})
+		}
+
+		return &dir{info: &fileInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+	}
+
+	assetName, ok := fs.names[name]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+
+	b, err := fs.assetFn(assetName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &file{
+		Reader: bytes.NewReader(b),
+		info:   &fileInfo{name: path.Base(name), size: int64(len(b))},
+	}, nil
+}
+
+// SubFS returns an `http.FileSystem` of the "dir" directory of the "fs",
+// i.e `SubFS(BindataFS(Asset, AssetNames), "./assets")` opens the "/css/main.css"
+// as the "/assets/css/main.css" of the embedded files.
+func SubFS(fs http.FileSystem, dir string) http.FileSystem {
+	dir = cleanPath(dir)
+	if dir == "/" {
+		return fs
+	}
+
+	return &subFS{fs: fs, dir: dir}
+}
+
+type subFS struct {
+	fs  http.FileSystem
+	dir string
+}
+
+func (fs *subFS) Open(name string) (http.File, error) {
+	return fs.fs.Open(path.Join(fs.dir, cleanPath(name)))
+}
+
+// OverlayFS returns a union `http.FileSystem` of the "filesystems",
+// a file is opened from the first of the "filesystems" which contains it
+// and the entries of a directory are the entries of that directory of all the "filesystems".
+//
+// Use it to shadow embedded files with local ones, i.e
+// `OverlayFS(http.Dir("./overrides"), SubFS(BindataFS(Asset, AssetNames), "./assets"))`.
+func OverlayFS(filesystems ...http.FileSystem) http.FileSystem {
+	return overlayFS(filesystems)
+}
+
+type overlayFS []http.FileSystem
+
+func (filesystems overlayFS) Open(name string) (http.File, error) {
+	var dirs []http.File
+
+	for _, fs := range filesystems {
+		f, err := fs.Open(name)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			closeFiles(dirs)
+			return nil, err
+		}
+
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			closeFiles(dirs)
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			if len(dirs) > 0 {
+				// a directory of a previous file system shadows this file.
+				f.Close()
+				break
+			}
+
+			return f, nil
+		}
+
+		dirs = append(dirs, f)
+	}
+
+	switch len(dirs) {
+	case 0:
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	case 1:
+		return dirs[0], nil
+	default:
+		return &overlayDir{File: dirs[0], layers: dirs}, nil
+	}
+}
+
+func closeFiles(files []http.File) {
+	for _, f := range files {
+		f.Close()
+	}
+}
+
+// overlayDir is a directory which exists in more than one of the file systems of an overlay.
+type overlayDir struct {
+	http.File
+	layers  []http.File
+	entries []os.FileInfo
+	read    bool
+	offset  int
+}
+
+func (d *overlayDir) Readdir(count int) ([]os.FileInfo, error) {
+	if !d.read {
+		seen := make(map[string]bool)
+		for _, layer := range d.layers {
+			entries, err := layer.Readdir(-1)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, entry := range entries {
+				if !seen[entry.Name()] {
+					seen[entry.Name()] = true
+					d.entries = append(d.entries, entry)
+				}
+			}
+		}
+
+		sort.Slice(d.entries, func(i, j int) bool { return d.entries[i].Name() < d.entries[j].Name() })
+		d.read = true
+	}
+
+	return readdir(d.entries, &d.offset, count)
+}
+
+func (d *overlayDir) Close() error {
+	var err error
+	for _, layer := range d.layers {
+		if closeErr := layer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// readdir returns the next "count" of the "entries" like the `os.File#Readdir`.
+func readdir(entries []os.FileInfo, offset *int, count int) ([]os.FileInfo, error) {
+	rest := entries[*offset:]
+	if count <= 0 {
+		*offset = len(entries)
+		return rest, nil
+	}
+
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+
+	if count > len(rest) {
+		count = len(rest)
+	}
+
+	*offset += count
+	return rest[:count], nil
+}
+
+// WalkFS walks the "root" directory of the "fs" in lexical order,
+// the "walkFn" is called for each file and directory with its slash-separated path,
+// if it returns the `filepath.SkipDir` for a directory then its files are skipped.
+func WalkFS(fs http.FileSystem, root string, walkFn func(path string, info os.FileInfo) error) error {
+	root = cleanPath(root)
+
+	f, err := fs.Open(root)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	var entries []os.FileInfo
+	if info.IsDir() {
+		entries, err = f.Readdir(-1)
+	}
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	if err = walkFn(root, info); err != nil || !info.IsDir() {
+		if err == filepath.SkipDir {
+			err = nil
+		}
+		return err
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	for _, entry := range entries {
+		p := path.Join(root, entry.Name())
+		if !entry.IsDir() {
+			if err = walkFn(p, entry); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err = WalkFS(fs, p, walkFn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadFile reads the "name" file of the "fs".
+func ReadFile(fs http.FileSystem, name string) ([]byte, error) {
+	f, err := fs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ioutil.ReadAll(f)
+}
+
+type fileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.size }
+func (fi *fileInfo) ModTime() time.Time { return time.Time{} }
+func (fi *fileInfo) IsDir() bool        { return fi.dir }
+func (fi *fileInfo) Sys() interface{}   { return nil }
+
+func (fi *fileInfo) Mode() os.FileMode {
+	if fi.dir {
+		return os.ModeDir | 0555
+	}
+
+	return 0444
+}
+
+// file is an in-memory `http.File`.
+type file struct {
+	*bytes.Reader
+	info os.FileInfo
+}
+
+func (f *file) Close() error                             { return nil }
+func (f *file) Readdir(count int) ([]os.FileInfo, error) { return nil, errNotDir }
+func (f *file) Stat() (os.FileInfo, error)               { return f.info, nil }
+
+var errNotDir = errors.New("not a directory")
+
+// dir is an in-memory directory `http.File`.
+type dir struct {
+	info    os.FileInfo
+	entries []os.FileInfo
+	offset  int
+}
+
+func (d *dir) Close() error                                 { return nil }
+func (d *dir) Read(p []byte) (int, error)                   { return 0, errNotFile }
+func (d *dir) Seek(offset int64, whence int) (int64, error) { return 0, errNotFile }
+func (d *dir) Stat() (os.FileInfo, error)                   { return d.info, nil }
+
+func (d *dir) Readdir(count int) ([]os.FileInfo, error) {
+	return readdir(d.entries, &d.offset, count)
+}
+
+var errNotFile = errors.New("is a directory")
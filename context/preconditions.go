@@ -0,0 +1,213 @@
+package context
+
+import (
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+func etagEmptyOrStrongMatch(rangeValue string, etagValue string) bool {
+	etag, _ := scanETag(rangeValue)
+	if etag != "" {
+		if etagStrongMatch(etag, etagValue) {
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// scanETag determines if a syntactically valid ETag is present at s. If so,
+// the ETag and remaining text after consuming ETag is returned. Otherwise,
+// it returns "", "".
+func scanETag(s string) (etag string, remain string) {
+	s = textproto.TrimString(s)
+	start := 0
+	if strings.HasPrefix(s, "W/") {
+		start = 2
+	}
+	if len(s[start:]) < 2 || s[start] != '"' {
+		return "", ""
+	}
+	// ETag is either W/"text" or "text".
+	// See RFC 7232 2.3.
+	for i := start + 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		// Character values allowed in ETags.
+		case c == 0x21 || c >= 0x23 && c <= 0x7E || c >= 0x80:
+		case c == '"':
+			return string(s[:i+1]), s[i+1:]
+		default:
+			break
+		}
+	}
+	return "", ""
+}
+
+// etagStrongMatch reports whether a and b match using strong ETag comparison.
+// Assumes a and b are valid ETags.
+func etagStrongMatch(a, b string) bool {
+	return a == b && a != "" && a[0] == '"'
+}
+
+// etagWeakMatch reports whether a and b match using weak ETag comparison.
+// Assumes a and b are valid ETags.
+func etagWeakMatch(a, b string) bool {
+	return strings.TrimPrefix(a, "W/") == strings.TrimPrefix(b, "W/")
+}
+
+// condResult is the result of an HTTP request precondition check.
+// See https://tools.ietf.org/html/rfc7232 section 3.
+type condResult int
+
+const (
+	condNone condResult = iota
+	condTrue
+	condFalse
+)
+
+func checkIfMatch(ctx Context) condResult {
+	im := ctx.GetHeader("If-Match")
+	if im == "" {
+		return condNone
+	}
+	for {
+		im = textproto.TrimString(im)
+		if len(im) == 0 {
+			break
+		}
+		if im[0] == ',' {
+			im = im[1:]
+			continue
+		}
+		if im[0] == '*' {
+			return condTrue
+		}
+		etag, remain := scanETag(im)
+		if etag == "" {
+			break
+		}
+		if etagStrongMatch(etag, ctx.ResponseWriter().Header().Get("Etag")) {
+			return condTrue
+		}
+		im = remain
+	}
+
+	return condFalse
+}
+
+func checkIfNoneMatch(ctx Context) condResult {
+	inm := ctx.GetHeader("If-None-Match")
+	if inm == "" {
+		return condNone
+	}
+	buf := inm
+	for {
+		buf = textproto.TrimString(buf)
+		if len(buf) == 0 {
+			break
+		}
+		if buf[0] == ',' {
+			buf = buf[1:]
+			continue
+		}
+		if buf[0] == '*' {
+			return condFalse
+		}
+		etag, remain := scanETag(buf)
+		if etag == "" {
+			break
+		}
+		if etagWeakMatch(etag, ctx.ResponseWriter().Header().Get("Etag")) {
+			return condFalse
+		}
+		buf = remain
+	}
+	return condTrue
+}
+
+// CheckPreconditions evaluates the request preconditions, the "If-Match", "If-Unmodified-Since",
+// "If-None-Match", "If-Modified-Since" and "If-Range" headers, against the response's "ETag" header and the "modtime",
+// and reports whether a precondition resulted in sending StatusNotModified or StatusPreconditionFailed.
+// The "rangeHeader" is the "Range" request header, if it's not ignored because of the "If-Range".
+func CheckPreconditions(ctx Context, modtime time.Time) (done bool, rangeHeader string) {
+	// This function carefully follows RFC 7232 section 6.
+	ch := checkIfMatch(ctx)
+	if ch == condNone {
+		ch = checkIfUnmodifiedSince(ctx, modtime)
+	}
+	if ch == condFalse {
+
+		ctx.StatusCode(http.StatusPreconditionFailed)
+		return true, ""
+	}
+	switch checkIfNoneMatch(ctx) {
+	case condFalse:
+		if ctx.Method() == http.MethodGet || ctx.Method() == http.MethodHead {
+			ctx.WriteNotModified()
+			return true, ""
+		}
+		ctx.StatusCode(http.StatusPreconditionFailed)
+		return true, ""
+
+	case condNone:
+		if modified, err := ctx.CheckIfModifiedSince(modtime); !modified && err == nil {
+			ctx.WriteNotModified()
+			return true, ""
+		}
+	}
+
+	rangeHeader = ctx.GetHeader("Range")
+	if rangeHeader != "" {
+		if checkIfRange(ctx, etagEmptyOrStrongMatch, modtime) == condFalse {
+			rangeHeader = ""
+		}
+	}
+	return false, rangeHeader
+}
+
+func checkIfUnmodifiedSince(ctx Context, modtime time.Time) condResult {
+	ius := ctx.GetHeader("If-Unmodified-Since")
+	if ius == "" || IsZeroTime(modtime) {
+		return condNone
+	}
+	if t, err := ParseTime(ctx, ius); err == nil {
+		// The Date-Modified header truncates sub-second precision, so
+		// use mtime < t+1s instead of mtime <= t to check for unmodified.
+		if modtime.Before(t.Add(1 * time.Second)) {
+			return condTrue
+		}
+		return condFalse
+	}
+	return condNone
+}
+
+func checkIfRange(ctx Context, etagEmptyOrStrongMatch func(ifRangeValue string, etagValue string) bool, modtime time.Time) condResult {
+	if ctx.Method() != http.MethodGet {
+		return condNone
+	}
+	ir := ctx.GetHeader("If-Range")
+	if ir == "" {
+		return condNone
+	}
+
+	if etagEmptyOrStrongMatch(ir, ctx.ResponseWriter().Header().Get("Etag")) {
+		return condTrue
+	}
+
+	// The If-Range value is typically the ETag value, but it may also be
+	// the modtime date. See golang.org/issue/8367.
+	if modtime.IsZero() {
+		return condFalse
+	}
+	t, err := ParseTime(ctx, ir)
+	if err != nil {
+		return condFalse
+	}
+	if t.Unix() == modtime.Unix() {
+		return condTrue
+	}
+	return condFalse
+}
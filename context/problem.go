@@ -0,0 +1,114 @@
+package context
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Problem is the problem details of an HTTP API error (RFC 7807),
+// it's sent as "application/problem+json" via the `Context#Problem`.
+//
+// The framework sends its errors as problem details when
+// the `Configuration.EnableProblemDetails` is true,
+// i.e the default http error code handlers and the `Context#ReadJSON` errors.
+//
+// Example:
+//
+//	ctx.Problem(context.Problem{
+//		Type:   "https://example.com/probs/out-of-credit",
+//		Title:  "You do not have enough credit.",
+//		Status: iris.StatusForbidden,
+//		Detail: "Your current balance is 30, but that costs 50.",
+//		Extensions: map[string]interface{}{"balance": 30},
+//	})
+type Problem struct {
+	// Type is a URI reference that identifies the problem type, defaults to "about:blank".
+	Type string
+	// Title is a short, human-readable summary of the problem type,
+	// defaults to the status code's text.
+	Title string
+	// Status is the HTTP status code, defaults to 500.
+	Status int
+	// Detail is a human-readable explanation specific to this occurrence of the problem.
+	Detail string
+	// Instance is a URI reference that identifies the specific occurrence of the problem,
+	// i.e the request's path.
+	Instance string
+	// Extensions are any additional members of the problem details.
+	Extensions map[string]interface{}
+}
+
+// NewProblem returns the problem details of the "statusCode",
+// its detail is the "err"'s message, if it's not nil.
+// If the "err" is a `Problem` already then it's returned instead.
+func NewProblem(statusCode int, err error) Problem {
+	if p, ok := err.(Problem); ok {
+		return p
+	}
+
+	p := Problem{Status: statusCode}
+	if err != nil {
+		p.Detail = err.Error()
+	}
+
+	return p
+}
+
+// Error completes the error interface.
+func (p Problem) Error() string {
+	title := p.Title
+	if title == "" {
+		title = http.StatusText(p.Status)
+	}
+
+	if p.Detail != "" {
+		if title == "" {
+			return p.Detail
+		}
+
+		return title + ": " + p.Detail
+	}
+
+	return title
+}
+
+// MarshalJSON writes the problem details as a single JSON object,
+// the extensions are written as members of it.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+
+	m["type"] = p.Type
+	if p.Title != "" {
+		m["title"] = p.Title
+	}
+
+	if p.Status > 0 {
+		m["status"] = p.Status
+	}
+
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+
+	return json.Marshal(m)
+}
+
+// Dispatch writes the problem details to the client, see `Context#Problem`,
+// and stops the execution of the next handlers, the response is the error.
+//
+// It completes the hero and mvc `Result` interface.
+func (p Problem) Dispatch(ctx Context) {
+	ctx.Problem(p)
+	ctx.StopExecution()
+}
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hidevopsio/iris/core/memstore"
 )
@@ -94,10 +95,12 @@ var (
 	//
 	// Key is the specific type, which should be unique.
 	// The value is a function which accepts the parameter index
-	// and it should return the value as the parameter type evaluator expects it.
+	// and it should return the value as the parameter type evaluator expects it,
+	// or its zero value if the parameter is optional and it was omitted from the request path.
 	// i.e [reflect.TypeOf("string")] = func(paramIndex int) interface{} {
 	//     return func(ctx Context) <T> {
-	//         return ctx.Params().GetEntryAt(paramIndex).ValueRaw.(<T>)
+	//         v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(<T>)
+	//         return v
 	//     }
 	// }
 	//
@@ -105,64 +108,92 @@ var (
 	ParamResolvers = map[reflect.Type]func(paramIndex int) interface{}{
 		reflect.TypeOf(""): func(paramIndex int) interface{} {
 			return func(ctx Context) string {
-				return ctx.Params().GetEntryAt(paramIndex).ValueRaw.(string)
+				v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(string)
+				return v
 			}
 		},
 		reflect.TypeOf(int(1)): func(paramIndex int) interface{} {
 			return func(ctx Context) int {
-				// v, _ := ctx.Params().GetEntryAt(paramIndex).IntDefault(0)
-				// return v
-				return ctx.Params().GetEntryAt(paramIndex).ValueRaw.(int)
+				v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(int)
+				return v
 			}
 		},
 		reflect.TypeOf(int8(1)): func(paramIndex int) interface{} {
 			return func(ctx Context) int8 {
-				return ctx.Params().GetEntryAt(paramIndex).ValueRaw.(int8)
+				v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(int8)
+				return v
 			}
 		},
 		reflect.TypeOf(int16(1)): func(paramIndex int) interface{} {
 			return func(ctx Context) int16 {
-				return ctx.Params().GetEntryAt(paramIndex).ValueRaw.(int16)
+				v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(int16)
+				return v
 			}
 		},
 		reflect.TypeOf(int32(1)): func(paramIndex int) interface{} {
 			return func(ctx Context) int32 {
-				return ctx.Params().GetEntryAt(paramIndex).ValueRaw.(int32)
+				v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(int32)
+				return v
 			}
 		},
 		reflect.TypeOf(int64(1)): func(paramIndex int) interface{} {
 			return func(ctx Context) int64 {
-				return ctx.Params().GetEntryAt(paramIndex).ValueRaw.(int64)
+				v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(int64)
+				return v
 			}
 		},
 		reflect.TypeOf(uint(1)): func(paramIndex int) interface{} {
 			return func(ctx Context) uint {
-				return ctx.Params().GetEntryAt(paramIndex).ValueRaw.(uint)
+				v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(uint)
+				return v
 			}
 		},
 		reflect.TypeOf(uint8(1)): func(paramIndex int) interface{} {
 			return func(ctx Context) uint8 {
-				return ctx.Params().GetEntryAt(paramIndex).ValueRaw.(uint8)
+				v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(uint8)
+				return v
 			}
 		},
 		reflect.TypeOf(uint16(1)): func(paramIndex int) interface{} {
 			return func(ctx Context) uint16 {
-				return ctx.Params().GetEntryAt(paramIndex).ValueRaw.(uint16)
+				v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(uint16)
+				return v
 			}
 		},
 		reflect.TypeOf(uint32(1)): func(paramIndex int) interface{} {
 			return func(ctx Context) uint32 {
-				return ctx.Params().GetEntryAt(paramIndex).ValueRaw.(uint32)
+				v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(uint32)
+				return v
 			}
 		},
 		reflect.TypeOf(uint64(1)): func(paramIndex int) interface{} {
 			return func(ctx Context) uint64 {
-				return ctx.Params().GetEntryAt(paramIndex).ValueRaw.(uint64)
+				v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(uint64)
+				return v
+			}
+		},
+		reflect.TypeOf(float32(1)): func(paramIndex int) interface{} {
+			return func(ctx Context) float32 {
+				v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(float32)
+				return v
+			}
+		},
+		reflect.TypeOf(float64(1)): func(paramIndex int) interface{} {
+			return func(ctx Context) float64 {
+				v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(float64)
+				return v
+			}
+		},
+		reflect.TypeOf(time.Time{}): func(paramIndex int) interface{} {
+			return func(ctx Context) time.Time {
+				v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(time.Time)
+				return v
 			}
 		},
 		reflect.TypeOf(true): func(paramIndex int) interface{} {
 			return func(ctx Context) bool {
-				return ctx.Params().GetEntryAt(paramIndex).ValueRaw.(bool)
+				v, _ := ctx.Params().GetEntryAt(paramIndex).ValueRaw.(bool)
+				return v
 			}
 		},
 	}
@@ -58,8 +58,10 @@ func (w *ResponseRecorder) BeginRecord(underline ResponseWriter) {
 // EndResponse is auto-called when the whole client's request is done,
 // releases the response recorder and its underline ResponseWriter.
 func (w *ResponseRecorder) EndResponse() {
-	releaseResponseRecorder(w)
+	// the recorder may be acquired by another request as soon as it's released,
+	// its underline ResponseWriter should be released first.
 	w.ResponseWriter.EndResponse()
+	releaseResponseRecorder(w)
 }
 
 // Write Adds the contents to the body reply, it writes the contents temporarily
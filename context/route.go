@@ -42,4 +42,47 @@ type RouteReadOnly interface {
 
 	// MainHandlerName returns the first registered handler for the route.
 	MainHandlerName() string
+
+	// Meta returns the route's informational metadata,
+	// i.e its description or the permissions which are required to access it.
+	Meta() RouteMeta
+}
+
+// RouteMeta is the informational metadata of a route,
+// it's not used by the router itself, it's there for other subsystems
+// like documentation generators, authorization and metrics middleware.
+type RouteMeta struct {
+	// Description is a short description of the route.
+	Description string `json:"description,omitempty"`
+	// Tags are used to group the routes, i.e "users".
+	Tags []string `json:"tags,omitempty"`
+	// Responses are example values of the route's responses per status code,
+	// i.e {200: User{}, 404: Problem{}}.
+	Responses map[int]interface{} `json:"responses,omitempty"`
+	// Permissions are the permissions which are required to access the route, i.e "users:read".
+	Permissions []string `json:"permissions,omitempty"`
+	// Values are any custom metadata.
+	Values map[string]interface{} `json:"values,omitempty"`
+}
+
+// HasTag reports whether the route's metadata contain the "tag".
+func (m RouteMeta) HasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasPermission reports whether the "permission" is required to access the route.
+func (m RouteMeta) HasPermission(permission string) bool {
+	for _, p := range m.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,407 @@
+package host
+
+import (
+	stdContext "context"
+	"errors"
+	"hash/fnv"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hidevopsio/iris/context"
+)
+
+// Upstream is a target of the `LoadBalancer`.
+type Upstream struct {
+	// URL is the scheme, host and base path of the upstream.
+	URL *url.URL
+
+	proxy *httputil.ReverseProxy
+
+	active       int64 // in-flight requests.
+	unhealthy    int32 // 1 when the active health check failed.
+	fails        int32 // consecutive passive failures.
+	ejectedUntil int64 // unix nano time, see `LoadBalancerConfig#EjectDuration`.
+}
+
+// Healthy reports whether the upstream can receive requests,
+// it's false when the latest health check failed or it's ejected because of consecutive errors.
+func (u *Upstream) Healthy() bool {
+	return atomic.LoadInt32(&u.unhealthy) == 0 &&
+		time.Now().UnixNano() >= atomic.LoadInt64(&u.ejectedUntil)
+}
+
+// ActiveRequests returns the number of the in-flight requests of this upstream.
+func (u *Upstream) ActiveRequests() int64 {
+	return atomic.LoadInt64(&u.active)
+}
+
+// Strategy selects the upstream of a request among the healthy ones.
+// The "upstreams" is never empty.
+type Strategy interface {
+	Next(r *http.Request, upstreams []*Upstream) *Upstream
+}
+
+// StrategyFunc is a function which completes the `Strategy` interface.
+type StrategyFunc func(r *http.Request, upstreams []*Upstream) *Upstream
+
+// Next calls itself.
+func (fn StrategyFunc) Next(r *http.Request, upstreams []*Upstream) *Upstream {
+	return fn(r, upstreams)
+}
+
+// RoundRobin returns a `Strategy` which selects the upstreams in turn.
+func RoundRobin() Strategy {
+	var n uint64
+	return StrategyFunc(func(r *http.Request, upstreams []*Upstream) *Upstream {
+		i := atomic.AddUint64(&n, 1) - 1
+		return upstreams[i%uint64(len(upstreams))]
+	})
+}
+
+// LeastConnections returns a `Strategy` which selects the upstream with the fewest in-flight requests.
+func LeastConnections() Strategy {
+	return StrategyFunc(func(r *http.Request, upstreams []*Upstream) *Upstream {
+		least := upstreams[0]
+		for _, u := range upstreams[1:] {
+			if u.ActiveRequests() < least.ActiveRequests() {
+				least = u
+			}
+		}
+		return least
+	})
+}
+
+// ConsistentHash returns a `Strategy` which selects the same upstream for the same "key",
+// i.e a session id, while that upstream is healthy. When an upstream is ejected only its keys are moved.
+// Requests with an empty key are balanced in round-robin.
+//
+// See `HashByHeader` and `HashByCookie` too.
+func ConsistentHash(key func(r *http.Request) string) Strategy {
+	fallback := RoundRobin()
+	return StrategyFunc(func(r *http.Request, upstreams []*Upstream) *Upstream {
+		k := key(r)
+		if k == "" {
+			return fallback.Next(r, upstreams)
+		}
+
+		// rendezvous hashing, the upstream with the highest score wins.
+		var (
+			selected *Upstream
+			max      uint64
+		)
+		for _, u := range upstreams {
+			h := fnv.New64a()
+			h.Write([]byte(k))
+			h.Write([]byte(u.URL.String()))
+			if score := h.Sum64(); selected == nil || score > max {
+				selected, max = u, score
+			}
+		}
+		return selected
+	})
+}
+
+// HashByHeader returns a `ConsistentHash` strategy based on a request header's value.
+func HashByHeader(name string) Strategy {
+	return ConsistentHash(func(r *http.Request) string {
+		return r.Header.Get(name)
+	})
+}
+
+// HashByCookie returns a `ConsistentHash` strategy based on a request cookie's value.
+func HashByCookie(name string) Strategy {
+	return ConsistentHash(func(r *http.Request) string {
+		if c, err := r.Cookie(name); err == nil {
+			return c.Value
+		}
+		return ""
+	})
+}
+
+// LoadBalancerConfig is the configuration of the `LoadBalancer`.
+type LoadBalancerConfig struct {
+	// Strategy selects the upstream of each request.
+	// Defaults to `RoundRobin`.
+	Strategy Strategy
+	// HealthCheckPath is the path which is requested on each upstream
+	// every "HealthCheckInterval", a response status code other than 2xx or 3xx marks it unhealthy
+	// until the next successful check.
+	// Defaults to empty, active health checks are disabled.
+	HealthCheckPath string
+	// HealthCheckInterval defaults to 10 seconds.
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout defaults to 2 seconds.
+	HealthCheckTimeout time.Duration
+	// MaxFails is the number of the consecutive connection errors or 502, 503 and 504 responses
+	// of an upstream that ejects it for the "EjectDuration".
+	// Defaults to 3, a negative value disables the passive ejection.
+	MaxFails int
+	// EjectDuration defaults to 30 seconds.
+	EjectDuration time.Duration
+	// Retries is the number of the retries on other upstreams when a connection error occurred,
+	// only requests with idempotent methods and without body are retried.
+	// Defaults to 0.
+	Retries int
+	// Transport is used to proxy the requests and to perform the health checks,
+	// defaults to the `ProxyHandler`'s one.
+	Transport http.RoundTripper
+}
+
+// LoadBalancer is a reverse proxy which balances the requests between more than one upstreams,
+// with active health checks and passive ejection of the failing upstreams.
+//
+// It's an `http.Handler`, use its `Handler` to register it on a Party
+// or the `NewLoadBalancerProxy` to run it as a standalone server.
+type LoadBalancer struct {
+	Upstreams []*Upstream
+	config    LoadBalancerConfig
+
+	closeOnce sync.Once
+	closeChan chan struct{}
+}
+
+type proxyAttemptKey struct{}
+
+// proxyAttempt keeps the error of the upstream's round trip, it's
+// stored to the request's std context in order to retry before any write.
+type proxyAttempt struct {
+	err error
+}
+
+// NewLoadBalancer returns a new `LoadBalancer` for the "targets",
+// the active health checks start immediately if configured, use `Close` to stop them.
+//
+// Usage:
+// lb := NewLoadBalancer(targets, LoadBalancerConfig{Strategy: LeastConnections(), HealthCheckPath: "/health"})
+// app.Any("/{p:path}", lb.Handler())
+func NewLoadBalancer(targets []*url.URL, config LoadBalancerConfig) *LoadBalancer {
+	if config.Strategy == nil {
+		config.Strategy = RoundRobin()
+	}
+
+	if config.HealthCheckInterval <= 0 {
+		config.HealthCheckInterval = 10 * time.Second
+	}
+
+	if config.HealthCheckTimeout <= 0 {
+		config.HealthCheckTimeout = 2 * time.Second
+	}
+
+	if config.MaxFails == 0 {
+		config.MaxFails = 3
+	}
+
+	if config.EjectDuration <= 0 {
+		config.EjectDuration = 30 * time.Second
+	}
+
+	lb := &LoadBalancer{
+		config:    config,
+		closeChan: make(chan struct{}),
+	}
+
+	for _, target := range targets {
+		u := &Upstream{URL: target}
+
+		proxy := ProxyHandler(target)
+		if config.Transport != nil {
+			proxy.Transport = config.Transport
+		}
+
+		proxy.ModifyResponse = func(resp *http.Response) error {
+			switch resp.StatusCode {
+			case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+				lb.fail(u)
+			default:
+				lb.succeed(u)
+			}
+			return nil
+		}
+
+		proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			if errors.Is(err, stdContext.Canceled) || r.Context().Err() != nil {
+				// the client went away, it's not a failure of the upstream
+				// and there is no reason to retry.
+				return
+			}
+
+			lb.fail(u)
+			if attempt, ok := r.Context().Value(proxyAttemptKey{}).(*proxyAttempt); ok {
+				attempt.err = err
+				return
+			}
+			w.WriteHeader(http.StatusBadGateway)
+		}
+
+		u.proxy = proxy
+		lb.Upstreams = append(lb.Upstreams, u)
+	}
+
+	if config.HealthCheckPath != "" {
+		go lb.healthCheck()
+	}
+
+	return lb
+}
+
+func (lb *LoadBalancer) fail(u *Upstream) {
+	if lb.config.MaxFails < 0 {
+		return
+	}
+
+	if fails := atomic.AddInt32(&u.fails, 1); int(fails) >= lb.config.MaxFails {
+		atomic.StoreInt64(&u.ejectedUntil, time.Now().Add(lb.config.EjectDuration).UnixNano())
+		atomic.StoreInt32(&u.fails, 0)
+	}
+}
+
+func (lb *LoadBalancer) succeed(u *Upstream) {
+	atomic.StoreInt32(&u.fails, 0)
+}
+
+// next returns the upstream of the request, the already tried upstreams are excluded.
+func (lb *LoadBalancer) next(r *http.Request, tried []*Upstream) *Upstream {
+	available := make([]*Upstream, 0, len(lb.Upstreams))
+	for _, u := range lb.Upstreams {
+		if !u.Healthy() || containsUpstream(tried, u) {
+			continue
+		}
+		available = append(available, u)
+	}
+
+	if len(available) == 0 {
+		return nil
+	}
+
+	return lb.config.Strategy.Next(r, available)
+}
+
+func containsUpstream(upstreams []*Upstream, u *Upstream) bool {
+	for _, upstream := range upstreams {
+		if upstream == u {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isRetryable(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return r.Body == nil || r.Body == http.NoBody
+	default:
+		return false
+	}
+}
+
+// ServeHTTP proxies the request to the upstream selected by the strategy,
+// it responds with 503 if there is no healthy upstream and 502 if all attempts failed.
+func (lb *LoadBalancer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	retries := 0
+	if isRetryable(r) {
+		retries = lb.config.Retries
+	}
+
+	var tried []*Upstream
+	for {
+		u := lb.next(r, tried)
+		if u == nil {
+			if len(tried) == 0 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			} else {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+			return
+		}
+
+		attempt := new(proxyAttempt)
+		atomic.AddInt64(&u.active, 1)
+		u.proxy.ServeHTTP(w, r.WithContext(stdContext.WithValue(r.Context(), proxyAttemptKey{}, attempt)))
+		atomic.AddInt64(&u.active, -1)
+
+		if attempt.err == nil {
+			return
+		}
+
+		tried = append(tried, u)
+		if len(tried) > retries {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+	}
+}
+
+// Handler returns the load balancer as a route's handler.
+func (lb *LoadBalancer) Handler() context.Handler {
+	return func(ctx context.Context) {
+		lb.ServeHTTP(ctx.ResponseWriter(), ctx.Request())
+	}
+}
+
+// Close stops the active health checks.
+func (lb *LoadBalancer) Close() {
+	lb.closeOnce.Do(func() {
+		close(lb.closeChan)
+	})
+}
+
+func (lb *LoadBalancer) healthCheck() {
+	ticker := time.NewTicker(lb.config.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, u := range lb.Upstreams {
+			atomic.StoreInt32(&u.unhealthy, lb.check(u))
+		}
+
+		select {
+		case <-lb.closeChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (lb *LoadBalancer) check(u *Upstream) int32 {
+	client := &http.Client{
+		Timeout:   lb.config.HealthCheckTimeout,
+		Transport: u.proxy.Transport, // same as the proxy's, i.e insecure for loopback.
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(singleJoiningSlash(u.URL.String(), lb.config.HealthCheckPath))
+	if err != nil {
+		return 1
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return 1
+	}
+
+	return 0
+}
+
+// NewLoadBalancerProxy returns a new host (server supervisor) which
+// balances all requests between the upstreams of the "lb",
+// its health checks are stopped on shutdown.
+//
+// Usage:
+// lb := NewLoadBalancer(targets, LoadBalancerConfig{Retries: 1})
+// proxy := NewLoadBalancerProxy("mydomain.com:80", lb)
+// proxy.ListenAndServe() // use of `proxy.Shutdown` to close the proxy server.
+func NewLoadBalancerProxy(hostAddr string, lb *LoadBalancer) *Supervisor {
+	proxy := New(&http.Server{
+		Addr:    hostAddr,
+		Handler: lb,
+	})
+	proxy.RegisterOnShutdown(lb.Close)
+	return proxy
+}
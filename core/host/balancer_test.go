@@ -0,0 +1,231 @@
+// black-box testing
+package host_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/core/host"
+	irishttptest "github.com/hidevopsio/iris/httptest"
+)
+
+func newUpstream(t *testing.T, body string, statusCode int) (*httptest.Server, *url.URL) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		w.Write([]byte(body))
+	}))
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return srv, u
+}
+
+func serveBalancer(lb http.Handler, r *http.Request) (int, string) {
+	rec := httptest.NewRecorder()
+	lb.ServeHTTP(rec, r)
+	body, _ := ioutil.ReadAll(rec.Body)
+	return rec.Code, string(body)
+}
+
+func TestLoadBalancerRoundRobin(t *testing.T) {
+	a, aURL := newUpstream(t, "a", http.StatusOK)
+	defer a.Close()
+	b, bURL := newUpstream(t, "b", http.StatusOK)
+	defer b.Close()
+
+	lb := host.NewLoadBalancer([]*url.URL{aURL, bURL}, host.LoadBalancerConfig{})
+	defer lb.Close()
+
+	for i, expected := range []string{"a", "b", "a", "b"} {
+		if _, got := serveBalancer(lb, httptest.NewRequest(http.MethodGet, "/", nil)); expected != got {
+			t.Fatalf("[%d] expected response from upstream: %s but got: %s", i, expected, got)
+		}
+	}
+}
+
+func TestLoadBalancerRetryAndEjection(t *testing.T) {
+	down, downURL := newUpstream(t, "down", http.StatusOK)
+	down.Close() // connection refused.
+	up, upURL := newUpstream(t, "up", http.StatusOK)
+	defer up.Close()
+
+	lb := host.NewLoadBalancer([]*url.URL{downURL, upURL}, host.LoadBalancerConfig{
+		Retries:       1,
+		MaxFails:      1,
+		EjectDuration: time.Minute,
+	})
+	defer lb.Close()
+
+	for i := 0; i < 3; i++ {
+		if code, body := serveBalancer(lb, httptest.NewRequest(http.MethodGet, "/", nil)); code != http.StatusOK || body != "up" {
+			t.Fatalf("[%d] expected the request to be retried on the healthy upstream but got: %d %s", i, code, body)
+		}
+	}
+
+	if lb.Upstreams[0].Healthy() {
+		t.Fatalf("expected the failing upstream to be ejected")
+	}
+
+	// POST is not retried.
+	lb = host.NewLoadBalancer([]*url.URL{downURL}, host.LoadBalancerConfig{Retries: 1, MaxFails: 1})
+	if code, _ := serveBalancer(lb, httptest.NewRequest(http.MethodPost, "/", nil)); code != http.StatusBadGateway {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusBadGateway, code)
+	}
+
+	// no healthy upstream.
+	if code, _ := serveBalancer(lb, httptest.NewRequest(http.MethodGet, "/", nil)); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusServiceUnavailable, code)
+	}
+}
+
+func TestLoadBalancerClientCanceled(t *testing.T) {
+	entered := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-r.Context().Done()
+	}))
+	defer slow.Close()
+	slowURL, _ := url.Parse(slow.URL)
+
+	var hits uint32
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddUint32(&hits, 1)
+	}))
+	defer other.Close()
+	otherURL, _ := url.Parse(other.URL)
+
+	lb := host.NewLoadBalancer([]*url.URL{slowURL, otherURL}, host.LoadBalancerConfig{Retries: 1, MaxFails: 1})
+	defer lb.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-entered
+		cancel()
+	}()
+
+	serveBalancer(lb, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+	if !lb.Upstreams[0].Healthy() {
+		t.Fatalf("expected the upstream to be healthy when the client cancels the request")
+	}
+
+	if got := atomic.LoadUint32(&hits); got != 0 {
+		t.Fatalf("expected the canceled request not to be retried but it was sent to the other upstream %d time(s)", got)
+	}
+}
+
+func TestLoadBalancerHealthCheck(t *testing.T) {
+	sick, sickURL := newUpstream(t, "sick", http.StatusServiceUnavailable)
+	defer sick.Close()
+	healthy, healthyURL := newUpstream(t, "healthy", http.StatusOK)
+	defer healthy.Close()
+
+	lb := host.NewLoadBalancer([]*url.URL{sickURL, healthyURL}, host.LoadBalancerConfig{
+		HealthCheckPath:     "/health",
+		HealthCheckInterval: 10 * time.Millisecond,
+		MaxFails:            -1,
+	})
+	defer lb.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for lb.Upstreams[0].Healthy() {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the upstream to be marked as unhealthy by the health check")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, body := serveBalancer(lb, httptest.NewRequest(http.MethodGet, "/", nil)); body != "healthy" {
+			t.Fatalf("[%d] expected response from the healthy upstream but got: %s", i, body)
+		}
+	}
+}
+
+func TestLoadBalancerLeastConnections(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(entered)
+		<-release
+		w.Write([]byte("slow"))
+	}))
+	defer slow.Close()
+	slowURL, _ := url.Parse(slow.URL)
+
+	fast, fastURL := newUpstream(t, "fast", http.StatusOK)
+	defer fast.Close()
+
+	lb := host.NewLoadBalancer([]*url.URL{slowURL, fastURL}, host.LoadBalancerConfig{Strategy: host.LeastConnections()})
+	defer lb.Close()
+
+	done := make(chan string)
+	go func() {
+		_, body := serveBalancer(lb, httptest.NewRequest(http.MethodGet, "/", nil))
+		done <- body
+	}()
+
+	<-entered
+	for i := 0; i < 2; i++ {
+		if _, body := serveBalancer(lb, httptest.NewRequest(http.MethodGet, "/", nil)); body != "fast" {
+			t.Fatalf("[%d] expected response from the least busy upstream but got: %s", i, body)
+		}
+	}
+
+	close(release)
+	if body := <-done; body != "slow" {
+		t.Fatalf("expected response from the slow upstream but got: %s", body)
+	}
+}
+
+func TestLoadBalancerConsistentHash(t *testing.T) {
+	var urls []*url.URL
+	for _, name := range []string{"a", "b", "c"} {
+		srv, u := newUpstream(t, name, http.StatusOK)
+		defer srv.Close()
+		urls = append(urls, u)
+	}
+
+	lb := host.NewLoadBalancer(urls, host.LoadBalancerConfig{Strategy: host.HashByHeader("X-User")})
+	defer lb.Close()
+
+	for _, user := range []string{"kataras", "makis", "gerasimos"} {
+		var first string
+		for i := 0; i < 5; i++ {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("X-User", user)
+			_, body := serveBalancer(lb, r)
+			if i == 0 {
+				first = body
+			} else if body != first {
+				t.Fatalf("expected requests of user %s to be served by the same upstream: %s but got: %s", user, first, body)
+			}
+		}
+	}
+}
+
+func TestLoadBalancerHandler(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("upstream " + r.URL.Path))
+	}))
+	defer upstream.Close()
+	u, _ := url.Parse(upstream.URL)
+
+	lb := host.NewLoadBalancer([]*url.URL{u}, host.LoadBalancerConfig{})
+	defer lb.Close()
+
+	app := iris.New()
+	app.Party("/api").Any("/{p:path}", lb.Handler())
+
+	e := irishttptest.New(t, app)
+	e.GET("/api/users").Expect().Status(iris.StatusOK).Body().Equal("upstream /api/users")
+}
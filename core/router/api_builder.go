@@ -5,6 +5,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/hidevopsio/iris/context"
@@ -36,10 +37,14 @@ var (
 // repository passed to all parties(subrouters), it's the object witch keeps
 // all the routes.
 type repository struct {
+	mu     sync.RWMutex // routes can be registered and removed at serve-time too, see `RefreshRouter`.
 	routes []*Route
 }
 
 func (r *repository) register(route *Route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	for _, r := range r.routes {
 		if r.String() == route.String() {
 			return // do not register any duplicates, the sooner the better.
@@ -49,7 +54,24 @@ func (r *repository) register(route *Route) {
 	r.routes = append(r.routes, route)
 }
 
+func (r *repository) remove(routeName string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, route := range r.routes {
+		if route.Name == routeName {
+			r.routes = append(r.routes[:i:i], r.routes[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
 func (r *repository) get(routeName string) *Route {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
 	for _, r := range r.routes {
 		if r.Name == routeName {
 			return r
@@ -58,8 +80,14 @@ func (r *repository) get(routeName string) *Route {
 	return nil
 }
 
+// getAll returns a copy of the registered routes,
+// the request handler may sort them while new routes are being registered.
 func (r *repository) getAll() []*Route {
-	return r.routes
+	r.mu.RLock()
+	routes := make([]*Route, len(r.routes))
+	copy(routes, r.routes)
+	r.mu.RUnlock()
+	return routes
 }
 
 // APIBuilder the visible API for constructing the router
@@ -105,6 +133,12 @@ type APIBuilder struct {
 
 	// the per-party (and its children) execution rules for begin, main and done handlers.
 	handlerExecutionRules ExecutionRules
+	// the per-party (and its children) routes' maximum execution time and the status code
+	// that is fired when exceeded, see `Timeout`.
+	timeout           time.Duration
+	timeoutStatusCode int
+	// the per-party (and its children) path policy, see `SetPathPolicy`.
+	pathPolicy PathPolicy
 }
 
 var _ Party = (*APIBuilder)(nil)
@@ -181,6 +215,34 @@ func (api *APIBuilder) SetExecutionRules(executionRules ExecutionRules) Party {
 	return api
 }
 
+// Timeout sets a maximum execution time for the handlers of the future routes
+// of this Party and its children.
+// The request's std context, `ctx.Request().Context()`, is canceled when the "timeout" is exceeded
+// and the "statusCode" error (503 if zero) is fired instead of the recorded response,
+// so it can be customized through the `OnErrorCode`.
+// A zero "timeout" disables it.
+//
+// See `context#Timeout` and `Route#Timeout` for more.
+//
+// Returns this Party.
+func (api *APIBuilder) Timeout(timeout time.Duration, statusCode int) Party {
+	api.timeout = timeout
+	api.timeoutStatusCode = statusCode
+	return api
+}
+
+// SetPathPolicy sets the way the request paths are matched against the future routes
+// of this Party and its children, i.e case-insensitive matching
+// and strict, lenient or redirect trailing slash handling.
+//
+// See `PathPolicy` and `Route#PathPolicy` for more.
+//
+// Returns this Party.
+func (api *APIBuilder) SetPathPolicy(policy PathPolicy) Party {
+	api.pathPolicy = policy
+	return api
+}
+
 // Handle registers a route to the server's api.
 // if empty method is passed then handler(s) are being registered to all methods, same as .Any.
 //
@@ -251,6 +313,10 @@ func (api *APIBuilder) Handle(method string, relativePath string, handlers ...co
 			return nil // fail on first error.
 		}
 
+		route.Timeout = api.timeout
+		route.TimeoutStatusCode = api.timeoutStatusCode
+		route.PathPolicy = api.pathPolicy
+
 		// Add UseGlobal & DoneGlobal Handlers
 		route.use(api.beginGlobalHandlers)
 		route.done(api.doneGlobalHandlers)
@@ -353,6 +419,9 @@ func (api *APIBuilder) Party(relativePath string, handlers ...context.Handler) P
 		relativePath:          fullpath,
 		allowMethods:          allowMethods,
 		handlerExecutionRules: api.handlerExecutionRules,
+		timeout:               api.timeout,
+		timeoutStatusCode:     api.timeoutStatusCode,
+		pathPolicy:            api.pathPolicy,
 	}
 }
 
@@ -436,6 +505,16 @@ func (api *APIBuilder) GetRoute(routeName string) *Route {
 	return api.routes.get(routeName)
 }
 
+// RemoveRoute unregisters a route based on its name,
+// it returns false if the route wasn't registered.
+// A call of `RefreshRouter` is required after this type of change in order to change to be really applied,
+// in-flight requests of the removed route are completed normally.
+//
+// To replace a route at serve-time, remove it and register the new one before the `RefreshRouter`.
+func (api *APIBuilder) RemoveRoute(routeName string) bool {
+	return api.routes.remove(routeName)
+}
+
 // GetRouteReadOnly returns the registered "read-only" route based on its name, otherwise nil.
 // One note: "routeName" should be case-sensitive. Used by the context to get the current route.
 // It returns an interface instead to reduce wrong usage and to keep the decoupled design between
@@ -808,6 +887,17 @@ func (api *APIBuilder) Favicon(favPath string, requestPath ...string) *Route {
 //
 // Returns the GET *Route.
 func (api *APIBuilder) StaticWeb(requestPath string, systemPath string) *Route {
+	return api.StaticFS(requestPath, http.Dir(Abs(systemPath)))
+}
+
+// StaticFS same as `StaticWeb` but it serves the files of the "fs" file system,
+// i.e an `http.FS(embedFS)`, a `context.BindataFS` or a `context.OverlayFS`
+// which can shadow embedded files with local ones.
+//
+//	api.StaticFS("/static", context.OverlayFS(http.Dir("./overrides"), http.FS(assets)))
+//
+// Returns the GET *Route.
+func (api *APIBuilder) StaticFS(requestPath string, fs http.FileSystem) *Route {
 	fullpath := joinPath(api.relativePath, requestPath)
 
 	// if subdomain,
@@ -819,7 +909,7 @@ func (api *APIBuilder) StaticWeb(requestPath string, systemPath string) *Route {
 	paramName := "file"
 	requestPath = joinPath(requestPath, WildcardParam(paramName))
 
-	h := NewStaticHandlerBuilder(systemPath).Listing(false).Build()
+	h := NewStaticFSHandlerBuilder(fs).Listing(false).Build()
 
 	if fullpath != "/" {
 		h = StripPrefix(fullpath, h)
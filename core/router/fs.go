@@ -24,94 +24,48 @@ import (
 // StaticEmbeddedHandler returns a Handler which can serve embedded files
 // that are embedded using the go-bindata tool(assetsGziped = false) or the kataras/bindata tool (assetsGziped = true).
 //
+// It serves the "vdir" directory of the `context.BindataFS(assetFn, namesFn)` file system,
+// see `StaticFSHandler` too.
+//
 // Examples: https://github.com/hidevopsio/iris/tree/master/_examples/file-server
 func StaticEmbeddedHandler(vdir string, assetFn func(name string) ([]byte, error), namesFn func() []string, assetsGziped bool) context.Handler {
-	// Depends on the command the user gave to the go-bindata
-	// the assset path (names) may be or may not be prepended with a slash.
-	// What we do: we remove the ./ from the vdir which should be
-	// the same with the asset path (names).
-	// we don't pathclean, because that will prepend a slash
-	//					   go-bindata should give a correct path format.
-	// On serve time we check the "paramName" (which is the path after the "requestPath")
-	// so it has the first directory part missing, we use the "vdir" to complete it
-	// and match with the asset path (names).
-	if len(vdir) > 0 {
-		if vdir[0] == '.' {
-			vdir = vdir[1:]
-		}
-		if vdir[0] == '/' || vdir[0] == os.PathSeparator { // second check for /something, (or ./something if we had dot on 0 it will be removed
-			vdir = vdir[1:]
-		}
-
-		// check for trailing slashes because new users may be do that by mistake
-		// although all examples are showing the correct way but you never know
-		// i.e "./assets/" is not correct, if was inside "./assets".
-		// remove last "/".
-		if trailingSlashIdx := len(vdir) - 1; vdir[trailingSlashIdx] == '/' {
-			vdir = vdir[0:trailingSlashIdx]
-		}
+	fs := context.SubFS(context.BindataFS(assetFn, namesFn), vdir)
+	h := NewStaticFSHandlerBuilder(fs).Listing(false).Build()
+	if !assetsGziped {
+		return h
 	}
 
-	// collect the names we are care for,
-	// because not all Asset used here, we need the vdir's assets.
-	allNames := namesFn()
-
-	var names []string
-	for _, path := range allNames {
-		// i.e: path = public/css/main.css
-
-		// check if path is the path name we care for
-		if !strings.HasPrefix(path, vdir) {
-			continue
+	return func(ctx context.Context) {
+		if isFile(fs, path.Clean("/"+ctx.Request().URL.Path)) {
+			// this will add the "Vary" : "Accept-Encoding"
+			// and 					"Content-Encoding": "gzip"
+			// headers, the files are already gziped.
+			context.AddGzipHeaders(ctx.ResponseWriter())
 		}
 
-		names = append(names, path)
+		h(ctx)
 	}
+}
 
-	// modtime := time.Now()
-	h := func(ctx context.Context) {
-
-		reqPath := strings.TrimPrefix(ctx.Request().URL.Path, "/"+vdir)
-		// i.e : /css/main.css
-
-		for _, path := range names {
-			// in order to map "/" as "/index.html"
-			if path == "/index.html" && reqPath == "/" {
-				reqPath = "/index.html"
-			}
-
-			if path != vdir+reqPath {
-				continue
-			}
-
-			cType := TypeByFilename(path)
-
-			buf, err := assetFn(path) // remove the first slash
-
-			if assetsGziped {
-				// this will add the "Vary" : "Accept-Encoding"
-				// and 					"Content-Encoding": "gzip"
-				// headers.
-				context.AddGzipHeaders(ctx.ResponseWriter())
-			}
-
-			if err != nil {
-				continue
-			}
+// isFile reports whether the "name" is a file of the "fs"
+// or a directory with an index.html file.
+func isFile(fs http.FileSystem, name string) bool {
+	f, err := fs.Open(name)
+	if err != nil {
+		return false
+	}
 
-			ctx.ContentType(cType)
-			if _, err := ctx.Write(buf); err != nil {
-				ctx.StatusCode(http.StatusInternalServerError)
-				ctx.StopExecution()
-			}
-			return
-		}
+	info, err := f.Stat()
+	f.Close()
+	if err != nil {
+		return false
+	}
 
-		// not found or error
-		ctx.NotFound()
+	if info.IsDir() {
+		return isFile(fs, path.Join(name, "index.html"))
 	}
 
-	return h
+	return true
 }
 
 // StaticHandler returns a new Handler which is ready
@@ -135,6 +89,19 @@ func StaticHandler(systemPath string, showList bool, gzip bool) context.Handler
 		Build()
 }
 
+// StaticFSHandler returns a new Handler which is ready
+// to serve all kind of static files of the "fs" file system,
+// i.e an `http.Dir`, an `http.FS(embedFS)`, a `context.BindataFS` or a `context.OverlayFS`.
+//
+// Developers can wrap this handler using the `router.StripPrefix`
+// for a fixed static path when the result handler is being, finally, registered to a route.
+func StaticFSHandler(fs http.FileSystem, showList bool, gzip bool) context.Handler {
+	return NewStaticFSHandlerBuilder(fs).
+		Gzip(gzip).
+		Listing(showList).
+		Build()
+}
+
 // StaticHandlerBuilder is the web file system's Handler builder
 // use that or the iris.StaticHandler/StaticWeb methods.
 type StaticHandlerBuilder interface {
@@ -150,15 +117,14 @@ type StaticHandlerBuilder interface {
 //  +------------------------------------------------------------+
 
 type fsHandler struct {
-	// user options, only directory is required.
-	directory       http.Dir
+	// user options, only the file system is required.
+	filesystem      http.FileSystem
 	listDirectories bool
 	gzip            bool
 	// these are init on the Build() call
-	filesystem http.FileSystem
-	once       sync.Once
-	handler    context.Handler
-	begin      context.Handlers
+	once    sync.Once
+	handler context.Handler
+	begin   context.Handlers
 }
 
 func toWebPath(systemPath string) string {
@@ -190,8 +156,14 @@ func Abs(path string) string {
 // this builder is used by people who have more complicated application
 // structure and want a fluent api to work on.
 func NewStaticHandlerBuilder(dir string) StaticHandlerBuilder {
+	return NewStaticFSHandlerBuilder(http.Dir(Abs(dir)))
+}
+
+// NewStaticFSHandlerBuilder same as `NewStaticHandlerBuilder`
+// but it serves the files of the "fs" file system instead of a system directory.
+func NewStaticFSHandlerBuilder(fs http.FileSystem) StaticHandlerBuilder {
 	return &fsHandler{
-		directory: http.Dir(Abs(dir)),
+		filesystem: fs,
 		// list directories disabled by default
 		listDirectories: false,
 	}
@@ -245,8 +217,6 @@ func (w *fsHandler) Build() context.Handler {
 	// we have to ensure that Build is called ONLY one time,
 	// one instance per one static directory.
 	w.once.Do(func() {
-		w.filesystem = w.directory
-
 		fileserver := func(ctx context.Context) {
 			upath := ctx.Request().URL.Path
 			if !strings.HasPrefix(upath, "/") {
@@ -426,7 +396,7 @@ func detectOrWriteContentType(ctx context.Context, name string, content io.ReadS
 // The sizeFunc is called at most once. Its error, if any, is sent in the HTTP response.
 func serveContent(ctx context.Context, name string, modtime time.Time, sizeFunc func() (int64, error), content io.ReadSeeker) (string, int) /* we could use the TransactionErrResult but prefer not to create new objects for each of the errors on static file handlers*/ {
 	ctx.SetLastModified(modtime)
-	done, rangeReq := checkPreconditions(ctx, modtime)
+	done, rangeReq := context.CheckPreconditions(ctx, modtime)
 	if done {
 		return "", http.StatusNotModified
 	}
@@ -529,208 +499,6 @@ func serveContent(ctx context.Context, name string, modtime time.Time, sizeFunc
 	return "", code
 }
 
-func etagEmptyOrStrongMatch(rangeValue string, etagValue string) bool {
-	etag, _ := scanETag(rangeValue)
-	if etag != "" {
-		if etagStrongMatch(etag, etagValue) {
-			return true
-		}
-		return false
-	}
-	return true
-}
-
-// scanETag determines if a syntactically valid ETag is present at s. If so,
-// the ETag and remaining text after consuming ETag is returned. Otherwise,
-// it returns "", "".
-func scanETag(s string) (etag string, remain string) {
-	s = textproto.TrimString(s)
-	start := 0
-	if strings.HasPrefix(s, "W/") {
-		start = 2
-	}
-	if len(s[start:]) < 2 || s[start] != '"' {
-		return "", ""
-	}
-	// ETag is either W/"text" or "text".
-	// See RFC 7232 2.3.
-	for i := start + 1; i < len(s); i++ {
-		c := s[i]
-		switch {
-		// Character values allowed in ETags.
-		case c == 0x21 || c >= 0x23 && c <= 0x7E || c >= 0x80:
-		case c == '"':
-			return string(s[:i+1]), s[i+1:]
-		default:
-			break
-		}
-	}
-	return "", ""
-}
-
-// etagStrongMatch reports whether a and b match using strong ETag comparison.
-// Assumes a and b are valid ETags.
-func etagStrongMatch(a, b string) bool {
-	return a == b && a != "" && a[0] == '"'
-}
-
-// etagWeakMatch reports whether a and b match using weak ETag comparison.
-// Assumes a and b are valid ETags.
-func etagWeakMatch(a, b string) bool {
-	return strings.TrimPrefix(a, "W/") == strings.TrimPrefix(b, "W/")
-}
-
-// condResult is the result of an HTTP request precondition check.
-// See https://tools.ietf.org/html/rfc7232 section 3.
-type condResult int
-
-const (
-	condNone condResult = iota
-	condTrue
-	condFalse
-)
-
-func checkIfMatch(ctx context.Context) condResult {
-	im := ctx.GetHeader("If-Match")
-	if im == "" {
-		return condNone
-	}
-	for {
-		im = textproto.TrimString(im)
-		if len(im) == 0 {
-			break
-		}
-		if im[0] == ',' {
-			im = im[1:]
-			continue
-		}
-		if im[0] == '*' {
-			return condTrue
-		}
-		etag, remain := scanETag(im)
-		if etag == "" {
-			break
-		}
-		if etagStrongMatch(etag, ctx.ResponseWriter().Header().Get("Etag")) {
-			return condTrue
-		}
-		im = remain
-	}
-
-	return condFalse
-}
-
-func checkIfNoneMatch(ctx context.Context) condResult {
-	inm := ctx.GetHeader("If-None-Match")
-	if inm == "" {
-		return condNone
-	}
-	buf := inm
-	for {
-		buf = textproto.TrimString(buf)
-		if len(buf) == 0 {
-			break
-		}
-		if buf[0] == ',' {
-			buf = buf[1:]
-		}
-		if buf[0] == '*' {
-			return condFalse
-		}
-		etag, remain := scanETag(buf)
-		if etag == "" {
-			break
-		}
-		if etagWeakMatch(etag, ctx.ResponseWriter().Header().Get("Etag")) {
-			return condFalse
-		}
-		buf = remain
-	}
-	return condTrue
-}
-
-// checkPreconditions evaluates request preconditions and reports whether a precondition
-// resulted in sending StatusNotModified or StatusPreconditionFailed.
-func checkPreconditions(ctx context.Context, modtime time.Time) (done bool, rangeHeader string) {
-	// This function carefully follows RFC 7232 section 6.
-	ch := checkIfMatch(ctx)
-	if ch == condNone {
-		ch = checkIfUnmodifiedSince(ctx, modtime)
-	}
-	if ch == condFalse {
-
-		ctx.StatusCode(http.StatusPreconditionFailed)
-		return true, ""
-	}
-	switch checkIfNoneMatch(ctx) {
-	case condFalse:
-		if ctx.Method() == http.MethodGet || ctx.Method() == http.MethodHead {
-			ctx.WriteNotModified()
-			return true, ""
-		}
-		ctx.StatusCode(http.StatusPreconditionFailed)
-		return true, ""
-
-	case condNone:
-		if modified, err := ctx.CheckIfModifiedSince(modtime); !modified && err == nil {
-			ctx.WriteNotModified()
-			return true, ""
-		}
-	}
-
-	rangeHeader = ctx.GetHeader("Range")
-	if rangeHeader != "" {
-		if checkIfRange(ctx, etagEmptyOrStrongMatch, modtime) == condFalse {
-			rangeHeader = ""
-		}
-	}
-	return false, rangeHeader
-}
-
-func checkIfUnmodifiedSince(ctx context.Context, modtime time.Time) condResult {
-	ius := ctx.GetHeader("If-Unmodified-Since")
-	if ius == "" || context.IsZeroTime(modtime) {
-		return condNone
-	}
-	if t, err := context.ParseTime(ctx, ius); err == nil {
-		// The Date-Modified header truncates sub-second precision, so
-		// use mtime < t+1s instead of mtime <= t to check for unmodified.
-		if modtime.Before(t.Add(1 * time.Second)) {
-			return condTrue
-		}
-		return condFalse
-	}
-	return condNone
-}
-
-func checkIfRange(ctx context.Context, etagEmptyOrStrongMatch func(ifRangeValue string, etagValue string) bool, modtime time.Time) condResult {
-	if ctx.Method() != http.MethodGet {
-		return condNone
-	}
-	ir := ctx.GetHeader("If-Range")
-	if ir == "" {
-		return condNone
-	}
-
-	if etagEmptyOrStrongMatch(ir, ctx.GetHeader("Etag")) {
-		return condTrue
-	}
-
-	// The If-Range value is typically the ETag value, but it may also be
-	// the modtime date. See golang.org/issue/8367.
-	if modtime.IsZero() {
-		return condFalse
-	}
-	t, err := context.ParseTime(ctx, ir)
-	if err != nil {
-		return condFalse
-	}
-	if t.Unix() == modtime.Unix() {
-		return condTrue
-	}
-	return condFalse
-}
-
 // name is '/'-separated, not filepath.Separator.
 func serveFile(ctx context.Context, fs http.FileSystem, name string, redirect bool, showList bool, gzip bool) (string, int) {
 	const indexPage = "/index.html"
@@ -771,8 +539,10 @@ func serveFile(ctx context.Context, fs http.FileSystem, name string, redirect bo
 		}
 	}
 
-	// redirect if the directory name doesn't end in a slash
-	if d.IsDir() {
+	// redirect if the directory name doesn't end in a slash,
+	// unless the router removes the trailing slash of the current route's paths,
+	// the directory is served without it then, see `PathPolicy`.
+	if d.IsDir() && keepsTrailingSlash(ctx) {
 		url := ctx.Request().URL.Path
 		if url[len(url)-1] != '/' {
 			localRedirect(ctx, path.Base(url)+"/")
@@ -856,6 +626,20 @@ func toHTTPError(err error) (msg string, httpStatus int) {
 	return "500 Internal Server Error", http.StatusInternalServerError
 }
 
+// keepsTrailingSlash reports whether the request path reaches the handler with its trailing slash,
+// that's true when the path correction is disabled or the current route's trailing slash mode is strict.
+func keepsTrailingSlash(ctx context.Context) bool {
+	if ctx.Application().ConfigurationReadOnly().GetDisablePathCorrection() {
+		return true
+	}
+
+	if r, ok := ctx.GetCurrentRoute().(routeReadOnlyWrapper); ok {
+		return r.PathPolicy.TrailingSlash == TrailingSlashStrict
+	}
+
+	return true
+}
+
 // localRedirect gives a Moved Permanently response.
 // It does not convert relative paths to absolute paths like Redirect does.
 func localRedirect(ctx context.Context, newPath string) {
@@ -0,0 +1,101 @@
+package router_test
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/context"
+	"github.com/hidevopsio/iris/httptest"
+)
+
+func TestStaticFS(t *testing.T) {
+	assets := map[string]string{
+		"assets/index.html":  "<h1>embedded</h1>",
+		"assets/css/app.css": "body {}",
+		"assets/js/app.js":   "embedded();",
+		"other/secret.txt":   "secret",
+	}
+
+	assetFn := func(name string) ([]byte, error) {
+		if contents, ok := assets[name]; ok {
+			return []byte(contents), nil
+		}
+		return nil, os.ErrNotExist
+	}
+	namesFn := func() []string {
+		names := make([]string, 0, len(assets))
+		for name := range assets {
+			names = append(names, name)
+		}
+		return names
+	}
+
+	embedded := context.SubFS(context.BindataFS(assetFn, namesFn), "./assets")
+	overrides := http.FS(fstest.MapFS{
+		"js/app.js":  {Data: []byte("override();")},
+		"js/new.js":  {Data: []byte("new();")},
+		"index.html": {Data: []byte("<h1>override</h1>")},
+	})
+
+	app := iris.New()
+	app.StaticFS("/embedded", embedded)
+	app.StaticFS("/static", context.OverlayFS(overrides, embedded))
+	app.StaticEmbedded("/bindata", "./assets", assetFn, namesFn)
+
+	e := httptest.New(t, app)
+
+	e.GET("/embedded/css/app.css").Expect().Status(httptest.StatusOK).
+		ContentType("text/css").Body().Equal("body {}")
+	e.GET("/embedded/").Expect().Status(httptest.StatusOK).Body().Equal("<h1>embedded</h1>")
+	e.GET("/embedded/js/").Expect().Status(httptest.StatusForbidden)
+	e.GET("/embedded/secret.txt").Expect().Status(httptest.StatusNotFound)
+	e.GET("/embedded/../other/secret.txt").Expect().Status(httptest.StatusNotFound)
+
+	// local files shadow the embedded ones.
+	e.GET("/static/js/app.js").Expect().Status(httptest.StatusOK).Body().Equal("override();")
+	e.GET("/static/js/new.js").Expect().Status(httptest.StatusOK).Body().Equal("new();")
+	e.GET("/static/css/app.css").Expect().Status(httptest.StatusOK).Body().Equal("body {}")
+	e.GET("/static/").Expect().Status(httptest.StatusOK).Body().Equal("<h1>override</h1>")
+
+	e.GET("/bindata/js/app.js").Expect().Status(httptest.StatusOK).Body().Equal("embedded();")
+	e.GET("/bindata/missing.js").Expect().Status(httptest.StatusNotFound)
+}
+
+func TestOverlayFSReaddir(t *testing.T) {
+	fs := context.OverlayFS(
+		http.FS(fstest.MapFS{"a.txt": {Data: []byte("a1")}, "dir/c.txt": {}}),
+		http.FS(fstest.MapFS{"a.txt": {Data: []byte("a2")}, "b.txt": {}, "dir/d.txt": {}}),
+	)
+
+	var files []string
+	err := context.WalkFS(fs, "/", func(path string, info os.FileInfo) error {
+		if !info.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"/a.txt", "/b.txt", "/dir/c.txt", "/dir/d.txt"}
+	if len(files) != len(expected) {
+		t.Fatalf("expected files %v but got %v", expected, files)
+	}
+	for i := range expected {
+		if files[i] != expected[i] {
+			t.Fatalf("expected files %v but got %v", expected, files)
+		}
+	}
+
+	b, err := context.ReadFile(fs, "/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "a1" {
+		t.Fatalf("expected the first file system's file but got '%s'", b)
+	}
+}
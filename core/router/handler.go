@@ -60,7 +60,12 @@ func (h *routerHandler) addRoute(r *Route) error {
 		h.trees = append(h.trees, t)
 	}
 
-	t.insert(path, routeName, handlers)
+	t.insert(path, routeName, handlers, r.PathPolicy)
+	// register the same route without its trailing optional parameters too,
+	// their default values (if any) are set by the macro handler.
+	for _, optionalPath := range r.optionalPaths() {
+		t.insert(optionalPath, routeName, handlers, r.PathPolicy)
+	}
 	return nil
 }
 
@@ -142,42 +147,6 @@ func (h *routerHandler) Build(provider RoutesProvider) error {
 func (h *routerHandler) HandleRequest(ctx context.Context) {
 	method := ctx.Method()
 	path := ctx.Path()
-	if !ctx.Application().ConfigurationReadOnly().GetDisablePathCorrection() {
-
-		if len(path) > 1 && strings.HasSuffix(path, "/") {
-			// Remove trailing slash and client-permanent rule for redirection,
-			// if confgiuration allows that and path has an extra slash.
-
-			// update the new path and redirect.
-			r := ctx.Request()
-			// use Trim to ensure there is no open redirect due to two leading slashes
-			path = "/" + strings.Trim(path, "/")
-			r.URL.Path = path
-			url := r.URL.String()
-
-			// Fixes https://github.com/hidevopsio/iris/issues/921
-			// This is caused for security reasons, imagine a payment shop,
-			// you can't just permantly redirect a POST request, so just 307 (RFC 7231, 6.4.7).
-			if method == http.MethodPost || method == http.MethodPut {
-				ctx.Redirect(url, http.StatusTemporaryRedirect)
-				return
-			}
-
-			ctx.Redirect(url, http.StatusMovedPermanently)
-
-			// RFC2616 recommends that a short note "SHOULD" be included in the
-			// response because older user agents may not understand 301/307.
-			// Shouldn't send the response for POST or HEAD; that leaves GET.
-			if method == http.MethodGet {
-				note := "<a href=\"" +
-					html.EscapeString(url) +
-					"\">Moved Permanently</a>.\n"
-
-				ctx.ResponseWriter().WriteString(note)
-			}
-			return
-		}
-	}
 
 	for i := range h.trees {
 		t := h.trees[i]
@@ -185,39 +154,21 @@ func (h *routerHandler) HandleRequest(ctx context.Context) {
 			continue
 		}
 
-		if h.hosts && t.subdomain != "" {
-			requestHost := ctx.Host()
-			if netutil.IsLoopbackSubdomain(requestHost) {
-				// this fixes a bug when listening on
-				// 127.0.0.1:8080 for example
-				// and have a wildcard subdomain and a route registered to root domain.
-				continue // it's not a subdomain, it's something like 127.0.0.1 probably
-			}
-			// it's a dynamic wildcard subdomain, we have just to check if ctx.subdomain is not empty
-			if t.subdomain == SubdomainWildcardIndicator {
-				// mydomain.com -> invalid
-				// localhost -> invalid
-				// sub.mydomain.com -> valid
-				// sub.localhost -> valid
-				serverHost := ctx.Application().ConfigurationReadOnly().GetVHost()
-				if serverHost == requestHost {
-					continue // it's not a subdomain, it's a full domain (with .com...)
-				}
+		if !h.matchHost(ctx, t) {
+			continue
+		}
 
-				dotIdx := strings.IndexByte(requestHost, '.')
-				slashIdx := strings.IndexByte(requestHost, '/')
-				if dotIdx > 0 && (slashIdx == -1 || slashIdx > dotIdx) {
-					// if "." was found anywhere but not at the first path segment (host).
-				} else {
-					continue
+		n, correctedPath, redirect := h.find(ctx, t, path)
+		if n != nil {
+			if correctedPath != "" {
+				if redirect {
+					redirectToCorrectedPath(ctx, correctedPath)
+					return
 				}
-				// continue to that, any subdomain is valid.
-			} else if !strings.HasPrefix(requestHost, t.subdomain) { // t.subdomain contains the dot.
-				continue
+				// let the handlers, i.e the static file server, see the same path as the router.
+				ctx.Request().URL.Path = correctedPath
 			}
-		}
-		n := t.search(path, ctx.Params())
-		if n != nil {
+
 			ctx.SetCurrentRouteName(n.RouteName)
 			ctx.Do(n.Handlers)
 			// found
@@ -245,44 +196,125 @@ func (h *routerHandler) HandleRequest(ctx context.Context) {
 	ctx.StatusCode(http.StatusNotFound)
 }
 
-func (h *routerHandler) subdomainAndPathAndMethodExists(ctx context.Context, t *trie, method, path string) bool {
-	if method != "" && method != t.method {
-		return false
+// find returns the node of the request path based on the matched route's `PathPolicy`.
+// If the path should be corrected, i.e because of a trailing slash, then the corrected path
+// is returned too, with "redirect" set to true if the client should be redirected to it.
+func (h *routerHandler) find(ctx context.Context, t *trie, path string) (n *trieNode, correctedPath string, redirect bool) {
+	params := ctx.Params()
+
+	if len(path) > 1 && !ctx.Application().ConfigurationReadOnly().GetDisablePathCorrection() {
+		hasTrailingSlash := path[len(path)-1] == '/'
+		hasDuplicateSlashes := strings.Contains(path, "//")
+
+		if hasTrailingSlash || hasDuplicateSlashes {
+			cleanPath := cleanSlashes(path)
+			if n = t.search(cleanPath, params); n != nil {
+				policy := n.policy
+				if policy.TrailingSlash != TrailingSlashStrict && !(hasDuplicateSlashes && policy.KeepDuplicateSlashes) {
+					redirect = policy.TrailingSlash == TrailingSlashRedirect
+					// the case of the path is corrected on canonical redirect or
+					// when it's served, the handlers should see the registered path.
+					if policy.CaseInsensitive && (policy.CanonicalRedirect || !redirect) {
+						cleanPath = canonicalPath(n.key, cleanPath)
+						redirect = redirect || policy.CanonicalRedirect
+					}
+
+					return n, cleanPath, redirect
+				}
+			}
+
+			params.Reset()
+		}
 	}
 
-	if h.hosts && t.subdomain != "" {
-		requestHost := ctx.Host()
-		if netutil.IsLoopbackSubdomain(requestHost) {
-			// this fixes a bug when listening on
-			// 127.0.0.1:8080 for example
-			// and have a wildcard subdomain and a route registered to root domain.
-			return false // it's not a subdomain, it's something like 127.0.0.1 probably
+	if n = t.search(path, params); n != nil && n.policy.CaseInsensitive {
+		if canonical := canonicalPath(n.key, path); canonical != path {
+			return n, canonical, n.policy.CanonicalRedirect
 		}
-		// it's a dynamic wildcard subdomain, we have just to check if ctx.subdomain is not empty
-		if t.subdomain == SubdomainWildcardIndicator {
-			// mydomain.com -> invalid
-			// localhost -> invalid
-			// sub.mydomain.com -> valid
-			// sub.localhost -> valid
-			serverHost := ctx.Application().ConfigurationReadOnly().GetVHost()
-			if serverHost == requestHost {
-				return false // it's not a subdomain, it's a full domain (with .com...)
-			}
+	}
 
-			dotIdx := strings.IndexByte(requestHost, '.')
-			slashIdx := strings.IndexByte(requestHost, '/')
-			if dotIdx > 0 && (slashIdx == -1 || slashIdx > dotIdx) {
-				// if "." was found anywhere but not at the first path segment (host).
-			} else {
-				return false
-			}
-			// continue to that, any subdomain is valid.
-		} else if !strings.HasPrefix(requestHost, t.subdomain) { // t.subdomain contains the dot.
+	return n, "", false
+}
+
+func redirectToCorrectedPath(ctx context.Context, path string) {
+	method := ctx.Method()
+	// update the new path and redirect.
+	r := ctx.Request()
+	// use Trim to ensure there is no open redirect due to two leading slashes
+	r.URL.Path = "/" + strings.Trim(path, "/")
+	url := r.URL.String()
+
+	// Fixes https://github.com/hidevopsio/iris/issues/921
+	// This is caused for security reasons, imagine a payment shop,
+	// you can't just permantly redirect a POST request, so just 307 (RFC 7231, 6.4.7).
+	if method == http.MethodPost || method == http.MethodPut {
+		ctx.Redirect(url, http.StatusTemporaryRedirect)
+		return
+	}
+
+	ctx.Redirect(url, http.StatusMovedPermanently)
+
+	// RFC2616 recommends that a short note "SHOULD" be included in the
+	// response because older user agents may not understand 301/307.
+	// Shouldn't send the response for POST or HEAD; that leaves GET.
+	if method == http.MethodGet {
+		note := "<a href=\"" +
+			html.EscapeString(url) +
+			"\">Moved Permanently</a>.\n"
+
+		ctx.ResponseWriter().WriteString(note)
+	}
+}
+
+// matchHost reports whether the request's host matches the subdomain of the tree.
+func (h *routerHandler) matchHost(ctx context.Context, t *trie) bool {
+	if !h.hosts || t.subdomain == "" {
+		return true
+	}
+
+	requestHost := ctx.Host()
+	if netutil.IsLoopbackSubdomain(requestHost) {
+		// this fixes a bug when listening on
+		// 127.0.0.1:8080 for example
+		// and have a wildcard subdomain and a route registered to root domain.
+		return false // it's not a subdomain, it's something like 127.0.0.1 probably
+	}
+	// it's a dynamic wildcard subdomain, we have just to check if ctx.subdomain is not empty
+	if t.subdomain == SubdomainWildcardIndicator {
+		// mydomain.com -> invalid
+		// localhost -> invalid
+		// sub.mydomain.com -> valid
+		// sub.localhost -> valid
+		serverHost := ctx.Application().ConfigurationReadOnly().GetVHost()
+		if serverHost == requestHost {
+			return false // it's not a subdomain, it's a full domain (with .com...)
+		}
+
+		dotIdx := strings.IndexByte(requestHost, '.')
+		slashIdx := strings.IndexByte(requestHost, '/')
+		if dotIdx > 0 && (slashIdx == -1 || slashIdx > dotIdx) {
+			// if "." was found anywhere but not at the first path segment (host).
+		} else {
 			return false
 		}
+		// continue to that, any subdomain is valid.
+	} else if !strings.HasPrefix(requestHost, t.subdomain) { // t.subdomain contains the dot.
+		return false
+	}
+
+	return true
+}
+
+func (h *routerHandler) subdomainAndPathAndMethodExists(ctx context.Context, t *trie, method, path string) bool {
+	if method != "" && method != t.method {
+		return false
+	}
+
+	if !h.matchHost(ctx, t) {
+		return false
 	}
 
-	n := t.search(path, ctx.Params())
+	n, _, _ := h.find(ctx, t, path)
 	return n != nil
 }
 
@@ -1,6 +1,9 @@
 package router
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/hidevopsio/iris/context"
 	"github.com/hidevopsio/iris/core/errors"
 	"github.com/hidevopsio/iris/macro"
@@ -98,6 +101,25 @@ type Party interface {
 	//
 	// Example: https://github.com/hidevopsio/iris/tree/master/_examples/mvc/middleware/without-ctx-next
 	SetExecutionRules(executionRules ExecutionRules) Party
+	// Timeout sets a maximum execution time for the handlers of the future routes
+	// of this Party and its children.
+	// The request's std context, `ctx.Request().Context()`, is canceled when the "timeout" is exceeded
+	// and the "statusCode" error (503 if zero) is fired instead of the recorded response,
+	// so it can be customized through the `OnErrorCode`.
+	// A zero "timeout" disables it.
+	//
+	// See `context#Timeout` and `Route#Timeout` for more.
+	//
+	// Returns this Party.
+	Timeout(timeout time.Duration, statusCode int) Party
+	// SetPathPolicy sets the way the request paths are matched against the future routes
+	// of this Party and its children, i.e case-insensitive matching
+	// and strict, lenient or redirect trailing slash handling.
+	//
+	// See `PathPolicy` and `Route#PathPolicy` for more.
+	//
+	// Returns this Party.
+	SetPathPolicy(policy PathPolicy) Party
 	// Handle registers a route to the server's router.
 	// if empty method is passed then handler(s) are being registered to all methods, same as .Any.
 	//
@@ -252,6 +274,14 @@ type Party interface {
 	//
 	// Returns the GET *Route.
 	StaticWeb(requestPath string, systemPath string) *Route
+	// StaticFS same as `StaticWeb` but it serves the files of the "fs" file system,
+	// i.e an `http.FS(embedFS)`, a `context.BindataFS` or a `context.OverlayFS`
+	// which can shadow embedded files with local ones.
+	//
+	//     router.StaticFS("/static", context.OverlayFS(http.Dir("./overrides"), http.FS(assets)))
+	//
+	// Returns the GET *Route.
+	StaticFS(requestPath string, fs http.FileSystem) *Route
 
 	// Layout overrides the parent template layout with a more specific layout for this Party.
 	// It returns the current Party.
@@ -1,11 +1,15 @@
 package router
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"path"
 	"strconv"
 	"strings"
 
+	"github.com/hidevopsio/iris/core/errors"
+	"github.com/hidevopsio/iris/core/memstore"
 	"github.com/hidevopsio/iris/core/netutil"
 	"github.com/hidevopsio/iris/macro"
 	"github.com/hidevopsio/iris/macro/interpreter/ast"
@@ -364,3 +368,144 @@ func (ps *RoutePathReverser) URL(routeName string, paramValues ...interface{}) (
 
 	return
 }
+
+// URLParams are the named parameters' values of a route, used to build its URL.
+// The `URLParamSubdomain` key is reserved for the subdomain part of a wildcard subdomain route.
+//
+// See `RoutePathReverser#Build`.
+type URLParams map[string]interface{}
+
+// URLParamSubdomain is the key of the `URLParams` which holds the subdomain
+// of a wildcard subdomain route, i.e "username" for the "*." subdomain results to "username.mydomain.com".
+const URLParamSubdomain = "subdomain"
+
+var (
+	errURLRouteNotFound = errors.New("url: route '%s' not found")
+	errURLParamMissing  = errors.New("url: route '%s': missing value for parameter '%s'")
+	errURLParamInvalid  = errors.New("url: route '%s': invalid value '%s' for parameter '%s'")
+	errURLHostMissing   = errors.New("url: route '%s': subdomain routes require an absolute URL but host is missing")
+)
+
+// Build returns the URL of a route based on its name, the named parameters' values and an optional query.
+// The values are validated by the parameters' macro functions, i.e {id:int min(1)}, and
+// trailing optional parameters can be omitted.
+//
+// Routes without subdomain result to a path, i.e /user/42?tab=posts,
+// subdomain and wildcard subdomain routes result to an absolute URL
+// based on the host, see `WithHost` and `WithServer`.
+func (ps *RoutePathReverser) Build(routeName string, params URLParams, query url.Values) (string, error) {
+	r := ps.provider.GetRoute(routeName)
+	if r == nil {
+		return "", errURLRouteNotFound.Format(routeName)
+	}
+
+	tmpl := r.Tmpl()
+	args := make([]string, 0, len(tmpl.Params))
+	omitted := ""
+
+	for i := range tmpl.Params {
+		p := &tmpl.Params[i]
+
+		v, ok := params[p.Name]
+		if !ok || v == nil {
+			if !p.Optional {
+				return "", errURLParamMissing.Format(routeName, p.Name)
+			}
+			if omitted == "" {
+				omitted = p.Name
+			}
+			continue
+		}
+
+		if omitted != "" { // optional parameters can be omitted only at the end of the path.
+			return "", errURLParamMissing.Format(routeName, omitted)
+		}
+
+		value := toString(v)
+		if p.CanEval() && !p.Eval(value, new(memstore.Store)) {
+			return "", errURLParamInvalid.Format(routeName, value, p.Name)
+		}
+
+		args = append(args, escapeParamValue(p, value))
+	}
+
+	urlpath := r.ResolvePath(args...)
+	if len(query) > 0 {
+		urlpath += "?" + query.Encode()
+	}
+
+	if r.Subdomain == "" {
+		return urlpath, nil
+	}
+
+	if ps.vhost == "" || ps.vscheme == "" {
+		return "", errURLHostMissing.Format(routeName)
+	}
+
+	subdomain := r.Subdomain
+	if subdomain == SubdomainWildcardIndicator {
+		v, ok := params[URLParamSubdomain]
+		if !ok || v == nil {
+			return "", errURLParamMissing.Format(routeName, URLParamSubdomain)
+		}
+		subdomain = toString(v) + "."
+	}
+
+	return ps.vscheme + "://" + subdomain + ps.vhost + urlpath, nil
+}
+
+// Href is the template-friendly version of `Build`,
+// it receives pairs of names and values, the names that are not route parameters
+// are added to the query, i.e {{ url "user.show" "id" 42 "tab" "posts" }}.
+// It returns an empty string on failure.
+func (ps *RoutePathReverser) Href(routeName string, pairs ...interface{}) string {
+	r := ps.provider.GetRoute(routeName)
+	if r == nil {
+		return ""
+	}
+
+	params := make(URLParams)
+	query := make(url.Values)
+
+	for i := 0; i+1 < len(pairs); i += 2 {
+		key := toString(pairs[i])
+		if key == URLParamSubdomain || r.Tmpl().IsParam(key) {
+			params[key] = pairs[i+1]
+			continue
+		}
+
+		query.Add(key, toString(pairs[i+1]))
+	}
+
+	u, err := ps.Build(routeName, params, query)
+	if err != nil {
+		return ""
+	}
+
+	return u
+}
+
+func toString(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// escapeParamValue escapes the parameter's value,
+// the slashes of a trailing parameter, i.e {p:path}, are kept.
+func escapeParamValue(p *macro.TemplateParam, value string) string {
+	if t, ok := p.Type.(ast.TrailingParamType); ok && t.Trailing() {
+		segments := strings.Split(value, "/")
+		for i, segment := range segments {
+			segments[i] = url.PathEscape(segment)
+		}
+		return strings.Join(segments, "/")
+	}
+
+	return url.PathEscape(value)
+}
@@ -0,0 +1,81 @@
+package router
+
+import (
+	"strings"
+)
+
+// TrailingSlashMode describes how a request path with a trailing slash
+// is matched against a route registered without it, i.e "/users/" and "/users".
+type TrailingSlashMode uint8
+
+const (
+	// TrailingSlashRedirect redirects the client to the path without the trailing slash,
+	// 301 or 307 for POST and PUT requests. The default mode.
+	TrailingSlashRedirect TrailingSlashMode = iota
+	// TrailingSlashLenient serves the route as if the path had no trailing slash.
+	TrailingSlashLenient
+	// TrailingSlashStrict does not correct the path, "/users/" is not the "/users" route,
+	// the duplicate slashes are kept as well.
+	TrailingSlashStrict
+)
+
+// PathPolicy describes how the request paths are matched against the routes of a Party.
+// The zero value keeps the default behavior: case-sensitive paths and
+// trailing and duplicate slashes are redirected to the clean path.
+//
+// Note that the `Configuration#DisablePathCorrection` disables the slash corrections for all routes.
+//
+// See `Party#SetPathPolicy`.
+type PathPolicy struct {
+	// CaseInsensitive matches the static path segments ignoring their case,
+	// i.e "/Users/{name}" is matched by "/users/kataras" and the "name" parameter is "kataras".
+	CaseInsensitive bool
+	// CanonicalRedirect redirects the case-insensitive matches to the registered path's case.
+	CanonicalRedirect bool
+	// TrailingSlash is the mode of the request paths with a trailing slash,
+	// defaults to the `TrailingSlashRedirect`.
+	TrailingSlash TrailingSlashMode
+	// KeepDuplicateSlashes disables the cleaning of the request paths with duplicate slashes,
+	// i.e "/users//kataras". The cleaned path is redirected or served based on the "TrailingSlash" mode.
+	KeepDuplicateSlashes bool
+}
+
+// cleanSlashes removes the duplicate and the trailing slashes of a request path.
+func cleanSlashes(p string) string {
+	for strings.Contains(p, "//") {
+		p = strings.Replace(p, "//", "/", -1)
+	}
+
+	if len(p) > 1 && p[len(p)-1] == '/' {
+		p = p[0 : len(p)-1]
+	}
+
+	return p
+}
+
+// canonicalPath returns the "reqPath" with the case of the node's registered static path segments,
+// the parameters' values are kept as they're.
+func canonicalPath(registeredPath, reqPath string) string {
+	if registeredPath == pathSep {
+		return reqPath
+	}
+
+	keys := strings.Split(registeredPath, pathSep)
+	segments := strings.Split(reqPath, pathSep)
+
+	for i, key := range keys {
+		if i >= len(segments) || key == "" {
+			continue
+		}
+
+		if key[0] == WildcardParamStart[0] {
+			break // the rest of the segments are the wildcard's value.
+		}
+
+		if key[0] != ParamStart[0] {
+			segments[i] = key
+		}
+	}
+
+	return strings.Join(segments, pathSep)
+}
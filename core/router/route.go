@@ -3,6 +3,7 @@ package router
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hidevopsio/iris/context"
 	"github.com/hidevopsio/iris/macro"
@@ -32,6 +33,24 @@ type Route struct {
 	// FormattedPath all dynamic named parameters (if any) replaced with %v,
 	// used by Application to validate param values of a Route based on its name.
 	FormattedPath string `json:"formattedPath"`
+	// Timeout is the maximum execution time of the route's handlers, zero means no timeout.
+	// When exceeded, the request's std context is canceled and the
+	// TimeoutStatusCode (503 if zero) is fired instead of the recorded response.
+	// It's set by the Party's `Timeout` but it can be changed before build.
+	//
+	// See `context#Timeout` for more.
+	Timeout           time.Duration `json:"timeout"`
+	TimeoutStatusCode int           `json:"timeoutStatusCode"`
+	// true if the timeout handler was prepended to the Handlers on build.
+	hasTimeoutHandler bool
+	// PathPolicy describes how the request paths are matched against this route,
+	// i.e case-insensitive and trailing slash handling.
+	// It's set by the Party's `SetPathPolicy` but it can be changed before build.
+	PathPolicy PathPolicy `json:"pathPolicy"`
+	// Meta is the route's informational metadata, i.e its description, tags and required permissions,
+	// it's there for other subsystems like documentation generators, authorization and metrics middleware,
+	// they can read it through the `Context#GetCurrentRoute().Meta()` as well.
+	Meta context.RouteMeta `json:"meta"`
 }
 
 // NewRoute returns a new route based on its method,
@@ -139,6 +158,12 @@ func (r *Route) BuildHandlers() {
 		r.Handlers = append(r.Handlers, r.doneHandlers...)
 		r.doneHandlers = r.doneHandlers[0:0]
 	} // note: no mutex needed, this should be called in-sync when server is not running of course.
+
+	if r.Timeout > 0 && !r.hasTimeoutHandler {
+		// the timeout handler should be the first one in order to run the whole chain with a deadline.
+		r.Handlers = append(context.Handlers{context.Timeout(r.Timeout, r.TimeoutStatusCode)}, r.Handlers...)
+		r.hasTimeoutHandler = true
+	}
 }
 
 // String returns the form of METHOD, SUBDOMAIN, TMPL PATH.
@@ -167,6 +192,10 @@ func (r Route) RegisteredHandlersLen() int {
 		n--
 	}
 
+	if r.hasTimeoutHandler {
+		n--
+	}
+
 	return n
 }
 
@@ -229,13 +258,53 @@ func (r Route) StaticPath() string {
 	return src[:bidx]
 }
 
+// optionalPaths returns the route's underline path
+// without its trailing optional parameters, one path per omitted parameter,
+// i.e "/archive/:year/:month" with both of them optional
+// returns "/archive/:year" and "/archive".
+func (r Route) optionalPaths() (paths []string) {
+	path := r.Path
+	for i := len(r.tmpl.Params) - 1; i >= 0; i-- {
+		if !r.tmpl.Params[i].Optional {
+			break
+		}
+
+		idx := strings.LastIndexByte(path, '/')
+		if idx == -1 {
+			break
+		}
+
+		path = path[:idx]
+		if path == "" {
+			path = "/"
+		}
+		paths = append(paths, path)
+	}
+
+	return
+}
+
 // ResolvePath returns the formatted path's %v replaced with the args.
+// Trailing optional parameters without a corresponding argument are omitted from the result.
 func (r Route) ResolvePath(args ...string) string {
 	rpath, formattedPath := r.Path, r.FormattedPath
 	if rpath == formattedPath {
 		// static, no need to pass args
 		return rpath
 	}
+
+	if n := len(r.tmpl.Params); len(args) < n && r.tmpl.Params[n-1].Optional {
+		// cut the omitted optional parameters' segments, they are always at the end of the path.
+		if paths := r.optionalPaths(); len(paths) > 0 {
+			omitted := n - len(args)
+			if omitted > len(paths) {
+				omitted = len(paths)
+			}
+			rpath = paths[omitted-1]
+			formattedPath = formatPath(rpath)
+		}
+	}
+
 	// check if we have /*, if yes then join all arguments to one as path and pass that as parameter
 	if rpath[len(rpath)-1] == WildcardParamStart[0] {
 		parameter := strings.Join(args, "/")
@@ -302,3 +371,7 @@ func (rd routeReadOnlyWrapper) Tmpl() macro.Template {
 func (rd routeReadOnlyWrapper) MainHandlerName() string {
 	return rd.Route.MainHandlerName
 }
+
+func (rd routeReadOnlyWrapper) Meta() context.RouteMeta {
+	return rd.Route.Meta
+}
@@ -0,0 +1,121 @@
+package router
+
+// RouteChangeType describes the kind of a `RouteChange`.
+type RouteChangeType uint8
+
+const (
+	// RouteAdded is fired when a route was registered after the previous build.
+	RouteAdded RouteChangeType = iota + 1
+	// RouteRemoved is fired when a route was removed, see `APIBuilder#RemoveRoute`.
+	RouteRemoved
+	// RouteReplaced is fired when a route was removed and registered again with the same name.
+	RouteReplaced
+	// RouteOffline is fired when a route's method changed to `MethodNone`, see `Route#SetStatusOffline`.
+	RouteOffline
+	// RouteRestored is fired when an offline route is available again, see `Route#RestoreStatus`.
+	RouteRestored
+	// RouteMethodChanged is fired when a route's method changed, see `Route#ChangeMethod`.
+	RouteMethodChanged
+)
+
+func (t RouteChangeType) String() string {
+	switch t {
+	case RouteAdded:
+		return "added"
+	case RouteRemoved:
+		return "removed"
+	case RouteReplaced:
+		return "replaced"
+	case RouteOffline:
+		return "offline"
+	case RouteRestored:
+		return "restored"
+	case RouteMethodChanged:
+		return "method changed"
+	default:
+		return "unknown"
+	}
+}
+
+// RouteChange is the event which is fired
+// to the `Router#RegisterOnRouteChange` listeners
+// when `Router#RefreshRouter` applied a change to a route.
+type RouteChange struct {
+	Type RouteChangeType
+	// Route is the current route, for `RouteRemoved` is the removed one.
+	Route *Route
+	// Previous is the replaced route on `RouteReplaced`, otherwise nil.
+	Previous *Route
+	// PreviousMethod is the method of the route at the previous build.
+	PreviousMethod string
+}
+
+// routeSnapshot keeps the state of a route at build time,
+// a route's method can be changed by the caller before the next build.
+type routeSnapshot struct {
+	route  *Route
+	method string
+}
+
+// RegisterOnRouteChange registers a listener which is called
+// after a `RefreshRouter` applied changes to the routes, once per changed route.
+// The initial build does not fire any events.
+func (router *Router) RegisterOnRouteChange(cb func(RouteChange)) {
+	if cb == nil {
+		return
+	}
+
+	router.mu.Lock()
+	router.routeChangeCb = append(router.routeChangeCb, cb)
+	router.mu.Unlock()
+}
+
+// diffRoutes stores the "routes" as the latest build and returns their
+// changes since the previous one. Should be called under the router's lock.
+func (router *Router) diffRoutes(routes []*Route) (changes []RouteChange) {
+	snapshots := make(map[string]routeSnapshot, len(routes))
+	for _, r := range routes {
+		snapshots[r.Name] = routeSnapshot{route: r, method: r.Method}
+	}
+
+	prev := router.routes
+	router.routes = snapshots
+
+	if prev == nil { // initial build.
+		return nil
+	}
+
+	for _, r := range routes {
+		old, ok := prev[r.Name]
+		if !ok {
+			changes = append(changes, RouteChange{Type: RouteAdded, Route: r})
+			continue
+		}
+
+		if old.route != r {
+			changes = append(changes, RouteChange{Type: RouteReplaced, Route: r, Previous: old.route, PreviousMethod: old.method})
+			continue
+		}
+
+		if old.method == r.Method {
+			continue
+		}
+
+		typ := RouteMethodChanged
+		if r.Method == MethodNone {
+			typ = RouteOffline
+		} else if old.method == MethodNone {
+			typ = RouteRestored
+		}
+
+		changes = append(changes, RouteChange{Type: typ, Route: r, PreviousMethod: old.method})
+	}
+
+	for name, old := range prev {
+		if _, ok := snapshots[name]; !ok {
+			changes = append(changes, RouteChange{Type: RouteRemoved, Route: old.route, PreviousMethod: old.method})
+		}
+	}
+
+	return
+}
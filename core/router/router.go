@@ -3,6 +3,7 @@ package router
 import (
 	"net/http"
 	"sync"
+	"sync/atomic"
 
 	"github.com/hidevopsio/iris/context"
 	"github.com/hidevopsio/iris/core/errors"
@@ -13,25 +14,58 @@ import (
 // Router is responsible to build the received request handler and run it
 // to serve requests, based on the received context.Pool.
 //
-// User can refresh the router with `RefreshRouter` whenever a route's field is changed by him.
+// User can refresh the router with `RefreshRouter` whenever a route's field is changed by him,
+// even when the server is running.
 type Router struct {
 	mu sync.Mutex // for Downgrade, WrapRouter & BuildRouter,
 	// not indeed but we don't to risk its usage by third-parties.
-	requestHandler RequestHandler   // build-accessible, can be changed to define a custom router or proxy, used on RefreshRouter too.
-	mainHandler    http.HandlerFunc // init-accessible
-	wrapperFunc    func(http.ResponseWriter, *http.Request, http.HandlerFunc)
+
+	// holds the *routerState, the request handler, which is build-accessible and
+	// can be changed to define a custom router or proxy, and the main handler.
+	// They are swapped atomically on RefreshRouter, the in-flight requests are completed by the previous ones.
+	state       atomic.Value
+	wrapperFunc func(http.ResponseWriter, *http.Request, http.HandlerFunc)
 
 	cPool          *context.Pool // used on RefreshRouter
 	routesProvider RoutesProvider
+
+	// the routes of the latest build, used to fire the route change events on RefreshRouter.
+	routes        map[string]routeSnapshot
+	routeChangeCb []func(RouteChange)
 }
 
+type routerState struct {
+	requestHandler RequestHandler
+	mainHandler    http.HandlerFunc
+}
+
+var emptyRouterState = new(routerState)
+
 // NewRouter returns a new empty Router.
 func NewRouter() *Router { return &Router{} }
 
+func (router *Router) load() *routerState {
+	if s, ok := router.state.Load().(*routerState); ok {
+		return s
+	}
+
+	return emptyRouterState
+}
+
 // RefreshRouter re-builds the router. Should be called when a route's state
-// changed (i.e Method changed at serve-time).
+// changed (i.e Method changed at serve-time) or routes were registered or removed at serve-time.
+//
+// The default request handler is re-built from scratch and
+// it replaces the current one only on success, so it's safe to be called while serving,
+// requests that are already being served complete on the previous routing table.
+// The listeners registered by `RegisterOnRouteChange` are notified after the swap.
 func (router *Router) RefreshRouter() error {
-	return router.BuildRouter(router.cPool, router.requestHandler, router.routesProvider, true)
+	requestHandler := router.load().requestHandler
+	if _, ok := requestHandler.(*routerHandler); ok {
+		requestHandler = NewDefaultHandler()
+	}
+
+	return router.BuildRouter(router.cPool, requestHandler, router.routesProvider, true)
 }
 
 // BuildRouter builds the router based on
@@ -51,26 +85,25 @@ func (router *Router) BuildRouter(cPool *context.Pool, requestHandler RequestHan
 		return errors.New("router: context pool is nil")
 	}
 
+	router.mu.Lock()
+
 	// build the handler using the routesProvider
 	if err := requestHandler.Build(routesProvider); err != nil {
+		router.mu.Unlock()
 		return err
 	}
 
-	router.mu.Lock()
-	defer router.mu.Unlock()
-
 	// store these for RefreshRouter's needs.
 	if force {
 		router.cPool = cPool
-		router.requestHandler = requestHandler
 		router.routesProvider = routesProvider
 	} else {
 		if router.cPool == nil {
 			router.cPool = cPool
 		}
 
-		if router.requestHandler == nil {
-			router.requestHandler = requestHandler
+		if current := router.load().requestHandler; current != nil {
+			requestHandler = current
 		}
 
 		if router.routesProvider == nil && routesProvider != nil {
@@ -79,14 +112,30 @@ func (router *Router) BuildRouter(cPool *context.Pool, requestHandler RequestHan
 	}
 
 	// the important
-	router.mainHandler = func(w http.ResponseWriter, r *http.Request) {
+	mainHandler := func(w http.ResponseWriter, r *http.Request) {
 		ctx := cPool.Acquire(w, r)
-		router.requestHandler.HandleRequest(ctx)
+		requestHandler.HandleRequest(ctx)
 		cPool.Release(ctx)
 	}
 
 	if router.wrapperFunc != nil { // if wrapper used then attach that as the router service
-		router.mainHandler = NewWrapper(router.wrapperFunc, router.mainHandler).ServeHTTP
+		mainHandler = NewWrapper(router.wrapperFunc, mainHandler).ServeHTTP
+	}
+
+	router.state.Store(&routerState{requestHandler: requestHandler, mainHandler: mainHandler})
+
+	var changes []RouteChange
+	if router.routesProvider != nil {
+		changes = router.diffRoutes(router.routesProvider.GetRoutes())
+	}
+	listeners := router.routeChangeCb
+	router.mu.Unlock()
+
+	// fire outside of the lock, a listener may register or refresh routes too.
+	for _, change := range changes {
+		for _, cb := range listeners {
+			cb(change)
+		}
 	}
 
 	return nil
@@ -105,13 +154,14 @@ func (router *Router) BuildRouter(cPool *context.Pool, requestHandler RequestHan
 // Downgrade is thread-safe.
 func (router *Router) Downgrade(newMainHandler http.HandlerFunc) {
 	router.mu.Lock()
-	router.mainHandler = newMainHandler
+	router.state.Store(&routerState{requestHandler: router.load().requestHandler, mainHandler: newMainHandler})
 	router.mu.Unlock()
 }
 
 // Downgraded returns true if this router is downgraded.
 func (router *Router) Downgraded() bool {
-	return router.mainHandler != nil && router.requestHandler == nil
+	s := router.load()
+	return s.mainHandler != nil && s.requestHandler == nil
 }
 
 // WrapperFunc is used as an expected input parameter signature
@@ -156,17 +206,17 @@ func (router *Router) WrapRouter(wrapperFunc WrapperFunc) {
 
 // ServeHTTPC serves the raw context, useful if we have already a context, it by-pass the wrapper.
 func (router *Router) ServeHTTPC(ctx context.Context) {
-	router.requestHandler.HandleRequest(ctx)
+	router.load().requestHandler.HandleRequest(ctx)
 }
 
 func (router *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	router.mainHandler(w, r)
+	router.load().mainHandler(w, r)
 }
 
 // RouteExists reports whether a particular route exists
 // It will search from the current subdomain of context's host, if not inside the root domain.
 func (router *Router) RouteExists(ctx context.Context, method, path string) bool {
-	return router.requestHandler.RouteExists(ctx, method, path)
+	return router.load().requestHandler.RouteExists(ctx, method, path)
 }
 
 type wrapper struct {
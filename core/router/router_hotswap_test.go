@@ -0,0 +1,135 @@
+// black-box testing
+
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/context"
+	"github.com/hidevopsio/iris/core/router"
+	irishttptest "github.com/hidevopsio/iris/httptest"
+)
+
+func TestRouterHotSwap(t *testing.T) {
+	app := iris.New()
+
+	var (
+		mu      sync.Mutex
+		changes []router.RouteChange
+	)
+	app.RegisterOnRouteChange(func(c router.RouteChange) {
+		mu.Lock()
+		changes = append(changes, c)
+		mu.Unlock()
+	})
+
+	expectChanges := func(expected ...router.RouteChangeType) {
+		t.Helper()
+		mu.Lock()
+		defer mu.Unlock()
+
+		if len(changes) != len(expected) {
+			t.Fatalf("expected %d route changes but got %d: %v", len(expected), len(changes), changes)
+		}
+
+		for i, c := range changes {
+			if c.Type != expected[i] {
+				t.Fatalf("[%d] expected route change of type: %s but got: %s", i, expected[i], c.Type)
+			}
+		}
+		changes = changes[0:0]
+	}
+
+	app.Get("/static", func(ctx context.Context) { ctx.WriteString("static") })
+	e := irishttptest.New(t, app)
+	expectChanges() // initial build does not fire.
+
+	e.GET("/static").Expect().Status(iris.StatusOK).Body().Equal("static")
+	e.GET("/plugin").Expect().Status(iris.StatusNotFound)
+
+	// add.
+	app.Get("/plugin", func(ctx context.Context) { ctx.WriteString("v1") }).Name = "plugin"
+	if err := app.RefreshRouter(); err != nil {
+		t.Fatal(err)
+	}
+	expectChanges(router.RouteAdded)
+	e.GET("/plugin").Expect().Status(iris.StatusOK).Body().Equal("v1")
+
+	// replace.
+	if !app.RemoveRoute("plugin") {
+		t.Fatalf("expected plugin route to be removed")
+	}
+	app.Get("/plugin", func(ctx context.Context) { ctx.WriteString("v2") }).Name = "plugin"
+	if err := app.RefreshRouter(); err != nil {
+		t.Fatal(err)
+	}
+	expectChanges(router.RouteReplaced)
+	e.GET("/plugin").Expect().Status(iris.StatusOK).Body().Equal("v2")
+
+	// offline and restore.
+	app.GetRoute("plugin").SetStatusOffline()
+	app.RefreshRouter()
+	expectChanges(router.RouteOffline)
+	e.GET("/plugin").Expect().Status(iris.StatusNotFound)
+
+	app.GetRoute("plugin").RestoreStatus()
+	app.RefreshRouter()
+	expectChanges(router.RouteRestored)
+	e.GET("/plugin").Expect().Status(iris.StatusOK).Body().Equal("v2")
+
+	// remove.
+	app.RemoveRoute("plugin")
+	app.RefreshRouter()
+	expectChanges(router.RouteRemoved)
+	e.GET("/plugin").Expect().Status(iris.StatusNotFound)
+
+	if app.RemoveRoute("plugin") {
+		t.Fatalf("expected false when removing a not registered route")
+	}
+
+	e.GET("/static").Expect().Status(iris.StatusOK).Body().Equal("static")
+}
+
+func TestRouterHotSwapInFlight(t *testing.T) {
+	app := iris.New()
+
+	entered, release := make(chan struct{}), make(chan struct{})
+	app.Get("/slow", func(ctx context.Context) {
+		close(entered)
+		<-release
+		ctx.WriteString("completed")
+	}).Name = "slow"
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+		close(done)
+	}()
+
+	<-entered
+	app.RemoveRoute("slow")
+	if err := app.RefreshRouter(); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	<-done
+
+	if expected, got := "completed", rec.Body.String(); expected != got {
+		t.Fatalf("expected in-flight request to complete with: %s but got: %s", expected, got)
+	}
+
+	rec = httptest.NewRecorder()
+	app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	if expected, got := http.StatusNotFound, rec.Code; expected != got {
+		t.Fatalf("expected status code: %d but got: %d", expected, got)
+	}
+}
@@ -0,0 +1,87 @@
+// black-box testing
+
+package router_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/context"
+	"github.com/hidevopsio/iris/hero"
+	"github.com/hidevopsio/iris/httptest"
+)
+
+func TestRouterOptionalParams(t *testing.T) {
+	app := iris.New()
+
+	app.Get("/list/{page:int min(1) default(1)}", func(ctx context.Context) {
+		page, _ := ctx.Params().GetInt("page")
+		ctx.Writef("page: %d", page)
+	}).Name = "list"
+
+	app.Get("/archive/{year:int?}/{month:int?}", func(ctx context.Context) {
+		ctx.Writef("%s/%s", ctx.Params().Get("year"), ctx.Params().Get("month"))
+	}).Name = "archive"
+
+	app.Get("/hello/{name:string default(world)}", func(ctx context.Context) {
+		ctx.Writef("hello %s", ctx.Params().Get("name"))
+	})
+
+	e := httptest.New(t, app)
+
+	e.GET("/list").Expect().Status(iris.StatusOK).Body().Equal("page: 1")
+	e.GET("/list/5").Expect().Status(iris.StatusOK).Body().Equal("page: 5")
+	e.GET("/list/0").Expect().Status(iris.StatusNotFound)
+	e.GET("/list/notanumber").Expect().Status(iris.StatusNotFound)
+
+	e.GET("/archive").Expect().Status(iris.StatusOK).Body().Equal("/")
+	e.GET("/archive/2018").Expect().Status(iris.StatusOK).Body().Equal("2018/")
+	e.GET("/archive/2018/10").Expect().Status(iris.StatusOK).Body().Equal("2018/10")
+
+	e.GET("/hello").Expect().Status(iris.StatusOK).Body().Equal("hello world")
+	e.GET("/hello/iris").Expect().Status(iris.StatusOK).Body().Equal("hello iris")
+
+	if expected, got := "/list", app.GetRoute("list").ResolvePath(); expected != got {
+		t.Fatalf("expected resolved path to be: %s but got: %s", expected, got)
+	}
+
+	if expected, got := "/list/3", app.GetRoute("list").ResolvePath("3"); expected != got {
+		t.Fatalf("expected resolved path to be: %s but got: %s", expected, got)
+	}
+
+	if expected, got := "/archive/2018", app.GetRoute("archive").ResolvePath("2018"); expected != got {
+		t.Fatalf("expected resolved path to be: %s but got: %s", expected, got)
+	}
+}
+
+func TestRouterOptionalParamsByIndex(t *testing.T) {
+	app := iris.New()
+
+	// the parameters are bound by their index, an omitted optional parameter
+	// without a default value should not shift the ones with a default value.
+	app.Get("/posts/{id:int?}/{page:int default(3)}", hero.Handler(func(id int, page int) string {
+		return fmt.Sprintf("%d:%d", id, page)
+	}))
+
+	app.Get("/tags/{tag:string?}/{sort:string default(asc)}", func(ctx context.Context) {
+		ctx.Writef("%v:%v", ctx.Params().GetEntryAt(0).ValueRaw, ctx.Params().GetEntryAt(1).ValueRaw)
+	})
+
+	e := httptest.New(t, app)
+
+	e.GET("/posts").Expect().Status(iris.StatusOK).Body().Equal("0:3")
+	e.GET("/posts/7").Expect().Status(iris.StatusOK).Body().Equal("7:3")
+	e.GET("/posts/7/2").Expect().Status(iris.StatusOK).Body().Equal("7:2")
+
+	e.GET("/tags").Expect().Status(iris.StatusOK).Body().Equal(":asc")
+	e.GET("/tags/go").Expect().Status(iris.StatusOK).Body().Equal("go:asc")
+	e.GET("/tags/go/desc").Expect().Status(iris.StatusOK).Body().Equal("go:desc")
+}
+
+func TestRouterOptionalParamInvalidDefault(t *testing.T) {
+	app := iris.New()
+	if route := app.Get("/list/{page:int default(first)}", func(ctx context.Context) {}); route != nil {
+		t.Fatalf("expected a nil route because of an invalid default value")
+	}
+}
@@ -0,0 +1,150 @@
+// black-box testing
+
+package router_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/context"
+	"github.com/hidevopsio/iris/core/router"
+)
+
+func TestRouterPathPolicy(t *testing.T) {
+	app := iris.New()
+
+	writePath := func(ctx context.Context) {
+		ctx.WriteString(ctx.Path() + ctx.Params().Get("name"))
+	}
+
+	app.Get("/users", writePath)
+	app.Post("/users", writePath)
+	app.Get("/users/list", writePath)
+	app.Get("/Admin", writePath)
+
+	app.Party("/lenient").SetPathPolicy(router.PathPolicy{TrailingSlash: router.TrailingSlashLenient}).Get("/users", writePath)
+	app.Party("/strict").SetPathPolicy(router.PathPolicy{TrailingSlash: router.TrailingSlashStrict}).Get("/users", writePath)
+	app.Party("/dup").SetPathPolicy(router.PathPolicy{KeepDuplicateSlashes: true}).Get("/users/list", writePath)
+	app.Party("/ci").SetPathPolicy(router.PathPolicy{CaseInsensitive: true}).Get("/Users/{name}", writePath)
+	app.Party("/canon").SetPathPolicy(router.PathPolicy{CaseInsensitive: true, CanonicalRedirect: true}).Get("/Users/{name}", writePath)
+
+	app.Get("/exists", func(ctx context.Context) {
+		ctx.Writef("%v %v %v",
+			ctx.RouteExists(http.MethodGet, "/users/"),
+			ctx.RouteExists(http.MethodGet, "/strict/users/"),
+			ctx.RouteExists(http.MethodGet, "/CI/users/kataras"))
+	})
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method     string
+		path       string
+		statusCode int
+		location   string
+		body       string
+	}{
+		{"GET", "/users", 200, "", "/users"},                                    // 0
+		{"GET", "/users/", 301, "/users", ""},                                   // 1
+		{"POST", "/users/", 307, "/users", ""},                                  // 2
+		{"GET", "/users//list", 301, "/users/list", ""},                         // 3
+		{"GET", "/notfound/", 404, "", ""},                                      // 4
+		{"GET", "/admin", 404, "", ""},                                          // 5
+		{"GET", "/lenient/users/", 200, "", "/lenient/users"},                   // 6
+		{"GET", "/lenient//users", 200, "", "/lenient/users"},                   // 7
+		{"GET", "/strict/users", 200, "", "/strict/users"},                      // 8
+		{"GET", "/strict/users/", 404, "", ""},                                  // 9
+		{"GET", "/dup/users//list", 404, "", ""},                                // 10
+		{"GET", "/dup/users/list/", 301, "/dup/users/list", ""},                 // 11
+		{"GET", "/ci/Users/kataras", 200, "", "/ci/Users/kataraskataras"},       // 12
+		{"GET", "/CI/users/Kataras", 200, "", "/ci/Users/KatarasKataras"},       // 13
+		{"GET", "/CI/users/Kataras/", 301, "/CI/users/Kataras", ""},             // 14
+		{"GET", "/canon/Users/Kataras", 200, "", "/canon/Users/KatarasKataras"}, // 15
+		{"GET", "/CANON/users/Kataras", 301, "/canon/Users/Kataras", ""},        // 16
+		{"GET", "/exists", 200, "", "true false true"},                          // 17
+	}
+
+	for i, tt := range tests {
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if expected, got := tt.statusCode, rec.Code; expected != got {
+			t.Fatalf("[%d] %s %s: expected status code: %d but got: %d", i, tt.method, tt.path, expected, got)
+		}
+
+		if expected, got := tt.location, rec.Header().Get("Location"); expected != got {
+			t.Fatalf("[%d] %s %s: expected location: %s but got: %s", i, tt.method, tt.path, expected, got)
+		}
+
+		if tt.body != "" {
+			if expected, got := tt.body, rec.Body.String(); expected != got {
+				t.Fatalf("[%d] %s %s: expected body: %s but got: %s", i, tt.method, tt.path, expected, got)
+			}
+		}
+	}
+}
+
+func TestRouterPathPolicyStaticWeb(t *testing.T) {
+	dir, err := ioutil.TempDir("", "iris-path-policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err = os.MkdirAll(filepath.Join(dir, "sub"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join(dir, "sub", "index.html"), []byte("index"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	app.StaticWeb("/static", dir)
+	app.Party("/lenient").SetPathPolicy(router.PathPolicy{TrailingSlash: router.TrailingSlashLenient, CaseInsensitive: true}).
+		StaticWeb("/assets", dir)
+	app.Party("/strict").SetPathPolicy(router.PathPolicy{TrailingSlash: router.TrailingSlashStrict}).
+		StaticWeb("/assets", dir)
+
+	if err = app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path       string
+		statusCode int
+		location   string
+	}{
+		{"/static/sub/", 301, "/static/sub"}, // 0
+		{"/static/sub", 200, ""},             // 1
+		{"/lenient/assets/sub/", 200, ""},    // 2
+		{"/LENIENT/Assets//sub", 200, ""},    // 3
+		{"/strict/assets/sub", 301, "sub/"},  // 4
+		{"/strict/assets/sub/", 200, ""},     // 5
+	}
+
+	for i, tt := range tests {
+		rec := httptest.NewRecorder()
+		app.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if expected, got := tt.statusCode, rec.Code; expected != got {
+			t.Fatalf("[%d] %s: expected status code: %d but got: %d", i, tt.path, expected, got)
+		}
+
+		if expected, got := tt.location, rec.Header().Get("Location"); expected != got {
+			t.Fatalf("[%d] %s: expected location: %s but got: %s", i, tt.path, expected, got)
+		}
+
+		if tt.statusCode == 200 {
+			if expected, got := "index", rec.Body.String(); expected != got {
+				t.Fatalf("[%d] %s: expected body: %s but got: %s", i, tt.path, expected, got)
+			}
+		}
+	}
+}
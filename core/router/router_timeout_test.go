@@ -0,0 +1,97 @@
+// black-box testing
+
+package router_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/context"
+	"github.com/hidevopsio/iris/httptest"
+)
+
+func TestRouterTimeout(t *testing.T) {
+	app := iris.New()
+
+	slowHandler := func(ctx context.Context) {
+		select {
+		case <-time.After(time.Second):
+			ctx.WriteString("late")
+		case <-ctx.Request().Context().Done():
+			ctx.Header("X-Late", "true")
+			ctx.WriteString("canceled")
+		}
+	}
+
+	// ignores the std context.
+	blockingHandler := func(ctx context.Context) {
+		time.Sleep(500 * time.Millisecond)
+		ctx.Header("X-Late", "true")
+		ctx.WriteString("late")
+	}
+
+	fastHandler := func(ctx context.Context) {
+		ctx.WriteString("fast")
+	}
+
+	app.OnErrorCode(iris.StatusGatewayTimeout, func(ctx context.Context) {
+		ctx.WriteString("custom timeout")
+	})
+
+	app.Get("/no-timeout", fastHandler)
+
+	p := app.Party("/api").Timeout(50*time.Millisecond, 0)
+	p.Get("/slow", slowHandler)
+	p.Get("/fast", fastHandler)
+
+	app.Party("/gateway").Timeout(50*time.Millisecond, iris.StatusGatewayTimeout).Get("/slow", slowHandler)
+
+	app.Party("/blocking").Timeout(50*time.Millisecond, iris.StatusGatewayTimeout).Get("/", blockingHandler)
+
+	var recovered interface{}
+	app.WrapRouter(func(w http.ResponseWriter, r *http.Request, router http.HandlerFunc) {
+		defer func() {
+			if recovered = recover(); recovered != nil {
+				w.WriteHeader(iris.StatusInternalServerError)
+			}
+		}()
+		router(w, r)
+	})
+	app.Party("/panic").Timeout(50*time.Millisecond, 0).Get("/", func(ctx context.Context) {
+		panic("handler panic")
+	})
+
+	r := app.Get("/route/slow", slowHandler)
+	r.Timeout = 50 * time.Millisecond
+
+	e := httptest.New(t, app)
+
+	e.GET("/no-timeout").Expect().Status(iris.StatusOK).Body().Equal("fast")
+	e.GET("/api/fast").Expect().Status(iris.StatusOK).Body().Equal("fast")
+	e.GET("/api/slow").Expect().Status(iris.StatusServiceUnavailable).
+		Header("X-Late").Empty()
+	e.GET("/gateway/slow").Expect().Status(iris.StatusGatewayTimeout).Body().Equal("custom timeout")
+	e.GET("/route/slow").Expect().Status(iris.StatusServiceUnavailable)
+
+	// the response is sent on the deadline, even if the handler doesn't return.
+	start := time.Now()
+	resp := e.GET("/blocking").Expect().Status(iris.StatusGatewayTimeout)
+	resp.Header("X-Late").Empty()
+	resp.Body().Equal("custom timeout")
+	if elapsed := time.Since(start); elapsed >= 500*time.Millisecond {
+		t.Fatalf("expected the timeout response on the deadline but it took %s", elapsed)
+	}
+
+	// the panic of a handler is raised by the timeout middleware with the handler's stack.
+	e.GET("/panic").Expect().Status(iris.StatusInternalServerError)
+	tp, ok := recovered.(*context.TimeoutPanic)
+	if !ok {
+		t.Fatalf("expected a timeout panic but got: %#v", recovered)
+	}
+	if tp.Value != "handler panic" || !strings.Contains(string(tp.Stack), "router_timeout_test.go") {
+		t.Fatalf("expected the panic value and the stack of the handler but got: %s", tp)
+	}
+}
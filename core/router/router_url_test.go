@@ -0,0 +1,93 @@
+// black-box testing
+
+package router_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/context"
+	"github.com/hidevopsio/iris/core/router"
+)
+
+func TestRoutePathReverserBuild(t *testing.T) {
+	app := iris.New()
+	h := func(ctx context.Context) {}
+
+	app.Get("/", h).Name = "home"
+	app.Get("/user/{id:int min(1)}", h).Name = "user.show"
+	app.Get("/archive/{year:int?}/{month:int?}", h).Name = "archive"
+	app.Get("/files/{p:path}", h).Name = "files"
+	app.Get("/search/{term:string}", h).Name = "search"
+	app.Party("admin.").Get("/dashboard", h).Name = "admin"
+	app.WildcardSubdomain().Get("/profile", h).Name = "profile"
+
+	rv := router.NewRoutePathReverser(app.APIBuilder, router.WithHost("mydomain.com"))
+
+	tests := []struct {
+		routeName string
+		params    router.URLParams
+		query     url.Values
+		expected  string
+		valid     bool
+	}{
+		{"home", nil, nil, "/", true},                                                                                        // 0
+		{"home", nil, url.Values{"page": {"2"}}, "/?page=2", true},                                                           // 1
+		{"user.show", router.URLParams{"id": 42}, url.Values{"tab": {"posts"}}, "/user/42?tab=posts", true},                  // 2
+		{"user.show", router.URLParams{"id": 0}, nil, "", false},                                                             // 3
+		{"user.show", router.URLParams{"id": "me"}, nil, "", false},                                                          // 4
+		{"user.show", nil, nil, "", false},                                                                                   // 5
+		{"archive", nil, nil, "/archive", true},                                                                              // 6
+		{"archive", router.URLParams{"year": 2018}, nil, "/archive/2018", true},                                              // 7
+		{"archive", router.URLParams{"year": 2018, "month": 10}, nil, "/archive/2018/10", true},                              // 8
+		{"archive", router.URLParams{"month": 10}, nil, "", false},                                                           // 9
+		{"files", router.URLParams{"p": "docs/a b.txt"}, nil, "/files/docs/a%20b.txt", true},                                 // 10
+		{"search", router.URLParams{"term": "a/b"}, nil, "/search/a%2Fb", true},                                              // 11
+		{"admin", nil, nil, "http://admin.mydomain.com/dashboard", true},                                                     // 12
+		{"profile", router.URLParams{router.URLParamSubdomain: "kataras"}, nil, "http://kataras.mydomain.com/profile", true}, // 13
+		{"profile", nil, nil, "", false},                                                                                     // 14
+		{"notfound", nil, nil, "", false},                                                                                    // 15
+	}
+
+	for i, tt := range tests {
+		got, err := rv.Build(tt.routeName, tt.params, tt.query)
+		if tt.valid && err != nil {
+			t.Fatalf("[%d] expected no error but got: %v", i, err)
+		}
+
+		if !tt.valid && err == nil {
+			t.Fatalf("[%d] expected an error but got url: %s", i, got)
+		}
+
+		if got != tt.expected {
+			t.Fatalf("[%d] expected url to be: %s but got: %s", i, tt.expected, got)
+		}
+	}
+
+	if expected, got := "/user/42?tab=posts", rv.Href("user.show", "id", 42, "tab", "posts"); expected != got {
+		t.Fatalf("expected href to be: %s but got: %s", expected, got)
+	}
+
+	if got := rv.Href("user.show", "id", -1); got != "" {
+		t.Fatalf("expected empty href for invalid parameter value but got: %s", got)
+	}
+
+	// without a host subdomain routes can't be resolved.
+	if _, err := app.URL("admin", nil, nil); err == nil {
+		t.Fatalf("expected an error for subdomain route without host")
+	}
+
+	if expected, got := "/user/1", mustURL(t, app, "user.show", router.URLParams{"id": 1}); expected != got {
+		t.Fatalf("expected url to be: %s but got: %s", expected, got)
+	}
+}
+
+func mustURL(t *testing.T, app *iris.Application, routeName string, params router.URLParams) string {
+	t.Helper()
+	u, err := app.URL(routeName, params, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
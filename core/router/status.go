@@ -95,8 +95,21 @@ func defaultErrorCodeHandlers() *ErrorCodeHandlers {
 	return chs
 }
 
+// statusText returns the default handler of the "statusCode",
+// it sends the status code's text or, if the `Configuration.EnableProblemDetails` is true,
+// the problem details of the request's error, see `context#GetErr`.
 func statusText(statusCode int) context.Handler {
 	return func(ctx context.Context) {
+		if ctx.Application().ConfigurationReadOnly().GetEnableProblemDetails() {
+			p := context.NewProblem(statusCode, ctx.GetErr())
+			if p.Instance == "" {
+				p.Instance = ctx.Path()
+			}
+
+			ctx.Problem(p)
+			return
+		}
+
 		ctx.WriteString(http.StatusText(statusCode))
 	}
 }
@@ -3,7 +3,10 @@ package router_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/hidevopsio/iris"
@@ -64,6 +67,88 @@ func TestOnAnyErrorCode(t *testing.T) {
 
 }
 
+func TestProblemDetails(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithProblemDetails)
+
+	app.Get("/err", func(ctx context.Context) {
+		ctx.SetErr(errors.New("database is down"))
+		ctx.StatusCode(iris.StatusInternalServerError)
+	})
+
+	app.Post("/json", func(ctx context.Context) {
+		var v struct{ Name string }
+		if err := ctx.ReadJSON(&v); err != nil {
+			if p, ok := err.(context.Problem); ok {
+				ctx.Problem(p)
+				return
+			}
+
+			ctx.StatusCode(iris.StatusInternalServerError)
+			return
+		}
+
+		ctx.WriteString(v.Name)
+	})
+
+	// custom error handlers can read the original error.
+	app.OnErrorCode(iris.StatusConflict, func(ctx context.Context) {
+		ctx.WriteString("conflict: " + ctx.GetErr().Error())
+	})
+	app.Get("/conflict", func(ctx context.Context) {
+		ctx.SetErr(errors.New("user exists"))
+		ctx.StatusCode(iris.StatusConflict)
+	})
+
+	e := httptest.New(t, app)
+
+	body := e.GET("/notfound").Expect().Status(iris.StatusNotFound).
+		ContentType(context.ContentProblemHeaderValue).Body().Raw()
+	expectProblem(t, body, map[string]interface{}{
+		"type":     "about:blank",
+		"title":    "Not Found",
+		"status":   float64(iris.StatusNotFound),
+		"instance": "/notfound",
+	})
+
+	body = e.GET("/err").Expect().Status(iris.StatusInternalServerError).
+		ContentType(context.ContentProblemHeaderValue).Body().Raw()
+	expectProblem(t, body, map[string]interface{}{
+		"type":     "about:blank",
+		"title":    "Internal Server Error",
+		"status":   float64(iris.StatusInternalServerError),
+		"detail":   "database is down",
+		"instance": "/err",
+	})
+
+	e.POST("/json").WithBytes([]byte(`{"Name":"iris"}`)).Expect().
+		Status(iris.StatusOK).Body().Equal("iris")
+	body = e.POST("/json").WithBytes([]byte(`{"Name":`)).Expect().
+		Status(iris.StatusBadRequest).ContentType(context.ContentProblemHeaderValue).Body().Raw()
+	expectProblem(t, body, map[string]interface{}{
+		"type":   "about:blank",
+		"title":  "Bad Request",
+		"status": float64(iris.StatusBadRequest),
+		"detail": "unexpected end of JSON input",
+	})
+
+	e.GET("/conflict").Expect().Status(iris.StatusConflict).
+		Body().Equal("conflict: user exists")
+}
+
+func expectProblem(t *testing.T, body string, expected map[string]interface{}) {
+	t.Helper()
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("expected problem details but got: %s: %v", body, err)
+	}
+
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected problem details: %v but got: %v", expected, got)
+	}
+}
+
 func checkAndClearBuf(t *testing.T, buff *bytes.Buffer, expected string) {
 	if got, expected := buff.String(), expected; got != expected {
 		t.Fatalf("expected middleware to run before the error handler, expected %s but got %s", expected, got)
@@ -32,6 +32,7 @@ type trieNode struct {
 	// insert data.
 	Handlers  context.Handlers
 	RouteName string
+	policy    PathPolicy
 }
 
 func newTrieNode() *trieNode {
@@ -88,6 +89,8 @@ type trie struct {
 	// so even 404 (on http services) is up to it, see trie#insert.
 	hasRootWildcard bool
 	hasRootSlash    bool
+	// true if at least one route is case-insensitive, its static segments are inserted in lower case.
+	hasCaseInsensitive bool
 
 	method string
 	// subdomain is empty for default-hostname routes,
@@ -114,7 +117,7 @@ func slowPathSplit(path string) []string {
 	return strings.Split(path, pathSep)[1:]
 }
 
-func (tr *trie) insert(path, routeName string, handlers context.Handlers) {
+func (tr *trie) insert(path, routeName string, handlers context.Handlers, policy PathPolicy) {
 	input := slowPathSplit(path)
 
 	n := tr.root
@@ -122,6 +125,10 @@ func (tr *trie) insert(path, routeName string, handlers context.Handlers) {
 		tr.hasRootSlash = true
 	}
 
+	if policy.CaseInsensitive {
+		tr.hasCaseInsensitive = true
+	}
+
 	var paramKeys []string
 
 	for _, s := range input {
@@ -144,6 +151,8 @@ func (tr *trie) insert(path, routeName string, handlers context.Handlers) {
 					tr.hasRootWildcard = true
 				}
 			}
+		} else if policy.CaseInsensitive {
+			s = strings.ToLower(s)
 		}
 
 		if !n.hasChild(s) {
@@ -156,6 +165,7 @@ func (tr *trie) insert(path, routeName string, handlers context.Handlers) {
 
 	n.RouteName = routeName
 	n.Handlers = handlers
+	n.policy = policy
 	n.paramKeys = paramKeys
 	n.key = path
 	n.end = true
@@ -172,6 +182,24 @@ func (tr *trie) insert(path, routeName string, handlers context.Handlers) {
 }
 
 func (tr *trie) search(q string, params *context.RequestParams) *trieNode {
+	if n := tr.find(q, q, params); n != nil || !tr.hasCaseInsensitive {
+		return n
+	}
+
+	// the static segments of the case-insensitive routes are stored in lower case,
+	// the parameters' values are taken from the original path.
+	if key := strings.ToLower(q); key != q && len(key) == len(q) {
+		if n := tr.find(key, q, params); n != nil && n.policy.CaseInsensitive {
+			return n
+		}
+	}
+
+	return nil
+}
+
+// find searches the node by the "key" path, "q" is the same path but
+// it's used to fill the parameters' values.
+func (tr *trie) find(key, q string, params *context.RequestParams) *trieNode {
 	end := len(q)
 
 	if end == 0 || (end == 1 && q[0] == pathSepB) {
@@ -193,7 +221,7 @@ func (tr *trie) search(q string, params *context.RequestParams) *trieNode {
 
 	for {
 		if i == end || q[i] == pathSepB {
-			if child := n.getChild(q[start:i]); child != nil {
+			if child := n.getChild(key[start:i]); child != nil {
 				n = child
 			} else if n.childNamedParameter {
 				n = n.getChild(ParamStart)
@@ -18,13 +18,13 @@ require (
 	github.com/hidevopsio/go-uuid v0.0.0-20240811102623-0749af16addf
 	github.com/hidevopsio/golog v0.0.0-20240811115351-6b4a7711e704
 	github.com/hidevopsio/httpexpect v0.0.0-20240811100504-92ed99bc8bec
-	github.com/hidevopsio/i18n v0.0.0-20240811095331-eee3daa5c80f
 	github.com/json-iterator/go v1.1.5
 	github.com/klauspost/compress v1.4.0
 	github.com/microcosm-cc/bluemonday v1.0.26
 	github.com/ryanuber/columnize v2.1.0+incompatible
 	github.com/valyala/bytebufferpool v1.0.0
 	golang.org/x/crypto v0.36.0
+	gopkg.in/ini.v1 v1.67.0
 	gopkg.in/russross/blackfriday.v2 v2.0.0+incompatible
 	gopkg.in/yaml.v2 v2.4.0
 )
@@ -68,6 +68,5 @@ require (
 	golang.org/x/sys v0.31.0 // indirect
 	golang.org/x/text v0.23.0 // indirect
 	google.golang.org/protobuf v1.33.0 // indirect
-	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -73,6 +73,17 @@ type (
 	// See `ExecutionRules` and `core/router/Party#SetExecutionRules` for more.
 	ExecutionOptions = router.ExecutionOptions
 
+	// PathPolicy describes how the request paths are matched against the routes of a Party,
+	// i.e case-insensitive matching and trailing slash handling.
+	//
+	// See `core/router/Party#SetPathPolicy` for more.
+	PathPolicy = router.PathPolicy
+
+	// URLParams are the named parameters' values of a route, used by the `Application#URL`.
+	//
+	// An alias for the `core/router#URLParams`.
+	URLParams = router.URLParams
+
 	// CookieOption is the type of function that is accepted on
 	// context's methods like `SetCookieKV`, `RemoveCookie` and `SetCookie`
 	// as their (last) variadic input argument to amend the end cookie's form.
@@ -0,0 +1,191 @@
+package hero_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/hero/di"
+	"github.com/hidevopsio/iris/httptest"
+	"github.com/hidevopsio/iris/middleware/recover"
+
+	. "github.com/hidevopsio/iris/hero"
+)
+
+type (
+	testConfig struct {
+		Name string
+	}
+
+	testDB struct {
+		name string
+		id   int
+	}
+
+	testTx struct {
+		db *testDB
+		id int
+	}
+
+	testRepo struct {
+		tx *testTx
+	}
+)
+
+func TestDependencyLifetimes(t *testing.T) {
+	var dbs, txs int
+
+	h := New().Register(
+		testConfig{Name: "mydb"},
+		// depends on the testConfig, computed once.
+		Singleton(func(cfg testConfig) *testDB {
+			dbs++
+			return &testDB{name: cfg.Name, id: dbs}
+		}),
+		// depends on the request and the singleton, computed once per request.
+		PerRequest(func(ctx iris.Context, db *testDB) *testTx {
+			txs++
+			return &testTx{db: db, id: txs}
+		}),
+		// depends on the per-request one, computed on each injection.
+		func(tx *testTx) *testRepo {
+			return &testRepo{tx: tx}
+		},
+	)
+
+	app := iris.New()
+	app.Get("/", h.Handler(func(ctx iris.Context, tx *testTx, repo *testRepo) {
+		// the middleware and the main handler share the same *testTx.
+		ctx.Next()
+	}), h.Handler(func(tx *testTx, repo *testRepo, db *testDB) string {
+		return fmt.Sprintf("%s:%d:%d:%d:%v", db.name, db.id, tx.id, repo.tx.id, repo.tx == tx)
+	}))
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().Equal("mydb:1:1:1:true")
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().Equal("mydb:1:2:2:true")
+
+	if dbs != 1 {
+		t.Fatalf("expected the singleton to be computed once but computed %d times", dbs)
+	}
+}
+
+func TestDependencyErrors(t *testing.T) {
+	type (
+		a struct{}
+		b struct{}
+	)
+
+	tests := []struct {
+		values   []interface{}
+		handler  interface{}
+		expected string
+	}{
+		{ // cycle.
+			values: []interface{}{
+				func(b) a { return a{} },
+				func(a) b { return b{} },
+			},
+			handler:  func(a) {},
+			expected: "dependency cycle:",
+		},
+		{ // missing.
+			values:   []interface{}{func(b) a { return a{} }},
+			handler:  func(a) {},
+			expected: "no dependency for its input argument #0 of type",
+		},
+		{ // singleton which depends on the request.
+			values:   []interface{}{Singleton(func(ctx iris.Context) a { return a{} })},
+			handler:  func(a) {},
+			expected: "singleton dependency",
+		},
+	}
+
+	for i, tt := range tests {
+		values := di.NewValues()
+		values.Add(tt.values...)
+
+		injector := di.Func(tt.handler, values...)
+		if injector.Length != 0 {
+			t.Fatalf("[%d] expected an invalid injector", i)
+		}
+
+		if trace := injector.String(); !strings.Contains(trace, tt.expected) {
+			t.Fatalf("[%d] expected the trace to contain '%s' but got: '%s'", i, tt.expected, trace)
+		}
+	}
+}
+
+func TestDependencyValues(t *testing.T) {
+	values := di.NewValues()
+	values.Add(PerRequest(func(ctx iris.Context) testConfig { return testConfig{} }))
+
+	if !values.AddOnce(PerRequest(func(ctx iris.Context) *testDB { return nil })) {
+		t.Fatalf("expected the dependencies of different constructors to be added")
+	}
+
+	if values.AddOnce(PerRequest(func(ctx iris.Context) *testDB { return nil })) {
+		t.Fatalf("expected the dependency of the same constructor type to not be added twice")
+	}
+
+	if expected, got := 2, values.Len(); expected != got {
+		t.Fatalf("expected %d dependencies but got %d", expected, got)
+	}
+
+	if injector := di.Func(func(testConfig) {}, values...); injector.Length != 1 ||
+		!strings.Contains(injector.String(), "per-request") {
+		t.Fatalf("expected a per-request binding but got: '%s'", injector.String())
+	}
+}
+
+type testCloser struct {
+	log *[]string
+}
+
+func (c *testCloser) Close() error {
+	*c.log = append(*c.log, "close")
+	return nil
+}
+
+func TestDependencyCleanup(t *testing.T) {
+	var log []string
+
+	h := New().Register(
+		PerRequest(func(ctx iris.Context) (*testTx, func()) {
+			log = append(log, "begin")
+			return &testTx{}, func() { log = append(log, "end") }
+		}),
+		func(tx *testTx) *testCloser {
+			return &testCloser{log: &log}
+		},
+	)
+
+	app := iris.New()
+	app.Get("/", h.Handler(func(ctx iris.Context, tx *testTx) {
+		ctx.Next()
+		log = append(log, "middleware")
+	}), h.Handler(func(tx *testTx, c *testCloser) string {
+		log = append(log, "handler")
+		return "ok"
+	}))
+	app.Get("/panic", recover.New(), h.Handler(func(tx *testTx) {
+		panic("handler")
+	}))
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().Equal("ok")
+
+	expected := "begin handler middleware close end"
+	if got := strings.Join(log, " "); got != expected {
+		t.Fatalf("expected the log to be '%s' but got '%s'", expected, got)
+	}
+
+	log = nil
+	e.GET("/panic").Expect().Status(httptest.StatusInternalServerError)
+
+	expected = "begin end"
+	if got := strings.Join(log, " "); got != expected {
+		t.Fatalf("expected the log to be '%s' but got '%s'", expected, got)
+	}
+}
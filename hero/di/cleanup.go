@@ -0,0 +1,106 @@
+package di
+
+import (
+	"io"
+	"reflect"
+)
+
+var (
+	cleanupTyp = reflect.TypeOf((func())(nil))
+	closerTyp  = reflect.TypeOf((*io.Closer)(nil)).Elem()
+)
+
+// isFactory reports whether the "typ" function returns a dependency's value,
+// it should return one single value or the value and its cleanup function, i.e
+// `func(db *sql.DB) (*sql.Tx, func())`.
+func isFactory(typ reflect.Type) bool {
+	switch typ.NumOut() {
+	case 1:
+		return true
+	case 2:
+		return typ.Out(1) == cleanupTyp
+	default:
+		return false
+	}
+}
+
+// hasCleanup reports whether the values of the "typ" factory should be cleaned up
+// at the end of the request, they have a cleanup function or they are `io.Closer`.
+func hasCleanup(typ reflect.Type) bool {
+	return typ.NumOut() == 2 || typ.Out(0).Implements(closerTyp)
+}
+
+// makeCleanupReturnValue returns the `BindObject#ReturnValue` of a factory's "call",
+// if "cleanup" is true then the cleanup function or the `io.Closer#Close` of its values
+// is registered to the request, see `Cleanup`.
+func makeCleanupReturnValue(call func([]reflect.Value) []reflect.Value, typ reflect.Type, cleanup bool) func([]reflect.Value) reflect.Value {
+	if !cleanup || !hasCleanup(typ) {
+		return func(ctx []reflect.Value) reflect.Value {
+			return call(ctx)[0]
+		}
+	}
+
+	return func(ctx []reflect.Value) reflect.Value {
+		results := call(ctx)
+		v := results[0]
+
+		var fn func()
+		if len(results) == 2 {
+			fn, _ = results[1].Interface().(func())
+		} else if goodVal(v) {
+			if closer, ok := v.Interface().(io.Closer); ok {
+				fn = func() { closer.Close() }
+			}
+		}
+
+		if fn != nil {
+			if c := requestCleanups(ctx); c != nil {
+				c.fns = append(c.fns, fn)
+			}
+		}
+
+		return v
+	}
+}
+
+const cleanupsKey = "iris.di.cleanups"
+
+type cleanups struct {
+	fns []func()
+}
+
+func requestCleanups(ctx []reflect.Value) *cleanups {
+	values := requestValues(ctx)
+	if values == nil {
+		return nil
+	}
+
+	c, _ := values.Get(cleanupsKey).(*cleanups)
+	return c
+}
+
+// Cleanup prepares the request's "ctx" for the cleanup of its dependencies,
+// the factories' cleanup functions and the `io.Closer` values which are computed for it.
+// It returns the function which calls them, in reverse order, or nil
+// if the request is already prepared by a previous handler, i.e a middleware which calls the `Next`.
+//
+// It's used by hero and mvc, the returned function should be deferred, so it's called on panics too.
+// The values of the singleton dependencies are never cleaned up.
+func Cleanup(ctx reflect.Value) (end func()) {
+	values := requestValues([]reflect.Value{ctx})
+	if values == nil || values.Get(cleanupsKey) != nil {
+		return nil
+	}
+
+	c := new(cleanups)
+	values.Set(cleanupsKey, c)
+
+	return func() {
+		values.Remove(cleanupsKey)
+		// the deferred functions are called in reverse order
+		// and even if one of them panics.
+		for _, fn := range c.fns {
+			defer fn()
+		}
+	}
+}
@@ -0,0 +1,360 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/hidevopsio/iris/core/memstore"
+)
+
+// Lifetime is the lifetime of a dependency's value.
+// See `TransientLifetime`, `PerRequestLifetime` and `SingletonLifetime`.
+type Lifetime uint8
+
+const (
+	// TransientLifetime is the lifetime of the dependencies which are computed on each injection,
+	// it's the default one for the functions.
+	TransientLifetime Lifetime = iota
+	// PerRequestLifetime is the lifetime of the dependencies which are computed once per request,
+	// on their first injection, and they are shared across all the injections of that request,
+	// i.e the input arguments of a middleware and a handler, or a controller's fields and its method's input arguments.
+	PerRequestLifetime
+	// SingletonLifetime is the lifetime of the dependencies which are computed once, on their first injection,
+	// and they are shared across all the requests.
+	// These dependencies can't depend on the request, i.e the Context, or on any `PerRequestLifetime` dependency.
+	SingletonLifetime
+)
+
+func (l Lifetime) String() string {
+	switch l {
+	case PerRequestLifetime:
+		return "per-request"
+	case SingletonLifetime:
+		return "singleton"
+	default:
+		return "transient"
+	}
+}
+
+// Dependency is a constructor function with an explicit `Lifetime`.
+// The constructor should return one single value,
+// its input arguments are resolved by the hijacker, i.e the Context,
+// and by the rest of the dependencies, including other constructors.
+//
+// Example: `NewDependency(func(cfg Config, ctx iris.Context) *Session {...}, PerRequestLifetime)`.
+type Dependency struct {
+	Constructor reflect.Value
+	Lifetime    Lifetime
+
+	// the context's values key of the per-request value.
+	key string
+	// the singleton value.
+	once  sync.Once
+	value reflect.Value
+}
+
+// NewDependency returns a new dependency of the "constructor" function,
+// its values are computed based on the "lifetime".
+func NewDependency(constructor interface{}, lifetime Lifetime) *Dependency {
+	d := &Dependency{
+		Constructor: ValueOf(constructor),
+		Lifetime:    lifetime,
+	}
+
+	d.key = fmt.Sprintf("iris.di.dependency.%p", d)
+	return d
+}
+
+var dependencyTyp = reflect.TypeOf((*Dependency)(nil))
+
+// asDependency returns the "v" as dependency, if it's one.
+func asDependency(v reflect.Value) (*Dependency, bool) {
+	if !v.IsValid() || v.Type() != dependencyTyp {
+		return nil, false
+	}
+
+	d, ok := v.Interface().(*Dependency)
+	return d, ok && d != nil
+}
+
+// typeOf returns the type of the "v", if it's a dependency then it returns its constructor's type.
+func typeOf(v reflect.Value) reflect.Type {
+	if d, ok := asDependency(v); ok {
+		return d.Constructor.Type()
+	}
+
+	return v.Type()
+}
+
+// valueType returns the type of the value that the "v" binds.
+func valueType(v reflect.Value) reflect.Type {
+	if d, ok := asDependency(v); ok {
+		v = d.Constructor
+	}
+
+	if IsFunc(v) && isFactory(v.Type()) {
+		return v.Type().Out(0)
+	}
+
+	return v.Type()
+}
+
+// valuesContext is completed by the Iris Context,
+// its values are the storage of the per-request dependencies.
+type valuesContext interface {
+	Values() *memstore.Store
+}
+
+// requestValues returns the values of the request,
+// the "ctx" is the input arguments of the injectors, the first one should be the Context.
+func requestValues(ctx []reflect.Value) *memstore.Store {
+	if len(ctx) == 0 || !ctx[0].IsValid() || !ctx[0].CanInterface() {
+		return nil
+	}
+
+	c, ok := ctx[0].Interface().(valuesContext)
+	if !ok {
+		return nil
+	}
+
+	return c.Values()
+}
+
+// bind applies the dependency's lifetime to the "b" bind object.
+func (d *Dependency) bind(b *BindObject) error {
+	b.Lifetime = d.Lifetime
+	if b.BindType != Dynamic {
+		return nil
+	}
+
+	returnValue := b.ReturnValue
+
+	switch d.Lifetime {
+	case SingletonLifetime:
+		if b.requestBound {
+			return fmt.Errorf("singleton dependency '%s' depends on the request", b.Type.String())
+		}
+
+		// it's not computed on behalf of a request,
+		// so the cleanup functions of its dependencies are not registered.
+		b.hasCleanup = false
+		b.ReturnValue = func([]reflect.Value) reflect.Value {
+			d.once.Do(func() {
+				d.value = returnValue(nil)
+			})
+
+			return d.value
+		}
+	case PerRequestLifetime:
+		b.requestBound = true
+		b.ReturnValue = func(ctx []reflect.Value) reflect.Value {
+			values := requestValues(ctx)
+			if values == nil {
+				return returnValue(ctx)
+			}
+
+			if v, ok := values.Get(d.key).(reflect.Value); ok {
+				return v
+			}
+
+			v := returnValue(ctx)
+			values.Set(d.key, v)
+			return v
+		}
+	}
+
+	return nil
+}
+
+// resolver makes the bind objects of the dependencies values,
+// including the constructors which depend on other dependencies.
+type resolver struct {
+	values   []reflect.Value
+	hijack   Hijacker
+	goodFunc TypeChecker
+
+	objects []*BindObject
+	errs    []error
+	state   []uint8
+	path    []int
+}
+
+const (
+	unresolved uint8 = iota
+	resolving
+	resolved
+)
+
+// resolveValues returns the bind object of each one of the "values",
+// if a value can't be binded then its object is nil and its error is not nil,
+// the error is `errBad` for the values that are not valid dependencies at all.
+func resolveValues(values []reflect.Value, hijack Hijacker, goodFunc TypeChecker) ([]*BindObject, []error) {
+	r := &resolver{
+		values:   values,
+		hijack:   hijack,
+		goodFunc: goodFunc,
+		objects:  make([]*BindObject, len(values)),
+		errs:     make([]error, len(values)),
+		state:    make([]uint8, len(values)),
+	}
+
+	for i := range values {
+		r.resolve(i)
+	}
+
+	return r.objects, r.errs
+}
+
+func (r *resolver) resolve(i int) (*BindObject, error) {
+	switch r.state[i] {
+	case resolved:
+		return r.objects[i], r.errs[i]
+	case resolving:
+		return nil, r.cycle(i)
+	}
+
+	r.state[i] = resolving
+	r.path = append(r.path, i)
+
+	b, err := r.makeBindObject(i)
+
+	r.path = r.path[:len(r.path)-1]
+	r.state[i] = resolved
+	if err != nil {
+		b = nil
+	}
+	r.objects[i], r.errs[i] = b, err
+
+	return b, err
+}
+
+// cycle returns the error of a dependency which depends on itself, i.e
+// "dependency cycle: *A -> *B -> *A".
+func (r *resolver) cycle(i int) error {
+	trace := ""
+	for j := len(r.path) - 1; j >= 0; j-- {
+		if r.path[j] == i {
+			for _, k := range r.path[j:] {
+				trace += valueType(r.values[k]).String() + " -> "
+			}
+			break
+		}
+	}
+
+	return fmt.Errorf("dependency cycle: %s%s", trace, valueType(r.values[i]).String())
+}
+
+func (r *resolver) makeBindObject(i int) (*BindObject, error) {
+	v := r.values[i]
+
+	d, isDependency := asDependency(v)
+	if isDependency {
+		v = d.Constructor
+		if !IsFunc(v) {
+			return nil, errBad
+		}
+	}
+
+	if !IsFunc(v) {
+		b, err := MakeBindObject(v, r.goodFunc)
+		return &b, err
+	}
+
+	typ := v.Type()
+	if !isFactory(typ) {
+		return nil, errBad
+	}
+
+	b := &BindObject{
+		Type:     typ.Out(0),
+		BindType: Dynamic,
+	}
+
+	var call func(ctx []reflect.Value) []reflect.Value
+	if r.goodFunc == nil || r.goodFunc(typ) {
+		call = v.Call
+		// it accepts the context.
+		b.requestBound = typ.NumIn() > 0
+	} else {
+		var err error
+		if call, err = r.makeConstructor(i, v, b); err != nil {
+			return nil, err
+		}
+	}
+
+	lifetime := TransientLifetime
+	if isDependency {
+		lifetime = d.Lifetime
+	}
+
+	b.ReturnValue = makeCleanupReturnValue(call, typ, lifetime != SingletonLifetime)
+	b.hasCleanup = b.hasCleanup || hasCleanup(typ)
+
+	if isDependency {
+		if err := d.bind(b); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// makeConstructor returns the caller of a function
+// which its input arguments are other dependencies.
+func (r *resolver) makeConstructor(i int, fn reflect.Value, b *BindObject) (func([]reflect.Value) []reflect.Value, error) {
+	typ := fn.Type()
+	n := typ.NumIn()
+	inputs := make([]*BindObject, n)
+
+	for k := 0; k < n; k++ {
+		inTyp := typ.In(k)
+
+		if r.hijack != nil {
+			if in, ok := r.hijack(inTyp); ok && in != nil {
+				inputs[k] = in
+				b.requestBound = true
+				continue
+			}
+		}
+
+		j := r.lookup(i, inTyp)
+		if j == -1 {
+			return nil, fmt.Errorf("dependency '%s': no dependency for its input argument #%d of type '%s'",
+				typ.Out(0).String(), k, inTyp.String())
+		}
+
+		in, err := r.resolve(j)
+		if err != nil {
+			return nil, err
+		}
+
+		inputs[k] = in
+		b.requestBound = b.requestBound || in.requestBound
+		b.hasCleanup = b.hasCleanup || in.hasCleanup
+	}
+
+	return func(ctx []reflect.Value) []reflect.Value {
+		in := make([]reflect.Value, n)
+		for k, b := range inputs {
+			k := k
+			b.Assign(ctx, func(v reflect.Value) {
+				in[k] = v
+			})
+		}
+
+		return fn.Call(in)
+	}, nil
+}
+
+// lookup returns the index of the first value, except the "self" one,
+// which binds the "inTyp", or -1.
+func (r *resolver) lookup(self int, inTyp reflect.Type) int {
+	for j, v := range r.values {
+		if j != self && equalTypes(valueType(v), inTyp) {
+			return j
+		}
+	}
+
+	return -1
+}
@@ -26,6 +26,9 @@ type (
 		// Valid is True when `Length` is > 0, it's statically set-ed for
 		// performance reasons.
 		Has bool
+		// HasCleanup is true when one or more of the binded values
+		// should be cleaned up at the end of the request, see `Cleanup`.
+		HasCleanup bool
 
 		trace string // for debug info.
 
@@ -36,11 +39,13 @@ type (
 type missingInput struct {
 	index int // the function's input argument's index.
 	found bool
+	err   error // the reason of a dependency that could be binded but failed, if any.
 }
 
-func (s *FuncInjector) miss(index int) {
+func (s *FuncInjector) miss(index int, err error) {
 	s.lost = append(s.lost, &missingInput{
 		index: index,
+		err:   err,
 	})
 }
 
@@ -63,6 +68,9 @@ func MakeFuncInjector(fn reflect.Value, hijack Hijacker, goodFunc TypeChecker, v
 	defer s.refresh()
 
 	n := typ.NumIn()
+	// resolve the dependencies, including the ones that depend on other dependencies.
+	objects, errs := resolveValues(values, hijack, goodFunc)
+	used := make([]bool, len(objects))
 
 	for i := 0; i < n; i++ {
 		inTyp := typ.In(i)
@@ -80,18 +88,34 @@ func MakeFuncInjector(fn reflect.Value, hijack Hijacker, goodFunc TypeChecker, v
 		}
 
 		matched := false
+		var err error
 
-		for j, v := range values {
-			if s.addValue(i, v) {
+		for j, b := range objects {
+			if used[j] {
+				continue
+			}
+
+			if b == nil {
+				// keep the reason of a dependency which could be binded
+				// to this input argument, for the debug trace.
+				if errs[j] != errBad && err == nil && equalTypes(valueType(values[j]), inTyp) {
+					err = errs[j]
+				}
+
+				continue
+			}
+
+			if b.IsAssignable(inTyp) {
 				matched = true
-				// remove this value, so it will not try to get binded
+				s.inputs = append(s.inputs, &targetFuncInput{
+					InputIndex: i,
+					Object:     b,
+				})
+				// mark this value as used, so it will not try to get binded
 				// again, a next value even with the same type is able to be
 				// used to other input arg. One value per input argument, order
 				// matters if same type of course.
-				//if len(values) > j+1 {
-				values = append(values[:j], values[j+1:]...)
-				//}
-
+				used[j] = true
 				break
 			}
 		}
@@ -102,7 +126,7 @@ func MakeFuncInjector(fn reflect.Value, hijack Hijacker, goodFunc TypeChecker, v
 			// but before this let's make a list of failed
 			// inputs, so they can be used for a re-try
 			// with different set of binding "values".
-			s.miss(i)
+			s.miss(i, err)
 		}
 
 	}
@@ -113,6 +137,13 @@ func MakeFuncInjector(fn reflect.Value, hijack Hijacker, goodFunc TypeChecker, v
 func (s *FuncInjector) refresh() {
 	s.Length = len(s.inputs)
 	s.Has = s.Length > 0
+	s.HasCleanup = false
+	for _, in := range s.inputs {
+		if in.Object.hasCleanup {
+			s.HasCleanup = true
+			break
+		}
+	}
 }
 
 func (s *FuncInjector) addValue(inputIndex int, value reflect.Value) bool {
@@ -180,9 +211,22 @@ func (s *FuncInjector) String() (trace string) {
 		// remember: on methods that are part of a struct (i.e controller)
 		// the input index  = 1 is the begggining instead of the 0,
 		// because the 0 is the controller receiver pointer of the method.
+		if in.Object.Lifetime != TransientLifetime {
+			bindmethodTyp += " " + in.Object.Lifetime.String()
+		}
 		trace += fmt.Sprintf("[%d] %s binding: '%s' for input position: %d and type: '%s'\n",
 			i+1, bindmethodTyp, in.Object.Type.String(), in.InputIndex, typIn.String())
 	}
+
+	for _, missing := range s.lost {
+		if missing.found || missing.err == nil {
+			continue
+		}
+
+		trace += fmt.Sprintf("[!] Missing binding for input position: %d and type: '%s': %v\n",
+			missing.index, s.typ.In(missing.index).String(), missing.err)
+	}
+
 	return
 }
 
@@ -36,6 +36,13 @@ type BindObject struct {
 
 	BindType    BindType
 	ReturnValue func([]reflect.Value) reflect.Value
+	// Lifetime is the lifetime of the dynamic values, see `Dependency`.
+	Lifetime Lifetime
+
+	// true when the value depends on the request.
+	requestBound bool
+	// true when the value, or one of its dependencies, has a cleanup function.
+	hasCleanup bool
 }
 
 // MakeBindObject accepts any "v" value, struct, pointer or a function
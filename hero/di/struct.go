@@ -40,6 +40,11 @@ type (
 		Has       bool
 		CanInject bool // if any bindable fields when the state is NOT singleton.
 		Scope     Scope
+		// HasCleanup is true when one or more of the binded values
+		// should be cleaned up at the end of the request, see `Cleanup`.
+		HasCleanup bool
+
+		errs []error // for debug info.
 	}
 )
 
@@ -65,6 +70,9 @@ func MakeStructInjector(v reflect.Value, hijack Hijacker, goodFunc TypeChecker,
 		elemType:       IndirectType(v.Type()),
 	}
 
+	// resolve the dependencies, including the ones that depend on other dependencies.
+	objects, errs := resolveValues(values, hijack, goodFunc)
+
 	fields := lookupFields(s.elemType, true, nil)
 	for _, f := range fields {
 		if hijack != nil {
@@ -78,19 +86,26 @@ func MakeStructInjector(v reflect.Value, hijack Hijacker, goodFunc TypeChecker,
 			}
 		}
 
-		for _, val := range values {
+		for j, b := range objects {
 			// the binded values to the struct's fields.
-			b, err := MakeBindObject(val, goodFunc)
+			if err := errs[j]; err != nil {
+				if err == errBad {
+					return s // if error stop here.
+				}
+
+				// a dependency that could be binded but failed, keep it for the debug trace.
+				if equalTypes(valueType(values[j]), f.Type) {
+					s.errs = append(s.errs, fmt.Errorf("field '%s %s': %v", f.Name, f.Type.String(), err))
+				}
 
-			if err != nil {
-				return s // if error stop here.
+				continue
 			}
 
 			if b.IsAssignable(f.Type) {
 				// fmt.Printf("bind the object to the field: %s at index: %#v and type: %s\n", f.Name, f.Index, f.Type.String())
 				s.fields = append(s.fields, &targetStructField{
 					FieldIndex: f.Index,
-					Object:     &b,
+					Object:     b,
 				})
 				break
 			}
@@ -98,6 +113,12 @@ func MakeStructInjector(v reflect.Value, hijack Hijacker, goodFunc TypeChecker,
 	}
 
 	s.Has = len(s.fields) > 0
+	for _, f := range s.fields {
+		if f.Object.hasCleanup {
+			s.HasCleanup = true
+			break
+		}
+	}
 	// set the overall state of this injector.
 	s.fillStruct()
 	s.setState()
@@ -169,11 +190,19 @@ func (s *StructInjector) fillStruct() {
 func (s *StructInjector) String() (trace string) {
 	for i, f := range s.fields {
 		elemField := s.elemType.FieldByIndex(f.FieldIndex)
+		bindmethodTyp := bindTypeString(f.Object.BindType)
+		if f.Object.Lifetime != TransientLifetime {
+			bindmethodTyp += " " + f.Object.Lifetime.String()
+		}
 		trace += fmt.Sprintf("[%d] %s binding: '%s' for field '%s %s'\n",
-			i+1, bindTypeString(f.Object.BindType), f.Object.Type.String(),
+			i+1, bindmethodTyp, f.Object.Type.String(),
 			elemField.Name, elemField.Type.String())
 	}
 
+	for _, err := range s.errs {
+		trace += fmt.Sprintf("[!] Missing binding for %v\n", err)
+	}
+
 	return
 }
 
@@ -63,13 +63,13 @@ func (bv *Values) AddValues(values ...reflect.Value) {
 // this is useful because you may have bind more than one value to two or more fields
 // with the same type.
 func (bv *Values) Remove(value interface{}, n int) bool {
-	return bv.remove(reflect.TypeOf(value), n)
+	return bv.remove(typeOf(reflect.ValueOf(value)), n)
 }
 
 func (bv *Values) remove(typ reflect.Type, n int) (ok bool) {
 	input := *bv
 	for i, in := range input {
-		if equalTypes(in.Type(), typ) {
+		if equalTypes(typeOf(in), typ) {
 			ok = true
 			input = input[:i+copy(input[i:], input[i+1:])]
 			if n > 1 {
@@ -87,12 +87,12 @@ func (bv *Values) remove(typ reflect.Type, n int) (ok bool) {
 // Has returns true if a binder responsible to
 // bind and return a type of "typ" is already registered to this controller.
 func (bv Values) Has(value interface{}) bool {
-	return bv.valueTypeExists(reflect.TypeOf(value))
+	return bv.valueTypeExists(typeOf(reflect.ValueOf(value)))
 }
 
 func (bv Values) valueTypeExists(typ reflect.Type) bool {
 	for _, in := range bv {
-		if equalTypes(in.Type(), typ) {
+		if equalTypes(typeOf(in), typ) {
 			return true
 		}
 	}
@@ -110,7 +110,7 @@ func (bv *Values) AddOnce(value interface{}) bool {
 
 func (bv *Values) addIfNotExists(v reflect.Value) bool {
 	var (
-		typ = v.Type() // no element, raw things here.
+		typ = typeOf(v) // no element, raw things here.
 	)
 
 	if !goodVal(v) {
@@ -62,11 +62,27 @@ type compatibleErr interface {
 // when the response contains an error which is not nil.
 var DefaultErrStatusCode = 400
 
-// DispatchErr writes the error to the response.
+// DispatchErr writes the error to the response and sets it as the request's error,
+// which can be read by the http error code handlers via `context#GetErr`.
+// If the error is a `Result` too, i.e a `Problem`, then it's dispatched instead.
+//
+// If the `Configuration.EnableProblemDetails` is true then the error is sent as a `Problem`.
 func DispatchErr(ctx context.Context, status int, err error) {
+	ctx.SetErr(err)
+	if r, ok := err.(Result); ok {
+		r.Dispatch(ctx)
+		return
+	}
+
 	if status < 400 {
 		status = DefaultErrStatusCode
 	}
+
+	if ctx.Application().ConfigurationReadOnly().GetEnableProblemDetails() {
+		context.NewProblem(status, err).Dispatch(ctx)
+		return
+	}
+
 	ctx.StatusCode(status)
 	if text := err.Error(); text != "" {
 		ctx.WriteString(text)
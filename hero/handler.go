@@ -81,12 +81,27 @@ func makeHandler(handler interface{}, values ...reflect.Value) (context.Handler,
 			callerFileName, callerLineNumber := fpc.FileLine(pc)
 			callerName := fpc.Name()
 
-			err := fmt.Errorf("input arguments length(%d) and valid binders length(%d) are not equal for typeof '%s' which is defined at %s:%d by %s",
-				n, funcInjector.Length, fn.Type().String(), callerFileName, callerLineNumber, callerName)
+			err := fmt.Errorf("input arguments length(%d) and valid binders length(%d) are not equal for typeof '%s' which is defined at %s:%d by %s\n%s",
+				n, funcInjector.Length, fn.Type().String(), callerFileName, callerLineNumber, callerName, funcInjector.String())
 			return nil, err
 		}
 	}
 
+	if funcInjector.HasCleanup {
+		// the cleanup functions of the dependencies are called after the rest of the handlers,
+		// if this handler calls the `Next`, and on panics too.
+		h := func(ctx context.Context) {
+			ctxValue := reflect.ValueOf(ctx)
+			if end := di.Cleanup(ctxValue); end != nil {
+				defer end()
+			}
+
+			DispatchFuncResult(ctx, funcInjector.Call(ctxValue))
+		}
+
+		return h, nil
+	}
+
 	h := func(ctx context.Context) {
 		// in := make([]reflect.Value, n, n)
 		// funcInjector.Inject(&in, reflect.ValueOf(ctx))
@@ -5,6 +5,7 @@ package hero_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/hidevopsio/iris"
 	"github.com/hidevopsio/iris/httptest"
@@ -126,3 +127,15 @@ func TestBindFunctionAsFunctionInputArgument(t *testing.T) {
 	e.POST("/").WithFormField("username", expectedUsername).
 		Expect().Status(iris.StatusOK).Body().Equal(expectedUsername)
 }
+
+func TestPathParamsOfNewTypes(t *testing.T) {
+	app := iris.New()
+	app.Get("/{price:float64}/{birth:date(02-01-2006)}/{id:uuid}", New().Handler(func(price float64, birth time.Time, id string) string {
+		return fmt.Sprintf("%.2f %s %s", price, birth.Format("2006-01-02"), id)
+	}))
+
+	e := httptest.New(t, app)
+	e.GET("/9.99/21-10-2018/6ba7b810-9dad-11d1-80b4-00c04fd430c8").Expect().Status(httptest.StatusOK).
+		Body().Equal("9.99 2018-10-21 6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	e.GET("/9.99/2018-10-21/6ba7b810-9dad-11d1-80b4-00c04fd430c8").Expect().Status(httptest.StatusNotFound)
+}
@@ -48,9 +48,13 @@ func (h *Hero) Dependencies() *di.Values {
 
 // Register adds one or more values as dependencies.
 // The value can be a single struct value-instance or a function
-// which has one input and one output, the input should be
-// an `iris.Context` and the output can be any type, that output type
+// which has one output, its inputs can be an `iris.Context`
+// and any of the rest dependencies, the output can be any type, that output type
 // will be binded to the handler's input argument, if matching.
+// The function is called on each injection, use the `Singleton` and `PerRequest`
+// to change its lifetime. It can return a cleanup function as its second output,
+// i.e `func(db *sql.DB) (*sql.Tx, func())`, or a value which implements the `io.Closer`,
+// these are called at the end of the request, in reverse order, even on panics.
 //
 // Example: `.Register(loggerService{prefix: "dev"}, func(ctx iris.Context) User {...})`.
 func Register(values ...interface{}) *Hero {
@@ -59,9 +63,13 @@ func Register(values ...interface{}) *Hero {
 
 // Register adds one or more values as dependencies.
 // The value can be a single struct value-instance or a function
-// which has one input and one output, the input should be
-// an `iris.Context` and the output can be any type, that output type
+// which has one output, its inputs can be an `iris.Context`
+// and any of the rest dependencies, the output can be any type, that output type
 // will be binded to the handler's input argument, if matching.
+// The function is called on each injection, use the `Singleton` and `PerRequest`
+// to change its lifetime. It can return a cleanup function as its second output,
+// i.e `func(db *sql.DB) (*sql.Tx, func())`, or a value which implements the `io.Closer`,
+// these are called at the end of the request, in reverse order, even on panics.
 //
 // Example: `.Register(loggerService{prefix: "dev"}, func(ctx iris.Context) User {...})`.
 func (h *Hero) Register(values ...interface{}) *Hero {
@@ -69,6 +77,31 @@ func (h *Hero) Register(values ...interface{}) *Hero {
 	return h
 }
 
+// Singleton returns a dependency which its "constructor" function is called once,
+// on its first injection, and its result is shared across all the requests.
+// The "constructor" can accept any of the rest dependencies
+// but not the `iris.Context` or a `PerRequest` dependency.
+//
+// Example: `.Register(Config{...}, hero.Singleton(func(cfg Config) *sql.DB {...}))`.
+func Singleton(constructor interface{}) *di.Dependency {
+	return di.NewDependency(constructor, di.SingletonLifetime)
+}
+
+// PerRequest returns a dependency which its "constructor" function is called once per request,
+// on its first injection, and its result is shared across all the injections of that request,
+// i.e a middleware and the main handler.
+//
+// Example: `.Register(hero.PerRequest(func(ctx iris.Context, db *sql.DB) *sql.Tx {...}))`.
+func PerRequest(constructor interface{}) *di.Dependency {
+	return di.NewDependency(constructor, di.PerRequestLifetime)
+}
+
+// Transient returns a dependency which its "constructor" function is called on each injection,
+// it's the default lifetime of the registered functions.
+func Transient(constructor interface{}) *di.Dependency {
+	return di.NewDependency(constructor, di.TransientLifetime)
+}
+
 // Clone creates and returns a new hero with the default Dependencies.
 // It copies the default's dependencies and returns a new hero.
 func Clone() *Hero {
@@ -0,0 +1,340 @@
+package hero
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hidevopsio/iris/context"
+)
+
+// Created returns a 201 Created `Result`,
+// the "location" is the url of the new resource and it's sent as the "Location" header,
+// the "body", if not nil, is sent like the rest custom struct results, JSON by default.
+//
+// Example: `return hero.Created("/users/42", user)`.
+func Created(location string, body interface{}) Result {
+	return createdResult{location: location, body: body}
+}
+
+type createdResult struct {
+	location string
+	body     interface{}
+}
+
+func (r createdResult) Dispatch(ctx context.Context) {
+	if r.location != "" {
+		ctx.Header("Location", r.location)
+	}
+
+	if r.body == nil {
+		ctx.StatusCode(http.StatusCreated)
+		return
+	}
+
+	DispatchCommon(ctx, http.StatusCreated, "", nil, r.body, nil, true)
+}
+
+// NoContent returns a 204 No Content `Result`.
+func NoContent() Result {
+	return noContentResult{}
+}
+
+type noContentResult struct{}
+
+func (noContentResult) Dispatch(ctx context.Context) {
+	ctx.StatusCode(http.StatusNoContent)
+}
+
+// Redirect returns a `Result` which redirects the client to the "url",
+// the optional "statusCode" should be a 3xx one,
+// defaults to 303 See Other for POST requests and 302 Found for the rest.
+func Redirect(url string, statusCode ...int) Result {
+	r := redirectResult{url: url}
+	if len(statusCode) > 0 {
+		r.statusCode = statusCode[0]
+	}
+
+	return r
+}
+
+type redirectResult struct {
+	url        string
+	statusCode int
+}
+
+func (r redirectResult) Dispatch(ctx context.Context) {
+	if r.statusCode < 300 || r.statusCode >= 400 {
+		r.statusCode = http.StatusFound
+		if ctx.Method() == http.MethodPost {
+			r.statusCode = http.StatusSeeOther
+		}
+	}
+
+	ctx.Redirect(r.url, r.statusCode)
+}
+
+// ProblemContentType is the content type of the `Problem` results.
+const ProblemContentType = context.ContentProblemHeaderValue
+
+// Problem is a `Result` of the problem details of an HTTP API error (RFC 7807),
+// it's sent as "application/problem+json", see `context.Problem` for more.
+//
+// Example:
+//
+//	return hero.Problem{
+//		Type:   "https://example.com/probs/out-of-credit",
+//		Title:  "You do not have enough credit.",
+//		Status: iris.StatusForbidden,
+//		Detail: "Your current balance is 30, but that costs 50.",
+//		Extensions: map[string]interface{}{"balance": 30},
+//	}
+type Problem = context.Problem
+
+var _ Result = Problem{}
+
+// File returns a `Result` which serves the "filename" file,
+// if "attachmentName" is not empty then the client is asked to download it with that name.
+// It sends a 404 Not Found if the file doesn't exist.
+func File(filename string, attachmentName ...string) Result {
+	r := fileResult{filename: filename}
+	if len(attachmentName) > 0 {
+		r.attachmentName = attachmentName[0]
+	}
+
+	return r
+}
+
+type fileResult struct {
+	filename       string
+	attachmentName string
+}
+
+func (r fileResult) Dispatch(ctx context.Context) {
+	var err error
+	if r.attachmentName != "" {
+		err = ctx.SendFile(r.filename, r.attachmentName)
+	} else {
+		err = ctx.ServeFile(r.filename, false)
+	}
+
+	if err != nil {
+		ctx.NotFound()
+	}
+}
+
+// Stream returns a `Result` which copies the "r" to the client as "contentType",
+// if "r" is an `io.Closer` then it's closed afterwards.
+func Stream(contentType string, r io.Reader) Result {
+	return streamResult{contentType: contentType, r: r}
+}
+
+type streamResult struct {
+	contentType string
+	r           io.Reader
+}
+
+func (r streamResult) Dispatch(ctx context.Context) {
+	if closer, ok := r.r.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	if r.contentType != "" {
+		ctx.ContentType(r.contentType)
+	}
+
+	io.Copy(ctx.ResponseWriter(), r.r)
+}
+
+// Paginated is a `Result` of a page of items, the items are sent as JSON
+// with the "X-Total-Count" header and the "Link" header (RFC 5988) which
+// contains the urls of the first, previous, next and last pages.
+//
+// Example:
+//
+//	func(page, perPage int) hero.Paginated {
+//		users, total := repo.List(page, perPage)
+//		return hero.Paginated{Items: users, Page: page, PerPage: perPage, Total: total}
+//	}
+type Paginated struct {
+	Items interface{}
+	// Page is the current, 1-based, page.
+	Page int
+	// PerPage is the number of the items per page.
+	PerPage int
+	// Total is the total number of the items.
+	Total int
+	// PageParam is the url query parameter of the page, defaults to "page".
+	PageParam string
+	// PerPageParam is the url query parameter of the items per page, defaults to "per_page".
+	PerPageParam string
+}
+
+var _ Result = Paginated{}
+
+// LastPage returns the number of the last page.
+func (p Paginated) LastPage() int {
+	if p.PerPage <= 0 || p.Total <= 0 {
+		return 1
+	}
+
+	return (p.Total + p.PerPage - 1) / p.PerPage
+}
+
+// Dispatch writes the items and the pagination headers to the client.
+func (p Paginated) Dispatch(ctx context.Context) {
+	if p.Page < 1 {
+		p.Page = 1
+	}
+
+	if p.PageParam == "" {
+		p.PageParam = "page"
+	}
+
+	if p.PerPageParam == "" {
+		p.PerPageParam = "per_page"
+	}
+
+	last := p.LastPage()
+	links := []struct {
+		rel  string
+		page int
+		ok   bool
+	}{
+		{"first", 1, true},
+		{"prev", p.Page - 1, p.Page > 1},
+		{"next", p.Page + 1, p.Page < last},
+		{"last", last, true},
+	}
+
+	u := *ctx.Request().URL
+	var header []string
+	for _, link := range links {
+		if !link.ok {
+			continue
+		}
+
+		query := u.Query()
+		query.Set(p.PageParam, strconv.Itoa(link.page))
+		if p.PerPage > 0 {
+			query.Set(p.PerPageParam, strconv.Itoa(p.PerPage))
+		}
+
+		u.RawQuery = query.Encode()
+		header = append(header, fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), link.rel))
+	}
+
+	ctx.Header("Link", strings.Join(header, ", "))
+	ctx.Header("X-Total-Count", strconv.Itoa(p.Total))
+
+	items := p.Items
+	if items == nil {
+		items = []interface{}{}
+	}
+
+	DispatchCommon(ctx, http.StatusOK, "", nil, items, nil, true)
+}
+
+// Negotiated returns a `Result` which sends the "v" based on the request's "Accept" header,
+// it can be sent as JSON (the default one), XML, YAML or plain text
+// and a 406 Not Acceptable is sent if none of them is acceptable.
+func Negotiated(v interface{}) Result {
+	return negotiatedResult{v: v}
+}
+
+type negotiatedResult struct {
+	v interface{}
+}
+
+// negotiatedContentTypes are the content types of the `Negotiated` result, by order of preference.
+var negotiatedContentTypes = []string{
+	context.ContentJSONHeaderValue,
+	"application/xml",
+	context.ContentXMLHeaderValue,
+	context.ContentYAMLHeaderValue,
+	context.ContentTextHeaderValue,
+}
+
+func (r negotiatedResult) Dispatch(ctx context.Context) {
+	contentType, ok := negotiateContentType(ctx.GetHeader("Accept"), negotiatedContentTypes)
+	if !ok {
+		ctx.StatusCode(http.StatusNotAcceptable)
+		return
+	}
+
+	var err error
+	switch contentType {
+	case "application/xml", context.ContentXMLHeaderValue:
+		var b []byte
+		if b, err = xml.MarshalIndent(r.v, "", " "); err == nil {
+			ctx.ContentType(contentType)
+			_, err = ctx.Write(b)
+		}
+	case context.ContentYAMLHeaderValue:
+		_, err = ctx.YAML(r.v)
+	case context.ContentTextHeaderValue:
+		ctx.ContentType(contentType)
+		_, err = ctx.WriteString(fmt.Sprint(r.v))
+	default:
+		_, err = ctx.JSON(r.v, context.JSON{Indent: " "})
+	}
+
+	if err != nil {
+		DispatchErr(ctx, http.StatusInternalServerError, err)
+	}
+}
+
+// negotiateContentType returns the first of the "offers" with the highest quality
+// of the "accept" header value, if it's empty then the first offer is returned.
+func negotiateContentType(accept string, offers []string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+
+	type accepted struct {
+		mediaType string
+		q         float64
+	}
+
+	var list []accepted
+	for _, part := range strings.Split(accept, ",") {
+		fields := strings.Split(part, ";")
+		a := accepted{mediaType: strings.ToLower(strings.TrimSpace(fields[0])), q: 1}
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					a.q = q
+				}
+			}
+		}
+
+		if a.mediaType != "" && a.q > 0 {
+			list = append(list, a)
+		}
+	}
+
+	// the more specific ones win on equal quality, i.e "text/plain" against "*/*".
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].q != list[j].q {
+			return list[i].q > list[j].q
+		}
+
+		return strings.Count(list[i].mediaType, "*") < strings.Count(list[j].mediaType, "*")
+	})
+
+	for _, a := range list {
+		for _, offer := range offers {
+			if a.mediaType == offer || a.mediaType == "*/*" ||
+				(strings.HasSuffix(a.mediaType, "/*") && strings.HasPrefix(offer, a.mediaType[:len(a.mediaType)-1])) {
+				return offer, true
+			}
+		}
+	}
+
+	return "", false
+}
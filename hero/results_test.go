@@ -0,0 +1,161 @@
+package hero_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/httptest"
+
+	. "github.com/hidevopsio/iris/hero"
+)
+
+func TestResults(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hero-results")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "hello.txt")
+	if err = ioutil.WriteFile(filename, []byte("hello file"), os.FileMode(0644)); err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	h := New()
+
+	app.Post("/created", h.Handler(func() Result {
+		return Created("/users/42", testCustomStruct{"Iris", 2})
+	}))
+	app.Delete("/no-content", h.Handler(NoContent))
+	app.Post("/redirect", h.Handler(func() Result {
+		return Redirect("/target")
+	}))
+	app.Post("/redirect/temporary", h.Handler(func() Result {
+		return Redirect("/target", iris.StatusTemporaryRedirect)
+	}))
+	app.Any("/target", func(ctx iris.Context) {
+		ctx.WriteString(ctx.Method())
+	})
+	app.Get("/problem", h.Handler(func() Result {
+		return Problem{
+			Type:       "https://example.com/probs/out-of-credit",
+			Status:     iris.StatusForbidden,
+			Detail:     "Your current balance is 30, but that costs 50.",
+			Extensions: map[string]interface{}{"balance": 30},
+		}
+	}))
+	app.Get("/problem/as/error", h.Handler(func() (testCustomStruct, error) {
+		return testCustomStruct{}, Problem{Status: iris.StatusConflict}
+	}))
+	app.Get("/file", h.Handler(func() Result {
+		return File(filename)
+	}))
+	app.Get("/file/attachment", h.Handler(func() Result {
+		return File(filename, "download.txt")
+	}))
+	app.Get("/file/missing", h.Handler(func() Result {
+		return File(filepath.Join(dir, "missing.txt"))
+	}))
+	app.Get("/stream", h.Handler(func() Result {
+		return Stream("text/csv", strings.NewReader("a,b\n1,2\n"))
+	}))
+	app.Get("/users", h.Handler(func(ctx iris.Context) Paginated {
+		page, _ := ctx.URLParamInt("page")
+		return Paginated{Items: []string{"user"}, Page: page, PerPage: 10, Total: 35}
+	}))
+	app.Get("/negotiated", h.Handler(func() Result {
+		return Negotiated(testCustomStruct{"Iris", 2})
+	}))
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+
+	e.POST("/created").Expect().Status(iris.StatusCreated).
+		Header("Location").Equal("/users/42")
+	e.POST("/created").Expect().JSON().Equal(testCustomStruct{"Iris", 2})
+	e.DELETE("/no-content").Expect().Status(iris.StatusNoContent).Body().Empty()
+
+	// the client follows the 303 redirect with a GET request.
+	e.POST("/redirect").Expect().Status(iris.StatusOK).Body().Equal("GET")
+	e.POST("/redirect/temporary").Expect().Status(iris.StatusTemporaryRedirect).
+		Header("Location").Equal("/target")
+
+	body := e.GET("/problem").Expect().Status(iris.StatusForbidden).
+		ContentType(ProblemContentType).Body().Raw()
+	expectProblem(t, body, map[string]interface{}{
+		"type":    "https://example.com/probs/out-of-credit",
+		"title":   "Forbidden",
+		"status":  float64(iris.StatusForbidden),
+		"detail":  "Your current balance is 30, but that costs 50.",
+		"balance": float64(30),
+	})
+
+	body = e.GET("/problem/as/error").Expect().Status(iris.StatusConflict).
+		ContentType(ProblemContentType).Body().Raw()
+	expectProblem(t, body, map[string]interface{}{
+		"type":   "about:blank",
+		"title":  "Conflict",
+		"status": float64(iris.StatusConflict),
+	})
+
+	e.GET("/file").Expect().Status(iris.StatusOK).Body().Equal("hello file")
+	e.GET("/file/attachment").Expect().Status(iris.StatusOK).
+		Header("Content-Disposition").Equal("attachment;filename=download.txt")
+	e.GET("/file/missing").Expect().Status(iris.StatusNotFound)
+	e.GET("/stream").Expect().Status(iris.StatusOK).
+		ContentType("text/csv").Body().Equal("a,b\n1,2\n")
+
+	users := e.GET("/users").WithQuery("page", 2).Expect().Status(iris.StatusOK)
+	users.Header("X-Total-Count").Equal("35")
+	users.Header("Link").Equal(`</users?page=1&per_page=10>; rel="first", </users?page=1&per_page=10>; rel="prev", ` +
+		`</users?page=3&per_page=10>; rel="next", </users?page=4&per_page=10>; rel="last"`)
+	users.JSON().Equal([]string{"user"})
+
+	e.GET("/negotiated").Expect().Status(iris.StatusOK).
+		ContentType("application/json").JSON().Equal(testCustomStruct{"Iris", 2})
+	e.GET("/negotiated").WithHeader("Accept", "application/xml;q=0.9, application/json;q=0.5").Expect().
+		Status(iris.StatusOK).ContentType("application/xml").Body().Contains("<name>Iris</name>")
+	e.GET("/negotiated").WithHeader("Accept", "text/plain, */*;q=0.1").Expect().
+		Status(iris.StatusOK).ContentType("text/plain").Body().Equal("{Iris 2}")
+	e.GET("/negotiated").WithHeader("Accept", "image/png").Expect().
+		Status(iris.StatusNotAcceptable)
+}
+
+func TestProblemDetailsErrors(t *testing.T) {
+	app := iris.New()
+	app.Configure(iris.WithProblemDetails)
+
+	app.Get("/", Handler(func() (string, error) {
+		return "", errors.New("invalid user")
+	}))
+
+	e := httptest.New(t, app)
+	body := e.GET("/").Expect().Status(iris.StatusBadRequest).
+		ContentType(ProblemContentType).Body().Raw()
+	expectProblem(t, body, map[string]interface{}{
+		"type":   "about:blank",
+		"title":  "Bad Request",
+		"status": float64(iris.StatusBadRequest),
+		"detail": "invalid user",
+	})
+}
+
+func expectProblem(t *testing.T, body string, expected map[string]interface{}) {
+	t.Helper()
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("expected problem details but got: %s: %v", body, err)
+	}
+
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected problem details: %v but got: %v", expected, got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"log"
 	"net"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
@@ -122,6 +123,16 @@ const (
 	MethodTrace   = "TRACE"
 )
 
+// The trailing slash modes of a `PathPolicy`.
+const (
+	// TrailingSlashRedirect redirects the client to the path without the trailing slash, the default mode.
+	TrailingSlashRedirect = router.TrailingSlashRedirect
+	// TrailingSlashLenient serves the route as if the path had no trailing slash.
+	TrailingSlashLenient = router.TrailingSlashLenient
+	// TrailingSlashStrict does not correct the path, "/users/" is not the "/users" route.
+	TrailingSlashStrict = router.TrailingSlashStrict
+)
+
 // MethodNone is an iris-specific "virtual" method
 // to store the "offline" routes.
 const MethodNone = "NONE"
@@ -241,6 +252,11 @@ func (app *Application) ConfigurationReadOnly() context.ConfigurationReadOnly {
 	return app.config
 }
 
+// GetContextPool returns the pool of the contexts, see `ContextPool`.
+func (app *Application) GetContextPool() *context.Pool {
+	return app.ContextPool
+}
+
 // Logger returns the golog logger instance(pointer) that is being used inside the "app".
 //
 // Available levels:
@@ -303,6 +319,24 @@ var (
 	Amber = view.Amber
 )
 
+// URL returns the URL of a registered route based on its name,
+// the named parameters' values, which are validated by the route's macro functions, and an optional query.
+// Subdomain routes result to an absolute URL based on the application's virtual host.
+//
+// Example: app.URL("user.show", iris.URLParams{"id": 42}, url.Values{"tab": {"posts"}})
+// will return "/user/42?tab=posts" for a route "/user/{id:int}" named "user.show".
+//
+// The template function "url" does the same for all view engines, i.e {{ url "user.show" "id" 42 "tab" "posts" }}.
+func (app *Application) URL(routeName string, params router.URLParams, query url.Values) (string, error) {
+	return app.routePathReverser().Build(routeName, params, query)
+}
+
+// routePathReverser returns a new reverser based on the current virtual host,
+// it's not known before the application's Run.
+func (app *Application) routePathReverser() *router.RoutePathReverser {
+	return router.NewRoutePathReverser(app.APIBuilder, router.WithHost(app.ConfigurationReadOnly().GetVHost()))
+}
+
 // NoLayout to disable layout for a particular template file
 // A shortcut for the `view#NoLayout`.
 const NoLayout = view.NoLayout
@@ -336,6 +370,30 @@ func (app *Application) View(writer io.Writer, filename string, layout string, b
 	return err
 }
 
+// ViewFragment executes and writes the result of a template file's named block (fragment) to the writer,
+// without its layout, the view engine of the template file should support fragments.
+//
+// First parameter is the writer to write the parsed template.
+// Second parameter is the relative, to templates directory, template filename, including extension.
+// Third parameter is the name of the template's block.
+// Forth parameter is the bindable data to the template, can be nil.
+//
+// Use context.ViewFragment to render template fragments to the client instead.
+// Returns an error on failure, otherwise nil.
+func (app *Application) ViewFragment(writer io.Writer, filename string, block string, bindingData interface{}) error {
+	if app.view.Len() == 0 {
+		err := errors.New("view engine is missing, use `RegisterView`")
+		app.Logger().Error(err)
+		return err
+	}
+
+	err := app.view.ExecuteFragment(writer, filename, block, bindingData)
+	if err != nil {
+		app.Logger().Error(err)
+	}
+	return err
+}
+
 var (
 	// LimitRequestBodySize is a middleware which sets a request body size limit
 	// for all next handlers in the chain.
@@ -348,6 +406,26 @@ var (
 	//
 	// Examples: https://github.com/hidevopsio/iris/tree/master/_examples/file-server
 	StaticEmbeddedHandler = router.StaticEmbeddedHandler
+	// StaticFSHandler returns a Handler which can serve
+	// the files of an `http.FileSystem`.
+	//
+	// A shortcut for the `router#StaticFSHandler`.
+	StaticFSHandler = router.StaticFSHandler
+	// BindataFS returns an `http.FileSystem` of the files
+	// which are embedded with the go-bindata tool.
+	//
+	// A shortcut for the `context#BindataFS`.
+	BindataFS = context.BindataFS
+	// SubFS returns an `http.FileSystem` of a directory of a file system.
+	//
+	// A shortcut for the `context#SubFS`.
+	SubFS = context.SubFS
+	// OverlayFS returns a union `http.FileSystem` of file systems,
+	// the first file system which contains a file shadows the rest,
+	// i.e local files can shadow embedded ones.
+	//
+	// A shortcut for the `context#OverlayFS`.
+	OverlayFS = context.OverlayFS
 	// StripPrefix returns a handler that serves HTTP requests
 	// by removing the given prefix from the request URL's Path
 	// and invoking the handler h. StripPrefix handles a
@@ -365,6 +443,12 @@ var (
 	//
 	// A shortcut for the `context#Gzip`.
 	Gzip = context.Gzip
+	// Timeout is a middleware which runs the next handlers in the chain with a deadline,
+	// the request's std context is canceled and a custom error code is fired when exceeded.
+	// See `Party#Timeout` to set a timeout for a group of routes.
+	//
+	// A shortcut for the `context#Timeout`.
+	Timeout = context.Timeout
 	// FromStd converts native http.Handler, http.HandlerFunc & func(w, r, next) to context.Handler.
 	//
 	// Supported form types:
@@ -571,7 +655,8 @@ func (app *Application) NewHost(srv *http.Server) *host.Supervisor {
 // A shortcut for the `host#RegisterOnInterrupt`.
 var RegisterOnInterrupt = host.RegisterOnInterrupt
 
-// Shutdown gracefully terminates all the application's server hosts.
+// Shutdown gracefully terminates all the application's server hosts
+// and closes the view engines, i.e stops watching their templates, see `view#View.Close`.
 // Returns an error on the first failure, otherwise nil.
 func (app *Application) Shutdown(ctx stdContext.Context) error {
 	for i, su := range app.Hosts {
@@ -581,7 +666,7 @@ func (app *Application) Shutdown(ctx stdContext.Context) error {
 			return err
 		}
 	}
-	return nil
+	return app.view.Close()
 }
 
 // Runner is just an interface which accepts the framework instance
@@ -775,7 +860,9 @@ func (app *Application) Build() error {
 			// Each engine has their defaults, i.e yield,render,render_r,partial, params...
 			rv := router.NewRoutePathReverser(app.APIBuilder)
 			app.view.AddFunc("urlpath", rv.Path)
-			// app.view.AddFunc("url", rv.URL)
+			app.view.AddFunc("url", view.URLFunc(func(routeName string, pairs ...interface{}) string {
+				return app.routePathReverser().Href(routeName, pairs...)
+			}))
 			rp.Describe("view: %v", app.view.Load())
 		}
 	})
@@ -40,6 +40,19 @@ func MakeHandler(tmpl macro.Template) context.Handler {
 
 	return func(ctx context.Context) {
 		for _, p := range tmpl.Params {
+			if p.Optional {
+				if _, found := ctx.Params().Store.GetEntry(p.Name); !found {
+					// the parameter was omitted from the request path,
+					// set its default value, or a placeholder, and continue.
+					if !p.EvalDefault(&ctx.Params().Store) {
+						ctx.StatusCode(p.ErrCode)
+						ctx.StopExecution()
+						return
+					}
+					continue
+				}
+			}
+
 			if !p.CanEval() {
 				continue // allow.
 			}
@@ -21,6 +21,9 @@ func TestCanMakeHandler(t *testing.T) {
 		{"/static/{myparam:int}/static", true},
 		{"/{myparam:path}", false},
 		{"/{myparam:path min(1) else 404}", true},
+		{"/{myparam?}", false},
+		{"/{myparam default(value)}", true},
+		{"/{myparam:int?}", true},
 	}
 
 	availableMacros := *macro.Defaults
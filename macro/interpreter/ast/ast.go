@@ -83,9 +83,13 @@ func GetMasterParamType(paramTypes ...ParamType) ParamType {
 // "uint8"
 // "uint64"
 // "boolean" or "bool"
+// "float64" or "float"
 // "alphabetical"
 // "file"
 // "path"
+// "uuid"
+// "date"
+// "enum"
 func LookupParamType(indentOrAlias string, paramTypes ...ParamType) (ParamType, bool) {
 	for _, pt := range paramTypes {
 		if pt.Indent() == indentOrAlias {
@@ -107,15 +111,18 @@ func LookupParamType(indentOrAlias string, paramTypes ...ParamType) (ParamType,
 // It holds its type (string, int, alphabetical, file, path),
 // its source ({param:type}),
 // its name ("param"),
-// its attached functions by the user (min, max...)
-// and the http error code if that parameter
-// failed to be evaluated.
+// its attached functions by the user (min, max...),
+// the http error code if that parameter
+// failed to be evaluated
+// and if it can be omitted from the request path ({page:int?} or {page:int default(1)}).
 type ParamStatement struct {
-	Src       string      // the original unparsed source, i.e: {id:int range(1,5) else 404}
-	Name      string      // id
-	Type      ParamType   // int
-	Funcs     []ParamFunc // range
-	ErrorCode int         // 404
+	Src          string      // the original unparsed source, i.e: {id:int range(1,5) else 404}
+	Name         string      // id
+	Type         ParamType   // int
+	Funcs        []ParamFunc // range
+	ErrorCode    int         // 404
+	Optional     bool        // true if {id:int?} or {id:int default(1)}
+	DefaultValue string      // "1" if {id:int default(1)}
 }
 
 // ParamFunc holds the name of a parameter's function
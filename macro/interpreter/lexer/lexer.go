@@ -63,6 +63,8 @@ func resolveTokenType(ch byte) token.Type {
 		return token.RPAREN
 	case ',':
 		return token.COMMA
+	case '?':
+		return token.QUESTION
 		// literals
 	case 0:
 		return token.EOF
@@ -110,27 +112,67 @@ func (l *Lexer) NextToken() (t token.Token) {
 // NextDynamicToken doesn't cares about the grammar.
 // It reads numbers or any unknown symbol,
 // it's being used by parser to skip all characters
-// between parameter function's arguments inside parenthesis,
+// of a parameter function's argument inside parenthesis,
 // in order to allow custom regexp on the end-language too.
 //
 // It moves the cursor forward.
 func (l *Lexer) NextDynamicToken() (t token.Token) {
 	// calculate anything, even spaces.
+	pos := l.pos
 
-	// numbers
+	// numbers, only if they are followed by the next argument or the end of the function,
+	// otherwise they are part of a bigger argument, i.e a date layout: 2006-01-02.
 	lit := l.readNumber()
-	if lit != "" {
+	if lit != "" && l.ch == '.' && l.readPos < len(l.input) && isDigit(l.input[l.readPos]) {
+		// decimals, i.e range(1.5,10).
+		l.readChar()
+		l.readNumber()
+		lit = l.input[pos:l.pos]
+	}
+
+	if lit != "" && (resolveTokenType(l.ch) == token.COMMA || resolveTokenType(l.ch) == token.RPAREN) {
 		return l.newToken(token.INT, lit)
 	}
 
-	lit = l.readIdentifierFuncArgument()
-	return l.newToken(token.IDENT, lit)
+	l.readIdentifierFuncArgument()
+	return l.newToken(token.IDENT, l.input[pos:l.pos])
+}
+
+// NextArgumentToken returns the next argument of a parameter function, after a comma,
+// i.e the green of enum(red,green,blue).
+// It reads the argument like the `NextDynamicToken`, without its leading spaces.
+//
+// It moves the cursor forward.
+func (l *Lexer) NextArgumentToken() token.Token {
+	l.skipWhitespace()
+	return l.NextDynamicToken()
 }
 
 // used to skip any illegal token if inside parenthesis, used to be able to set custom regexp inside a func.
+// It reads until the next argument or the end of the function,
+// the commas and parenthesis inside brackets, braces or parenthesis are part of the argument,
+// i.e the {1,3} of regexp(^[a-z]{1,3}$).
 func (l *Lexer) readIdentifierFuncArgument() string {
 	pos := l.pos
-	for resolveTokenType(l.ch) != token.RPAREN {
+	depth := 0
+	for l.ch != 0 {
+		switch l.ch {
+		case '(', '[', Begin:
+			depth++
+		case ']', End:
+			if depth > 0 {
+				depth--
+			}
+		case ')':
+			if depth == 0 {
+				return l.input[pos:l.pos]
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				return l.input[pos:l.pos]
+			}
+		}
 		l.readChar()
 	}
 
@@ -48,6 +48,38 @@ func TestNextToken(t *testing.T) {
 	}
 }
 
+func TestNextTokenOptional(t *testing.T) {
+	input := `{page:int?}`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.LBRACE, "{"},   // 0
+		{token.IDENT, "page"}, // 1
+		{token.COLON, ":"},    // 2
+		{token.IDENT, "int"},  // 3
+		{token.QUESTION, "?"}, // 4
+		{token.RBRACE, "}"},   // 5
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
 // EMEINA STO:
 // 30/232 selida apto making a interpeter in Go.
 // den ekana to skipWhitespaces giati skeftomai
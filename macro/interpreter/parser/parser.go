@@ -21,6 +21,7 @@ func Parse(fullpath string, paramTypes []ast.ParamType) ([]*ast.ParamStatement,
 	pathParts := strings.SplitN(fullpath, "/", -1)
 	p := new(ParamParser)
 	statements := make([]*ast.ParamStatement, 0)
+	var lastOptional *ast.ParamStatement
 	for i, s := range pathParts {
 		if s == "" { // if starts with /
 			continue
@@ -28,6 +29,9 @@ func Parse(fullpath string, paramTypes []ast.ParamType) ([]*ast.ParamStatement,
 
 		// if it's not a named path parameter of the new syntax then continue to the next
 		if s[0] != lexer.Begin || s[len(s)-1] != lexer.End {
+			if lastOptional != nil {
+				return nil, fmt.Errorf("%s: optional parameter \"%s\" can be followed only by other optional parameters", lastOptional.Src, lastOptional.Name)
+			}
 			continue
 		}
 
@@ -42,6 +46,14 @@ func Parse(fullpath string, paramTypes []ast.ParamType) ([]*ast.ParamStatement,
 			return nil, fmt.Errorf("%s: parameter type \"%s\" should be registered to the very last of a path", s, stmt.Type.Indent())
 		}
 
+		// optional parameters can only be registered at the end of a path,
+		// so the path can be matched with or without them, i.e /archive/{year:int?}/{month:int?}.
+		if stmt.Optional {
+			lastOptional = stmt
+		} else if lastOptional != nil {
+			return nil, fmt.Errorf("%s: optional parameter \"%s\" can be followed only by other optional parameters", lastOptional.Src, lastOptional.Name)
+		}
+
 		statements = append(statements, stmt)
 	}
 
@@ -81,6 +93,11 @@ const (
 	// per-parameter. An error code can be setted via
 	// the "else" keyword inside a route's path.
 	DefaultParamErrorCode = 404
+	// DefaultValueFuncName is the name of the special parameter function
+	// which marks a parameter as optional and sets the value
+	// that should be used when the parameter is omitted from the request path,
+	// i.e {page:int default(1)}.
+	DefaultValueFuncName = "default"
 )
 
 // func parseParamFuncArg(t token.Token) (a ast.ParamFuncArg, err error) {
@@ -143,13 +160,19 @@ func (p *ParamParser) Parse(paramTypes []ast.ParamType) (*ast.ParamStatement, er
 		case token.IDENT:
 			lastParamFunc.Name = t.Literal
 		case token.LPAREN:
+			if lastParamFunc.Name == "" && stmt.Type != nil {
+				// arguments of the parameter type itself,
+				// i.e {birth:date(2006-01-02)} or {color:enum(red,green,blue)},
+				// they're passed to the type's function with the same name.
+				lastParamFunc.Name = stmt.Type.Indent()
+			}
 			// param function without arguments ()
 			if l.PeekNextTokenType() == token.RPAREN {
 				// do nothing, just continue to the RPAREN
 				continue
 			}
 
-			argValTok := l.NextDynamicToken() // catch anything from "(" and forward, until ")", because we need to
+			argValTok := l.NextDynamicToken() // catch anything from "(" and forward, until "," or ")", because we need to
 			// be able to use regex expression as a macro type's func argument too.
 
 			// fmt.Printf("argValTok: %#v\n", argValTok)
@@ -157,11 +180,22 @@ func (p *ParamParser) Parse(paramTypes []ast.ParamType) (*ast.ParamStatement, er
 			lastParamFunc.Args = append(lastParamFunc.Args, argValTok.Literal)
 
 		case token.COMMA:
-			argValTok := l.NextToken()
+			argValTok := l.NextArgumentToken()
 			lastParamFunc.Args = append(lastParamFunc.Args, argValTok.Literal)
 		case token.RPAREN:
-			stmt.Funcs = append(stmt.Funcs, lastParamFunc)
+			if lastParamFunc.Name == DefaultValueFuncName {
+				if len(lastParamFunc.Args) > 1 {
+					p.appendErr("[%d:%d] expected one argument for %s but got %d", t.Start, t.End, DefaultValueFuncName, len(lastParamFunc.Args))
+				} else if len(lastParamFunc.Args) == 1 {
+					stmt.DefaultValue = lastParamFunc.Args[0]
+				}
+				stmt.Optional = true
+			} else {
+				stmt.Funcs = append(stmt.Funcs, lastParamFunc)
+			}
 			lastParamFunc = ast.ParamFunc{} // reset
+		case token.QUESTION:
+			stmt.Optional = true
 		case token.ELSE:
 			errCodeTok := l.NextToken()
 			if errCodeTok.Type != token.INT {
@@ -38,6 +38,9 @@ var (
 	paramTypeAlphabetical = simpleParamType("alphabetical")
 	paramTypeFile         = simpleParamType("file")
 	paramTypePath         = wildcardParamType("path")
+	paramTypeDate         = simpleParamType("date")
+	paramTypeEnum         = simpleParamType("enum")
+	paramTypeFloat64      = simpleParamType("float64")
 )
 
 var testParamTypes = []ast.ParamType{
@@ -45,6 +48,7 @@ var testParamTypes = []ast.ParamType{
 	paramTypeNumber, paramTypeInt64, paramTypeUint8, paramTypeUint64,
 	paramTypeBool,
 	paramTypeAlphabetical, paramTypeFile, paramTypePath,
+	paramTypeDate, paramTypeEnum, paramTypeFloat64,
 }
 
 func TestParseParamError(t *testing.T) {
@@ -208,7 +212,100 @@ func TestParseParam(t *testing.T) {
 				Type:      mustLookupParamType("bool"),
 				ErrorCode: 404,
 			}}, // 12
-
+		{true,
+			ast.ParamStatement{
+				Src:       "{page:int?}",
+				Name:      "page",
+				Type:      mustLookupParamType("number"),
+				ErrorCode: 404,
+				Optional:  true,
+			}}, // 13
+		{true,
+			ast.ParamStatement{
+				Src:          "{page:int min(1) default(1)}",
+				Name:         "page",
+				Type:         mustLookupParamType("number"),
+				Funcs:        []ast.ParamFunc{{Name: "min", Args: []string{"1"}}},
+				ErrorCode:    404,
+				Optional:     true,
+				DefaultValue: "1",
+			}}, // 14
+		{true,
+			ast.ParamStatement{
+				Src:       "{name?}",
+				Name:      "name",
+				Type:      mustLookupParamType("string"),
+				ErrorCode: 404,
+				Optional:  true,
+			}}, // 15
+		{false,
+			ast.ParamStatement{
+				Src:       "{page:int default(1,2)}",
+				Name:      "page",
+				Type:      mustLookupParamType("number"),
+				ErrorCode: 404,
+				Optional:  true,
+			}}, // 16
+		{true,
+			ast.ParamStatement{
+				Src:  "{birth:date(2006-01-02) else 400}", // type arguments.
+				Name: "birth",
+				Type: mustLookupParamType("date"),
+				Funcs: []ast.ParamFunc{
+					{
+						Name: "date",
+						Args: []string{"2006-01-02"}},
+				},
+				ErrorCode: 400,
+			}}, // 17
+		{true,
+			ast.ParamStatement{
+				Src:  "{color:enum(red,green,blue)}",
+				Name: "color",
+				Type: mustLookupParamType("enum"),
+				Funcs: []ast.ParamFunc{
+					{
+						Name: "enum",
+						Args: []string{"red", "green", "blue"}},
+				},
+				ErrorCode: 404,
+			}}, // 18
+		{true,
+			ast.ParamStatement{
+				Src:  "{x:float64 range(1.5,10.5)}", // decimal arguments.
+				Name: "x",
+				Type: mustLookupParamType("float64"),
+				Funcs: []ast.ParamFunc{
+					{
+						Name: "range",
+						Args: []string{"1.5", "10.5"}},
+				},
+				ErrorCode: 404,
+			}}, // 19
+		{true,
+			ast.ParamStatement{
+				Src:  "{x:float64 range(1, 10.5) else 400}",
+				Name: "x",
+				Type: mustLookupParamType("float64"),
+				Funcs: []ast.ParamFunc{
+					{
+						Name: "range",
+						Args: []string{"1", "10.5"}},
+				},
+				ErrorCode: 400,
+			}}, // 20
+		{true,
+			ast.ParamStatement{
+				Src:  "{name:string regexp(^[a-z]{1,3}$)}", // commas inside the argument.
+				Name: "name",
+				Type: mustLookupParamType("string"),
+				Funcs: []ast.ParamFunc{
+					{
+						Name: "regexp",
+						Args: []string{"^[a-z]{1,3}$"}},
+				},
+				ErrorCode: 404,
+			}}, // 21
 	}
 
 	p := new(ParamParser)
@@ -319,6 +416,34 @@ func TestParse(t *testing.T) {
 				ErrorCode: 404,
 			},
 			}}, // 7
+		{"/list/{page:int?}", true,
+			[]ast.ParamStatement{{
+				Src:       "{page:int?}",
+				Name:      "page",
+				Type:      paramTypeNumber,
+				ErrorCode: 404,
+				Optional:  true,
+			},
+			}}, // 8
+		{"/list/{page:int?}/invalid", false, // optional should be in the end segments
+			[]ast.ParamStatement{{
+				Src:       "{page:int?}",
+				Name:      "page",
+				Type:      paramTypeNumber,
+				ErrorCode: 404,
+				Optional:  true,
+			},
+			}}, // 9
+		{"/list/{page:int default(1)}/{id:int}", false, // optional should be followed only by optional parameters
+			[]ast.ParamStatement{{
+				Src:          "{page:int default(1)}",
+				Name:         "page",
+				Type:         paramTypeNumber,
+				ErrorCode:    404,
+				Optional:     true,
+				DefaultValue: "1",
+			},
+			}}, // 10
 	}
 	for i, tt := range tests {
 		statements, err := Parse(tt.path, testParamTypes)
@@ -16,6 +16,8 @@ type Token struct {
 // {id:uint64 range(1,5) else 404}
 // /admin/{id:int eq(1) else 402}
 // /file/{filepath:file else 405}
+// /list/{page:int?}
+// /list/{page:int default(1)}
 const (
 	EOF = iota // 0
 	ILLEGAL
@@ -29,7 +31,8 @@ const (
 	RPAREN // )
 	//	PARAM_FUNC_ARG   // 1
 	COMMA
-	IDENT // string or keyword
+	QUESTION // ?
+	IDENT    // string or keyword
 	// Keywords
 	// keywords_start
 	ELSE // else
@@ -107,12 +107,36 @@ func convertBuilderFunc(fn interface{}) ParamFuncBuilder {
 	}
 
 	numFields := typFn.NumIn()
+	variadic := typFn.IsVariadic()
+	if variadic {
+		numFields--
+	}
 
 	return func(args []string) reflect.Value {
-		if len(args) != numFields {
-			// no variadics support, for now.
+		var variadicValues []reflect.Value
+		if variadic {
+			// only string variadics are supported, i.e enum(a,b,c) -> func(options ...string).
+			if len(args) < numFields {
+				panic("args should be at least the same len as numFields")
+			}
+
+			if typFn.In(numFields).Elem().Kind() != reflect.String {
+				panic("only string variadic input arguments are supported")
+			}
+
+			for _, arg := range args[numFields:] {
+				variadicValues = append(variadicValues, reflect.ValueOf(strings.TrimSpace(arg)))
+			}
+
+			args = args[:numFields]
+		} else if len(args) > numFields && numFields > 0 && typFn.In(numFields-1).Kind() == reflect.String {
+			// the commas of the last string argument are part of it,
+			// i.e the regexp(^a,b$) -> func(expr string).
+			args = append(args[:numFields-1], strings.Join(args[numFields-1:], ","))
+		} else if len(args) != numFields {
 			panic("args should be the same len as numFields")
 		}
+
 		var argValues []reflect.Value
 		for i := 0; i < numFields; i++ {
 			field := typFn.In(i)
@@ -203,6 +227,7 @@ func convertBuilderFunc(fn interface{}) ParamFuncBuilder {
 			argValues = append(argValues, argValue)
 		}
 
+		argValues = append(argValues, variadicValues...)
 		evalFn := reflect.ValueOf(fn).Call(argValues)[0]
 
 		// var evaluator EvaluatorFunc
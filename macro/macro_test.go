@@ -4,6 +4,8 @@ import (
 	"reflect"
 	"strconv"
 	"testing"
+
+	"github.com/hidevopsio/iris/core/memstore"
 )
 
 // Most important tests to look:
@@ -419,6 +421,168 @@ func TestPathEvaluatorRaw(t *testing.T) {
 	}
 }
 
+func TestFloat64EvaluatorRaw(t *testing.T) {
+	tests := []struct {
+		pass  bool
+		input string
+	}{
+		{false, "astring"},          // 0
+		{true, "32321"},             // 1
+		{true, "-42.5"},             // 2
+		{true, "0.000001"},          // 3
+		{false, "1."},               // 4
+		{false, "1e10"},             // 5
+		{false, "main.css"},         // 6
+		{false, "/assets/main.css"}, // 7
+	}
+
+	for i, tt := range tests {
+		testEvaluatorRaw(t, Float64, tt.input, reflect.Float64, tt.pass, i)
+		testEvaluatorRaw(t, Float32, tt.input, reflect.Float32, tt.pass, i)
+	}
+}
+
+func TestUUIDEvaluatorRaw(t *testing.T) {
+	tests := []struct {
+		pass  bool
+		input string
+	}{
+		{true, "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},   // 0
+		{true, "6BA7B810-9DAD-11D1-80B4-00C04FD430C8"},   // 1
+		{false, "6ba7b8109dad11d180b400c04fd430c8"},      // 2
+		{false, "6ba7b810-9dad-11d1-80b4-00c04fd430c"},   // 3
+		{false, "6ba7b810-9dad-11d1-80b4-00c04fd430cz"},  // 4
+		{false, "6ba7b810-9dad-11d1-80b4-00c04fd430c8a"}, // 5
+	}
+
+	for i, tt := range tests {
+		testEvaluatorRaw(t, UUID, tt.input, reflect.String, tt.pass, i)
+	}
+}
+
+func TestDateEvaluatorRaw(t *testing.T) {
+	tests := []struct {
+		pass  bool
+		input string
+	}{
+		{true, "2018-10-21"},  // 0
+		{false, "2018-13-21"}, // 1
+		{false, "21-10-2018"}, // 2
+		{false, "astring"},    // 3
+	}
+
+	for i, tt := range tests {
+		testEvaluatorRaw(t, Date, tt.input, reflect.Struct, tt.pass, i)
+	}
+}
+
+func TestSlugEvaluatorRaw(t *testing.T) {
+	tests := []struct {
+		pass  bool
+		input string
+	}{
+		{true, "my-first-post"},   // 0
+		{true, "post2018"},        // 1
+		{false, "My-First-Post"},  // 2
+		{false, "my--first-post"}, // 3
+		{false, "-my-first-post"}, // 4
+		{false, "my_first_post"},  // 5
+	}
+
+	for i, tt := range tests {
+		testEvaluatorRaw(t, Slug, tt.input, reflect.String, tt.pass, i)
+	}
+}
+
+func TestEmailEvaluatorRaw(t *testing.T) {
+	tests := []struct {
+		pass  bool
+		input string
+	}{
+		{true, "user@example.com"},       // 0
+		{true, "first.last+tag@mail.io"}, // 1
+		{false, "user@example"},          // 2
+		{false, "user.example.com"},      // 3
+		{false, "user@@example.com"},     // 4
+		{false, "user@-example.com"},     // 5
+		{false, "user name@example.com"}, // 6
+	}
+
+	for i, tt := range tests {
+		testEvaluatorRaw(t, Email, tt.input, reflect.String, tt.pass, i)
+	}
+}
+
+func TestParseTypeArguments(t *testing.T) {
+	tests := []struct {
+		src          string
+		input        string
+		pass         bool
+		expectedType reflect.Kind
+	}{
+		{"/{color:enum(red,green,blue)}", "green", true, reflect.String},       // 0
+		{"/{color:enum(red,green,blue)}", "yellow", false, reflect.String},     // 1
+		{"/{color:enum}", "red", false, reflect.String},                        // 2
+		{"/{birth:date(02-01-2006)}", "21-10-2018", true, reflect.Struct},      // 3
+		{"/{birth:date(02-01-2006)}", "2018-10-21", false, reflect.Struct},     // 4
+		{"/{birth:date min(2018-01-01)}", "2017-12-31", false, reflect.Struct}, // 5
+		{"/{birth:date min(2018-01-01)}", "2018-10-21", true, reflect.Struct},  // 6
+		{"/{price:float64 range(1.5,10)}", "1.4", false, reflect.Float64},      // 7
+		{"/{price:float64 range(1.5,10)}", "9.99", true, reflect.Float64},      // 8
+		{"/{color:enum(red, green , blue)}", "green", true, reflect.String},    // 9
+	}
+
+	for i, tt := range tests {
+		tmpl, err := Parse(tt.src, *Defaults)
+		if err != nil {
+			t.Fatalf("tests[%d] - '%s' failed to be parsed: %v", i, tt.src, err)
+		}
+
+		p := tmpl.Params[0]
+		store := new(memstore.Store)
+		if passed := p.Eval(tt.input, store); passed != tt.pass {
+			t.Fatalf("tests[%d] - '%s' expecting[pass] %v but got %v for input: %s", i, tt.src, tt.pass, passed, tt.input)
+		}
+
+		if !tt.pass {
+			continue
+		}
+
+		if v := reflect.ValueOf(store.Get(p.Name)); v.Kind() != tt.expectedType {
+			t.Fatalf("tests[%d] - expecting[value.Kind] %v but got %v", i, tt.expectedType, v.Kind())
+		}
+	}
+}
+
+func TestParseRegexpArgument(t *testing.T) {
+	// the commas of the regexp are part of its argument.
+	tmpl, err := Parse("/{code:string regexp(^[a-z]{1,3},[0-9]$)}", *Defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := tmpl.Params[0]
+	for input, pass := range map[string]bool{"ab,1": true, "abcd,1": false, "ab": false} {
+		if passed := p.Eval(input, new(memstore.Store)); passed != pass {
+			t.Fatalf("expecting[pass] %v but got %v for input: %s", pass, passed, input)
+		}
+	}
+}
+
+func TestParseInvalidArguments(t *testing.T) {
+	tests := []string{
+		"/{birth:date min(tomorrow)}",
+		"/{birth:date max(2018-13-01)}",
+		"/{page:int min(first)}",
+	}
+
+	for i, src := range tests {
+		if _, err := Parse(src, *Defaults); err == nil {
+			t.Fatalf("tests[%d] - '%s' expected to fail because of an invalid argument", i, src)
+		}
+	}
+}
+
 func TestConvertBuilderFunc(t *testing.T) {
 	fn := func(min uint64, slice []string) func(string) bool {
 		return func(paramValue string) bool {
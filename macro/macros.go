@@ -3,6 +3,7 @@ package macro
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/hidevopsio/iris/macro/interpreter/ast"
 )
@@ -356,6 +357,71 @@ var (
 			}
 		})
 
+	simpleFloatEval = MustRegexp("^-?[0-9]+(\\.[0-9]+)?$")
+	// Float32 as float32 type
+	// -3.4e+38 to 3.4e+38, i.e 1.5 or -42.
+	Float32 = NewMacro("float32", "", false, false, func(paramValue string) (interface{}, bool) {
+		if !simpleFloatEval(paramValue) {
+			return nil, false
+		}
+
+		v, err := strconv.ParseFloat(paramValue, 32)
+		if err != nil {
+			return nil, false
+		}
+		return float32(v), true
+	}).
+		RegisterFunc("min", func(min float32) func(float32) bool {
+			return func(paramValue float32) bool {
+				return paramValue >= min
+			}
+		}).
+		RegisterFunc("max", func(max float32) func(float32) bool {
+			return func(paramValue float32) bool {
+				return paramValue <= max
+			}
+		}).
+		RegisterFunc("range", func(min, max float32) func(float32) bool {
+			return func(paramValue float32) bool {
+				return !(paramValue < min || paramValue > max)
+			}
+		})
+
+	// Float64 or float as float64 type
+	// -1.7e+308 to 1.7e+308, i.e 1.5 or -42.
+	Float64 = NewMacro("float64", "float", false, false, func(paramValue string) (interface{}, bool) {
+		if !simpleFloatEval(paramValue) {
+			return nil, false
+		}
+
+		v, err := strconv.ParseFloat(paramValue, 64)
+		if err != nil {
+			return nil, false
+		}
+		return v, true
+	}).
+		// checks if the param value's float64 representation is
+		// bigger or equal than 'min'.
+		RegisterFunc("min", func(min float64) func(float64) bool {
+			return func(paramValue float64) bool {
+				return paramValue >= min
+			}
+		}).
+		// checks if the param value's float64 representation is
+		// smaller or equal than 'max'.
+		RegisterFunc("max", func(max float64) func(float64) bool {
+			return func(paramValue float64) bool {
+				return paramValue <= max
+			}
+		}).
+		// checks if the param value's float64 representation is
+		// between min and max, including 'min' and 'max'.
+		RegisterFunc("range", func(min, max float64) func(float64) bool {
+			return func(paramValue float64) bool {
+				return !(paramValue < min || paramValue > max)
+			}
+		})
+
 	// Bool or boolean as bool type
 	// a string which is "1" or "t" or "T" or "TRUE" or "true" or "True"
 	// or "0" or "f" or "F" or "FALSE" or "false" or "False".
@@ -402,6 +468,79 @@ var (
 	// Should be living in the latest path segment of a route path.
 	Path = NewMacro("path", "", false, true, nil)
 
+	uuidEval = MustRegexp("^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$")
+	// UUID type
+	// a canonical, 36 characters long, universally unique identifier,
+	// i.e 6ba7b810-9dad-11d1-80b4-00c04fd430c8.
+	UUID = NewMacro("uuid", "", false, false, func(paramValue string) (interface{}, bool) {
+		if !uuidEval(paramValue) {
+			return nil, false
+		}
+		return paramValue, true
+	})
+
+	// Date type
+	// a date as time.Time type, parsed by the `DefaultDateLayout` "2006-01-02"
+	// or by the layout given as the type's argument, i.e {birth:date(02-01-2006)}.
+	Date = NewMacro("date", "", false, false, dateEvaluator(DefaultDateLayout)).
+		RegisterFunc("date", func(layout string) ParamEvaluator {
+			return dateEvaluator(layout)
+		}).
+		// checks if the param value's time.Time representation is
+		// after or equal than 'min', which is expressed in the `DefaultDateLayout`.
+		RegisterFunc("min", func(min string) func(time.Time) bool {
+			minDate := mustParseDate(min)
+			return func(paramValue time.Time) bool {
+				return !paramValue.Before(minDate)
+			}
+		}).
+		// checks if the param value's time.Time representation is
+		// before or equal than 'max', which is expressed in the `DefaultDateLayout`.
+		RegisterFunc("max", func(max string) func(time.Time) bool {
+			maxDate := mustParseDate(max)
+			return func(paramValue time.Time) bool {
+				return !paramValue.After(maxDate)
+			}
+		})
+
+	slugEval = MustRegexp("^[a-z0-9]+(-[a-z0-9]+)*$")
+	// Slug type
+	// lowercase letters and numbers separated by single dashes,
+	// i.e my-first-post-2018.
+	Slug = NewMacro("slug", "", false, false, func(paramValue string) (interface{}, bool) {
+		if !slugEval(paramValue) {
+			return nil, false
+		}
+		return paramValue, true
+	})
+
+	emailEval = MustRegexp("^[a-zA-Z0-9.!#$%&'*+=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)+$")
+	// Email type
+	// a valid e-mail address, i.e user@example.com.
+	Email = NewMacro("email", "", false, false, func(paramValue string) (interface{}, bool) {
+		if !emailEval(paramValue) {
+			return nil, false
+		}
+		return paramValue, true
+	})
+
+	// Enum type
+	// one of the values given as the type's arguments, i.e {color:enum(red,green,blue)}.
+	// Without arguments, it does not accept any value.
+	Enum = NewMacro("enum", "", false, false, func(string) (interface{}, bool) {
+		return nil, false
+	}).
+		RegisterFunc("enum", func(options ...string) ParamEvaluator {
+			return func(paramValue string) (interface{}, bool) {
+				for _, opt := range options {
+					if opt == paramValue {
+						return paramValue, true
+					}
+				}
+				return nil, false
+			}
+		})
+
 	// Defaults contains the defaults macro and parameters types for the router.
 	//
 	// Read https://github.com/hidevopsio/iris/tree/master/_examples/routing/macros for more details.
@@ -417,12 +556,41 @@ var (
 		Uint16,
 		Uint32,
 		Uint64,
+		Float32,
+		Float64,
 		Bool,
 		Alphabetical,
 		Path,
+		UUID,
+		Date,
+		Slug,
+		Email,
+		Enum,
 	}
 )
 
+// DefaultDateLayout is the layout which the `Date` parameter type
+// uses to parse its values when a custom layout is missing.
+const DefaultDateLayout = "2006-01-02"
+
+func dateEvaluator(layout string) ParamEvaluator {
+	return func(paramValue string) (interface{}, bool) {
+		v, err := time.Parse(layout, paramValue)
+		if err != nil {
+			return nil, false
+		}
+		return v, true
+	}
+}
+
+func mustParseDate(value string) time.Time {
+	v, err := time.Parse(DefaultDateLayout, value)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
 // Macros is just a type of a slice of *Macro
 // which is responsible to register and search for macros based on the indent(parameter type).
 type Macros []*Macro
@@ -1,6 +1,7 @@
 package macro
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/hidevopsio/iris/core/memstore"
@@ -20,6 +21,17 @@ type Template struct {
 	Params []TemplateParam `json:"params"`
 }
 
+// IsParam reports whether the template contains a parameter with the given "name".
+func (t Template) IsParam(name string) bool {
+	for _, p := range t.Params {
+		if p.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 // TemplateParam is the parsed macro parameter's template
 // they are being used to describe the param's syntax result.
 type TemplateParam struct {
@@ -30,6 +42,8 @@ type TemplateParam struct {
 	Name          string          `json:"name"`
 	Index         int             `json:"index"`
 	ErrCode       int             `json:"errCode"`
+	Optional      bool            `json:"optional"`
+	DefaultValue  string          `json:"defaultValue"`
 	TypeEvaluator ParamEvaluator  `json:"-"`
 	Funcs         []reflect.Value `json:"-"`
 
@@ -49,7 +63,7 @@ func (p TemplateParam) preComputed() TemplateParam {
 	// i.e {myparam} or {myparam:string} or {myparam:path} ->
 	// their type evaluator is nil because they don't do any checks and they don't change
 	// the default parameter value's type (string) so no need for any work).
-	p.canEval = p.TypeEvaluator != nil || len(p.Funcs) > 0 || p.ErrCode != parser.DefaultParamErrorCode
+	p.canEval = p.TypeEvaluator != nil || len(p.Funcs) > 0 || p.ErrCode != parser.DefaultParamErrorCode || p.DefaultValue != ""
 
 	return p
 }
@@ -100,6 +114,33 @@ func (p *TemplateParam) Eval(paramValue string, paramSetter memstore.ValueSetter
 	return true
 }
 
+// EvalDefault is called instead of `Eval` when an optional parameter
+// is omitted from the request path.
+// It sets the "DefaultValue", converted to the parameter type's value, if any,
+// otherwise an empty placeholder value so the indexes of the rest parameters stay the same.
+// It returns false if the default value does not pass the parameter type's evaluator and its functions.
+func (p *TemplateParam) EvalDefault(paramSetter memstore.ValueSetter) bool {
+	if p.DefaultValue == "" {
+		// keep a placeholder entry for the omitted parameter,
+		// the parameters are resolved by their index too (see `context.ParamResolvers`).
+		paramSetter.Set(p.Name, "")
+		return true
+	}
+
+	if p.TypeEvaluator == nil {
+		for _, fn := range p.stringInFuncs {
+			if !fn(p.DefaultValue) {
+				return false
+			}
+		}
+
+		paramSetter.Set(p.Name, p.DefaultValue)
+		return true
+	}
+
+	return p.Eval(p.DefaultValue, paramSetter)
+}
+
 // Parse takes a full route path and a macro map (macro map contains the macro types with their registered param functions)
 // and returns a new Template.
 // It builds all the parameter functions for that template
@@ -126,6 +167,8 @@ func Parse(src string, macros Macros) (Template, error) {
 			Name:          p.Name,
 			Index:         idx,
 			ErrCode:       p.ErrorCode,
+			Optional:      p.Optional,
+			DefaultValue:  p.DefaultValue,
 			TypeEvaluator: typEval,
 		}
 
@@ -141,15 +184,51 @@ func Parse(src string, macros Macros) (Template, error) {
 				}
 			}
 
-			evalFn := tmplFn(paramfn.Args)
+			evalFn, err := buildParamFunc(tmplFn, paramfn.Args)
+			if err != nil {
+				return tmpl, fmt.Errorf("%s: invalid arguments of the \"%s\" function for parameter \"%s\": %v", p.Src, paramfn.Name, p.Name, err)
+			}
+
 			if evalFn.IsNil() || !evalFn.IsValid() || evalFn.Kind() != reflect.Func {
 				continue
 			}
+
+			// if the function builds a param evaluator then it replaces the type's one,
+			// i.e {birth:date(2006-01-02)} or {color:enum(red,green,blue)}.
+			switch typEval := evalFn.Interface().(type) {
+			case ParamEvaluator:
+				tmplParam.TypeEvaluator = typEval
+				continue
+			case func(string) (interface{}, bool):
+				tmplParam.TypeEvaluator = typEval
+				continue
+			}
+
 			tmplParam.Funcs = append(tmplParam.Funcs, evalFn)
 		}
 
-		tmpl.Params = append(tmpl.Params, tmplParam.preComputed())
+		tmplParam = tmplParam.preComputed()
+		// check the default value once here, before the server ran, instead of
+		// failing on each request that omits the parameter.
+		if !tmplParam.EvalDefault(new(memstore.Store)) {
+			return tmpl, fmt.Errorf("%s: default value \"%s\" is not valid for parameter \"%s\"", p.Src, p.DefaultValue, p.Name)
+		}
+
+		tmpl.Params = append(tmpl.Params, tmplParam)
 	}
 
 	return tmpl, nil
 }
+
+// buildParamFunc calls the "builder" of a parameter function with its arguments,
+// the builders panic on invalid arguments, i.e the min(tomorrow) of a date,
+// the panic is returned as an error instead.
+func buildParamFunc(builder ParamFuncBuilder, args []string) (evalFn reflect.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	return builder(args), nil
+}
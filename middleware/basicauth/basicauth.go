@@ -4,48 +4,42 @@ package basicauth
 // test file: ../../_examples/authentication/basicauth/main_test.go
 
 import (
-	"encoding/base64"
+	"crypto/sha256"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/hidevopsio/iris"
 	"github.com/hidevopsio/iris/context"
 )
 
-type (
-	encodedUser struct {
-		HeaderValue string
-		Username    string
-		logged      bool
-		expires     time.Time
-	}
-	encodedUsers []encodedUser
-
-	basicAuthMiddleware struct {
-		config Config
-		// these are filled from the config.Users map at the startup
-		auth             encodedUsers
-		realmHeaderValue string
-
-		// The below can be removed but they are here because on the future we may add dynamic options for those two fields,
-		// it is a bit faster to check the b.$bool as well.
-		expireEnabled     bool // if the config.Expires is a valid date, default is disabled.
-		askHandlerEnabled bool // if the config.OnAsk is not nil, defaults to false.
-	}
-)
+type basicAuthMiddleware struct {
+	config           Config
+	realmHeaderValue string
 
-//
+	askHandlerEnabled bool // if the config.OnAsk is not nil, defaults to false.
+
+	mu sync.Mutex
+	// the login time of the users, used by the expiration.
+	logins map[string]time.Time
+	// the sums of the verified hashed passwords and their plain text passwords,
+	// in order to not verify them, i.e with the slow bcrypt, on each request.
+	verified map[[sha256.Size]byte]struct{}
+}
 
 // New accepts basicauth.Config and returns a new Handler
 // which will ask the client for basic auth (username, password),
 // validate that and if valid continues to the next handler, otherwise
 // throws a StatusUnauthorized http error code.
+//
+// The authenticated user is stored to the context, see `GetUser`.
 func New(c Config) context.Handler {
 	config := DefaultConfig()
 	if c.Realm != "" {
 		config.Realm = c.Realm
 	}
 	config.Users = c.Users
+	config.Store = c.Store
 	config.Expires = c.Expires
 	config.OnAsk = c.OnAsk
 
@@ -65,36 +59,93 @@ func Default(users map[string]string) context.Handler {
 }
 
 func (b *basicAuthMiddleware) init() {
-	// pass the encoded users from the user's config's Users value
-	b.auth = make(encodedUsers, 0, len(b.config.Users))
-
-	for k, v := range b.config.Users {
-		fullUser := k + ":" + v
-		header := "Basic " + base64.StdEncoding.EncodeToString([]byte(fullUser))
-		b.auth = append(b.auth, encodedUser{HeaderValue: header, Username: k, logged: false, expires: DefaultExpireTime})
-	}
-
 	// set the auth realm header's value
 	b.realmHeaderValue = "Basic realm=" + strconv.Quote(b.config.Realm)
 
-	b.expireEnabled = b.config.Expires > 0
 	b.askHandlerEnabled = b.config.OnAsk != nil
+	b.logins = make(map[string]time.Time)
+	b.verified = make(map[[sha256.Size]byte]struct{})
 }
 
-func (b *basicAuthMiddleware) findAuth(headerValue string) (auth *encodedUser, found bool) {
-	if len(headerValue) == 0 {
-		return
-	}
+func (b *basicAuthMiddleware) findUser(username, password string) (*User, bool) {
+	if hashed, ok := b.config.Users[username]; ok {
+		// only the passwords of the config.Users can be plain text, for backwards compatibility,
+		// the ones which are not of a supported hash format are compared as they are, i.e "$ecret".
+		if !IsHashSupported(hashed) {
+			if constantTimeEqual(hashed, password) {
+				return &User{Username: username, Password: hashed}, true
+			}
 
-	for _, user := range b.auth {
-		if user.HeaderValue == headerValue {
-			auth = &user
-			found = true
-			break
+			return nil, false
 		}
+
+		if b.verify(hashed, password) {
+			return &User{Username: username, Password: hashed}, true
+		}
+
+		return nil, false
+	}
+
+	if b.config.Store == nil {
+		return nil, false
+	}
+
+	user, ok := b.config.Store.GetUser(username)
+	if !ok || user == nil || !b.verify(user.Password, password) {
+		return nil, false
+	}
+
+	return user, true
+}
+
+func (b *basicAuthMiddleware) verify(hashed, password string) bool {
+	sum := sha256.Sum256([]byte(hashed + "\x00" + password))
+
+	b.mu.Lock()
+	_, ok := b.verified[sum]
+	b.mu.Unlock()
+	if ok {
+		return true
+	}
+
+	if !VerifyPassword(hashed, password) {
+		return false
 	}
 
-	return
+	b.mu.Lock()
+	b.verified[sum] = struct{}{}
+	b.mu.Unlock()
+	return true
+}
+
+// expired reports whether the login of the "user" has expired,
+// the user logins again on its next request.
+func (b *basicAuthMiddleware) expired(user *User) bool {
+	expires := user.Expires
+	if expires <= 0 {
+		expires = b.config.Expires
+	}
+
+	if expires <= 0 {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	loggedAt, logged := b.logins[user.Username]
+	if !logged {
+		b.logins[user.Username] = now
+		return false
+	}
+
+	if now.After(loggedAt.Add(expires)) {
+		delete(b.logins, user.Username)
+		return true
+	}
+
+	return false
 }
 
 func (b *basicAuthMiddleware) askForCredentials(ctx context.Context) {
@@ -107,26 +158,31 @@ func (b *basicAuthMiddleware) askForCredentials(ctx context.Context) {
 
 // Serve the actual middleware
 func (b *basicAuthMiddleware) Serve(ctx context.Context) {
-
-	auth, found := b.findAuth(ctx.GetHeader("Authorization"))
-	if !found {
-		b.askForCredentials(ctx)
-		ctx.StopExecution()
-		return
-		// don't continue to the next handler
-	}
-	// all ok
-	if b.expireEnabled {
-		if auth.logged == false {
-			auth.expires = time.Now().Add(b.config.Expires)
-			auth.logged = true
+	username, password, ok := ctx.Request().BasicAuth()
+	if ok {
+		var user *User
+		if user, ok = b.findUser(username, password); ok && b.expired(user) {
+			ok = false // ask for authentication again
 		}
 
-		if time.Now().After(auth.expires) {
-			b.askForCredentials(ctx) // ask for authentication again
-			ctx.StopExecution()
+		if ok {
+			ctx.Values().Set(userContextKey, user)
+			ctx.Next() // continue
 			return
 		}
 	}
-	ctx.Next() // continue
+
+	b.askForCredentials(ctx)
+	ctx.StopExecution()
+	// don't continue to the next handler
+}
+
+const userContextKey = "iris.basicauth.user"
+
+// GetUser returns the user which was authenticated by the basicauth middleware, nil if not authenticated.
+// It can be registered as a dependency of the hero handlers and the mvc controllers too, i.e
+// hero.Register(basicauth.GetUser) in order to accept a *basicauth.User input argument.
+func GetUser(ctx context.Context) *User {
+	user, _ := ctx.Values().Get(userContextKey).(*User)
+	return user
 }
@@ -16,11 +16,21 @@ var DefaultExpireTime time.Time // 0001-01-01 00:00:00 +0000 UTC
 
 // Config the configs for the basicauth middleware
 type Config struct {
-	// Users a map of login and the value (username/password)
+	// Users a map of login and the value (username/password),
+	// the passwords can be hashed, i.e with bcrypt, see `VerifyPassword`,
+	// the ones which are not of a supported hash format are plain text passwords, see `IsHashSupported`.
 	Users map[string]string
+	// Store is the store of the users which are not in the `Users`,
+	// i.e the users of a database or of an htpasswd file, see `LoadHtpasswd`.
+	// Its passwords should be hashed, the plain text ones are never accepted.
+	//
+	// Defaults to nil.
+	Store UserStore
 	// Realm http://tools.ietf.org/html/rfc2617#section-1.2. Default is "Authorization Required"
 	Realm string
-	// Expires expiration duration, default is 0 never expires
+	// Expires expiration duration, default is 0 never expires.
+	// The users are asked for their credentials again when their login expires,
+	// it can be overridden per user through the `User#Expires`.
 	Expires time.Duration
 
 	// OnAsk fires each time the server asks to the client for credentials in order to gain access and continue to the next handler.
@@ -46,7 +56,7 @@ type Config struct {
 
 // DefaultConfig returns the default configs for the BasicAuth middleware
 func DefaultConfig() Config {
-	return Config{make(map[string]string), DefaultBasicAuthRealm, 0, nil}
+	return Config{make(map[string]string), nil, DefaultBasicAuthRealm, 0, nil}
 }
 
 // User returns the user from context key same as  ctx.Request().BasicAuth().
@@ -0,0 +1,278 @@
+package basicauth
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// VerifyPassword reports whether the "password" matches the "hashed" one,
+// the "hashed" can be:
+//
+// - a bcrypt hash, i.e "$2y$10$..." (htpasswd -B)
+// - an argon2 hash of the PHC string format, i.e "$argon2id$v=19$m=65536,t=3,p=4$salt$hash"
+// - a SHA-256 or SHA-512 crypt hash, i.e "$5$salt$hash" or "$6$rounds=5000$salt$hash" (mkpasswd -m sha-512)
+// - a SHA-1 hash, i.e "{SHA}base64hash" (htpasswd -s)
+//
+// It's false for the rest of the hashes, i.e the MD5 "$apr1$" ones, and for the plain text passwords,
+// see `IsHashSupported`.
+func VerifyPassword(hashed, password string) bool {
+	if !IsHashSupported(hashed) {
+		return false
+	}
+
+	switch {
+	case strings.HasPrefix(hashed, "$2a$"), strings.HasPrefix(hashed, "$2b$"), strings.HasPrefix(hashed, "$2y$"):
+		return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(password)) == nil
+	case strings.HasPrefix(hashed, "$argon2"):
+		return verifyArgon2(hashed, password)
+	case strings.HasPrefix(hashed, "$5$"), strings.HasPrefix(hashed, "$6$"):
+		return verifyShaCrypt(hashed, password)
+	case strings.HasPrefix(hashed, "{SHA}"):
+		sum := sha1.Sum([]byte(password))
+		return constantTimeEqual(hashed[len("{SHA}"):], base64.StdEncoding.EncodeToString(sum[:]))
+	default:
+		return false
+	}
+}
+
+// IsHashSupported reports whether the "hashed" is of a hash format which the `VerifyPassword` supports.
+func IsHashSupported(hashed string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$", "$argon2i$", "$argon2id$", "$5$", "$6$", "{SHA}"} {
+		if strings.HasPrefix(hashed, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func constantTimeEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// verifyArgon2 verifies an argon2i or argon2id hash of the PHC string format.
+func verifyArgon2(hashed, password string) bool {
+	// $argon2id$v=19$m=65536,t=3,p=4$salt$hash
+	parts := strings.Split(hashed, "$")
+	if len(parts) != 6 {
+		return false
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false
+	}
+
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return false
+	}
+
+	var derived []byte
+	switch parts[1] {
+	case "argon2id":
+		derived = argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	case "argon2i":
+		derived = argon2.Key([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	default:
+		return false
+	}
+
+	return subtle.ConstantTimeCompare(derived, key) == 1
+}
+
+const (
+	shaCryptRoundsDefault = 5000
+	shaCryptRoundsMin     = 1000
+	shaCryptRoundsMax     = 999999999
+	shaCryptSaltMax       = 16
+)
+
+// verifyShaCrypt verifies a SHA-256 ("$5$") or SHA-512 ("$6$") crypt hash.
+func verifyShaCrypt(hashed, password string) bool {
+	// $6$rounds=5000$salt$hash or $6$salt$hash
+	parts := strings.Split(hashed, "$")
+	if len(parts) < 4 {
+		return false
+	}
+
+	id, parts := parts[1], parts[2:]
+
+	rounds, customRounds := shaCryptRoundsDefault, false
+	if strings.HasPrefix(parts[0], "rounds=") {
+		n, err := strconv.Atoi(strings.TrimPrefix(parts[0], "rounds="))
+		if err != nil {
+			return false
+		}
+
+		rounds, customRounds, parts = n, true, parts[1:]
+	}
+
+	if len(parts) != 2 {
+		return false
+	}
+
+	return constantTimeEqual(hashed, shaCrypt(id, password, parts[0], rounds, customRounds))
+}
+
+// shaCrypt returns the SHA-256 or SHA-512 crypt hash of the "password",
+// see https://www.akkadia.org/drepper/SHA-crypt.txt.
+func shaCrypt(id, password, salt string, rounds int, customRounds bool) string {
+	var (
+		newHash func() hash.Hash
+		order   [][3]int
+	)
+
+	switch id {
+	case "5":
+		newHash, order = sha256.New, shaCrypt256Order
+	case "6":
+		newHash, order = sha512.New, shaCrypt512Order
+	default:
+		return ""
+	}
+
+	if rounds < shaCryptRoundsMin {
+		rounds = shaCryptRoundsMin
+	} else if rounds > shaCryptRoundsMax {
+		rounds = shaCryptRoundsMax
+	}
+
+	if len(salt) > shaCryptSaltMax {
+		salt = salt[:shaCryptSaltMax]
+	}
+
+	p, s := []byte(password), []byte(salt)
+
+	h := newHash()
+	h.Write(p)
+	h.Write(s)
+	h.Write(p)
+	b := h.Sum(nil)
+
+	h = newHash()
+	h.Write(p)
+	h.Write(s)
+	cnt := len(p)
+	for ; cnt > len(b); cnt -= len(b) {
+		h.Write(b)
+	}
+	h.Write(b[:cnt])
+	for cnt = len(p); cnt > 0; cnt >>= 1 {
+		if cnt&1 != 0 {
+			h.Write(b)
+		} else {
+			h.Write(p)
+		}
+	}
+	a := h.Sum(nil)
+
+	h = newHash()
+	for i := 0; i < len(p); i++ {
+		h.Write(p)
+	}
+	pSeq := repeatBytes(h.Sum(nil), len(p))
+
+	h = newHash()
+	for i := 0; i < 16+int(a[0]); i++ {
+		h.Write(s)
+	}
+	sSeq := repeatBytes(h.Sum(nil), len(s))
+
+	c := a
+	for i := 0; i < rounds; i++ {
+		h = newHash()
+		if i&1 != 0 {
+			h.Write(pSeq)
+		} else {
+			h.Write(c)
+		}
+		if i%3 != 0 {
+			h.Write(sSeq)
+		}
+		if i%7 != 0 {
+			h.Write(pSeq)
+		}
+		if i&1 != 0 {
+			h.Write(c)
+		} else {
+			h.Write(pSeq)
+		}
+		c = h.Sum(nil)
+	}
+
+	var out strings.Builder
+	out.WriteString("$" + id + "$")
+	if customRounds {
+		out.WriteString("rounds=" + strconv.Itoa(rounds) + "$")
+	}
+	out.WriteString(salt + "$")
+
+	for _, o := range order {
+		// the last group of the order has less than three bytes, the missing ones are -1.
+		var w uint
+		n := 4
+		for _, idx := range o {
+			w <<= 8
+			if idx < 0 {
+				n--
+				continue
+			}
+			w |= uint(c[idx])
+		}
+		for ; n > 0; n-- {
+			out.WriteByte(cryptAlphabet[w&0x3f])
+			w >>= 6
+		}
+	}
+
+	return out.String()
+}
+
+const cryptAlphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// the byte order of the encoded SHA-256 and SHA-512 crypt hashes.
+var (
+	shaCrypt256Order = [][3]int{
+		{0, 10, 20}, {21, 1, 11}, {12, 22, 2}, {3, 13, 23}, {24, 4, 14},
+		{15, 25, 5}, {6, 16, 26}, {27, 7, 17}, {18, 28, 8}, {9, 19, 29},
+		{-1, 31, 30},
+	}
+	shaCrypt512Order = [][3]int{
+		{0, 21, 42}, {22, 43, 1}, {44, 2, 23}, {3, 24, 45}, {25, 46, 4},
+		{47, 5, 26}, {6, 27, 48}, {28, 49, 7}, {50, 8, 29}, {9, 30, 51},
+		{31, 52, 10}, {53, 11, 32}, {12, 33, 54}, {34, 55, 13}, {56, 14, 35},
+		{15, 36, 57}, {37, 58, 16}, {59, 17, 38}, {18, 39, 60}, {40, 61, 19},
+		{62, 20, 41}, {-1, -1, 63},
+	}
+)
+
+func repeatBytes(b []byte, n int) []byte {
+	out := make([]byte, 0, n)
+	for len(out) < n {
+		out = append(out, b[:min(len(b), n-len(out))]...)
+	}
+
+	return out
+}
@@ -0,0 +1,93 @@
+package basicauth
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// User is an authenticated user, see `GetUser`.
+type User struct {
+	Username string
+	// Password is the, optionally hashed, password of the user, see `VerifyPassword`.
+	Password string
+	// Expires overrides the `Config.Expires` for this user.
+	//
+	// Defaults to 0, the `Config.Expires` is used.
+	Expires time.Duration
+}
+
+// UserStore returns the users by their usernames, i.e from a database,
+// see `Config.Store`, `Users` and `LoadHtpasswd`.
+type UserStore interface {
+	// GetUser returns the user of the "username", false if it doesn't exist.
+	GetUser(username string) (*User, bool)
+}
+
+// UserStoreFunc is an adapter which allows the use of a function as a `UserStore`.
+type UserStoreFunc func(username string) (*User, bool)
+
+// GetUser calls the "fn".
+func (fn UserStoreFunc) GetUser(username string) (*User, bool) {
+	return fn(username)
+}
+
+// Users is a `UserStore` of usernames and their hashed passwords, see `VerifyPassword`.
+// Note that the plain text passwords are accepted only by the `Config.Users`.
+type Users map[string]string
+
+// GetUser returns the user of the "username", false if it doesn't exist.
+func (u Users) GetUser(username string) (*User, bool) {
+	password, ok := u[username]
+	if !ok {
+		return nil, false
+	}
+
+	return &User{Username: username, Password: password}, true
+}
+
+// LoadHtpasswd loads the users of an htpasswd file.
+// The passwords should be hashed with the bcrypt (htpasswd -B) or the SHA-1 (htpasswd -s)
+// or the SHA-256/SHA-512 crypt, see `VerifyPassword`, the files with other hashes,
+// i.e the htpasswd's default MD5, or with plain text passwords are rejected.
+func LoadHtpasswd(filename string) (Users, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseHtpasswd(f)
+}
+
+// ParseHtpasswd parses the "username:password" lines of an htpasswd file,
+// the empty lines and the lines which start with '#' are ignored.
+// It returns an error if a password is not hashed by a supported hash, see `LoadHtpasswd`.
+func ParseHtpasswd(r io.Reader) (Users, error) {
+	users := make(Users)
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		idx := strings.IndexByte(line, ':')
+		if idx <= 0 {
+			continue
+		}
+
+		username, hashed := line[:idx], line[idx+1:]
+		if !IsHashSupported(hashed) {
+			return nil, fmt.Errorf("htpasswd: line %d: unsupported password hash of user %q", lineNumber, username)
+		}
+
+		users[username] = hashed
+	}
+
+	return users, scanner.Err()
+}
@@ -0,0 +1,478 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/ini.v1"
+	"gopkg.in/yaml.v2"
+)
+
+// Catalog contains the translated messages of each language.
+// The catalogs are not global, each application or middleware can use its own catalog.
+//
+// A message is a string or a map of its `PluralForm`s and the nested keys
+// of the locale files are joined with a dot, i.e "nav.home".
+// See `Translate` for the message formatting.
+type Catalog struct {
+	mu              sync.RWMutex
+	defaultLanguage string
+	// the registered languages, in order of registration.
+	languages []string
+	// the messages by lowercase language, a message is a string or a map[PluralForm]string.
+	messages  map[string]map[string]interface{}
+	fallbacks map[string][]string
+	rules     map[string]PluralRule
+}
+
+// NewCatalog returns a new empty translations catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		messages:  make(map[string]map[string]interface{}),
+		fallbacks: make(map[string][]string),
+		rules:     make(map[string]PluralRule),
+	}
+}
+
+// normalizeLanguage returns the "language" tag with dashes, i.e "en-US" for "en_US".
+func normalizeLanguage(language string) string {
+	return strings.Replace(strings.TrimSpace(language), "_", "-", -1)
+}
+
+// parentLanguage returns the "language" without its last subtag, i.e "pt" for "pt-BR",
+// or empty if the "language" has no subtags.
+func parentLanguage(language string) string {
+	if idx := strings.LastIndexByte(language, '-'); idx > 0 {
+		return language[:idx]
+	}
+
+	return ""
+}
+
+// Load loads the messages of the "language" from the locale "filenames",
+// their format is resolved by their extension, ".ini", ".yaml", ".yml", ".json" or ".toml".
+func (c *Catalog) Load(language string, filenames ...string) error {
+	for _, filename := range filenames {
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+
+		if err = c.LoadData(language, filepath.Ext(filename), data); err != nil {
+			return fmt.Errorf("%s: %v", filename, err)
+		}
+	}
+
+	return nil
+}
+
+// LoadData loads the messages of the "language" from the "data"
+// of the "format" format, "ini", "yaml", "yml", "json" or "toml".
+// Use it to load embedded locale files.
+func (c *Catalog) LoadData(language string, format string, data []byte) error {
+	// remove the utf-8 byte order mark, if any.
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	var (
+		messages map[string]interface{}
+		err      error
+	)
+
+	switch strings.ToLower(strings.TrimPrefix(format, ".")) {
+	case "ini":
+		messages, err = parseINI(data)
+	case "yaml", "yml":
+		err = yaml.Unmarshal(data, &messages)
+	case "json":
+		err = json.Unmarshal(data, &messages)
+	case "toml":
+		err = toml.Unmarshal(data, &messages)
+	default:
+		err = fmt.Errorf("unsupported locale format '%s'", format)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	c.Set(language, messages)
+	return nil
+}
+
+// Set adds the "messages" to the "language", the nested maps
+// are flattened to dot-separated keys and the maps which contain
+// the plural forms, i.e "one" and "other", are plural messages.
+func (c *Catalog) Set(language string, messages map[string]interface{}) {
+	language = normalizeLanguage(language)
+	key := strings.ToLower(language)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dest, ok := c.messages[key]
+	if !ok {
+		dest = make(map[string]interface{})
+		c.messages[key] = dest
+		c.languages = append(c.languages, language)
+	}
+
+	flattenMessages(dest, "", messages)
+}
+
+func flattenMessages(dest map[string]interface{}, prefix string, messages map[string]interface{}) {
+	for key, value := range messages {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		nested, ok := toStringMap(value)
+		if !ok {
+			dest[key] = fmt.Sprint(value)
+			continue
+		}
+
+		if forms, ok := pluralForms(nested); ok {
+			dest[key] = forms
+			continue
+		}
+
+		flattenMessages(dest, key, nested)
+	}
+}
+
+// toStringMap returns the "v" as map[string]interface{} if it's a map, i.e a yaml mapping.
+func toStringMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			result[fmt.Sprint(k)] = v
+		}
+		return result, true
+	default:
+		return nil, false
+	}
+}
+
+// pluralForms returns the "m" as plural message
+// if all of its keys are plural forms and it contains the `PluralOther`.
+func pluralForms(m map[string]interface{}) (map[PluralForm]string, bool) {
+	if _, ok := m[string(PluralOther)]; !ok {
+		return nil, false
+	}
+
+	forms := make(map[PluralForm]string, len(m))
+	for key, value := range m {
+		if _, nested := toStringMap(value); nested || !isPluralForm(key) {
+			return nil, false
+		}
+
+		forms[PluralForm(key)] = fmt.Sprint(value)
+	}
+
+	return forms, true
+}
+
+// parseINI parses the keys of an ini file,
+// the keys of a "[section]" are prefixed by the section's name and a dot.
+func parseINI(data []byte) (map[string]interface{}, error) {
+	file, err := ini.Load(data)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make(map[string]interface{})
+	for _, section := range file.Sections() {
+		prefix := ""
+		if name := section.Name(); name != ini.DefaultSection {
+			prefix = name + "."
+		}
+
+		for _, key := range section.Keys() {
+			messages[prefix+key.Name()] = key.Value()
+		}
+	}
+
+	return messages, nil
+}
+
+// SetDefault sets the default language, the last language
+// which the messages are translated from if they're missing from a language.
+//
+// Defaults to the first loaded language.
+func (c *Catalog) SetDefault(language string) *Catalog {
+	c.mu.Lock()
+	c.defaultLanguage = normalizeLanguage(language)
+	c.mu.Unlock()
+	return c
+}
+
+// Default returns the default language.
+func (c *Catalog) Default() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.defaultLanguage == "" && len(c.languages) > 0 {
+		return c.languages[0]
+	}
+
+	return c.defaultLanguage
+}
+
+// SetFallback sets the languages which the messages of the "language"
+// are translated from, in order, if they're missing.
+//
+// The messages are always translated from the parents of a language,
+// i.e "pt" for the "pt-BR", and the default language, after its fallbacks.
+func (c *Catalog) SetFallback(language string, fallbacks ...string) *Catalog {
+	for i := range fallbacks {
+		fallbacks[i] = normalizeLanguage(fallbacks[i])
+	}
+
+	c.mu.Lock()
+	c.fallbacks[strings.ToLower(normalizeLanguage(language))] = fallbacks
+	c.mu.Unlock()
+	return c
+}
+
+// SetPluralRule sets the plural rule of the "language" and its regions.
+// There are built'n rules for the most common languages.
+func (c *Catalog) SetPluralRule(language string, rule PluralRule) *Catalog {
+	c.mu.Lock()
+	c.rules[strings.ToLower(normalizeLanguage(language))] = rule
+	c.mu.Unlock()
+	return c
+}
+
+// Languages returns the languages of the catalog, in order of registration.
+func (c *Catalog) Languages() []string {
+	c.mu.RLock()
+	languages := append([]string(nil), c.languages...)
+	c.mu.RUnlock()
+	return languages
+}
+
+// Match returns the language of the catalog which is the best match for the "language",
+// the language itself, one of its parents (i.e "pt" for "pt-BR")
+// or a region of its base language (i.e "en-US" for "en").
+func (c *Catalog) Match(language string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return matchLanguage(c.languages, language)
+}
+
+// matchLanguage returns the best match of the "language" from the "languages", see `Catalog#Match`.
+func matchLanguage(languages []string, language string) (string, bool) {
+	language = normalizeLanguage(language)
+	if language == "" {
+		return "", false
+	}
+
+	for tag := language; tag != ""; tag = parentLanguage(tag) {
+		for _, registered := range languages {
+			if strings.EqualFold(registered, tag) {
+				return registered, true
+			}
+		}
+	}
+
+	base := strings.ToLower(language)
+	if idx := strings.IndexByte(base, '-'); idx > 0 {
+		base = base[:idx]
+	}
+
+	for _, registered := range languages {
+		if strings.HasPrefix(strings.ToLower(registered), base+"-") {
+			return registered, true
+		}
+	}
+
+	return "", false
+}
+
+// chain returns the lowercase languages which a message of the "language" is looked up from.
+func (c *Catalog) chain(language string) []string {
+	var chain []string
+	seen := make(map[string]bool)
+
+	add := func(language string) {
+		for tag := strings.ToLower(normalizeLanguage(language)); tag != ""; tag = parentLanguage(tag) {
+			if !seen[tag] {
+				seen[tag] = true
+				chain = append(chain, tag)
+			}
+		}
+	}
+
+	add(language)
+	for _, fallback := range c.fallbacks[strings.ToLower(normalizeLanguage(language))] {
+		add(fallback)
+	}
+
+	if c.defaultLanguage != "" {
+		add(c.defaultLanguage)
+	} else if len(c.languages) > 0 {
+		add(c.languages[0])
+	}
+
+	return chain
+}
+
+// pluralRule returns the plural rule of the "language".
+func (c *Catalog) pluralRule(language string) PluralRule {
+	for tag := strings.ToLower(normalizeLanguage(language)); tag != ""; tag = parentLanguage(tag) {
+		if rule, ok := c.rules[tag]; ok {
+			return rule
+		}
+	}
+
+	return pluralRuleFor(language)
+}
+
+// Translate returns the "key" message of the "language", or of its fallback languages,
+// formatted with the "args", if the message is missing then the "key" is formatted.
+//
+// The arguments can be:
+// - a single map[string]interface{} (i.e iris.Map) of named arguments, the message's {name} placeholders,
+// the "count" argument chooses the form of a plural message
+// - positional arguments, the message's {0}, {1}... placeholders or, if it has no placeholders,
+// its printf-style verbs, the first number argument chooses the form of a plural message.
+//
+// The messages support ICU-like plural and select arguments too,
+// i.e "{count, plural, =0 {no cats} one {# cat} other {# cats}}".
+func (c *Catalog) Translate(language string, key string, args ...interface{}) string {
+	c.mu.RLock()
+	var (
+		msg interface{} = key
+		tag             = language
+	)
+	for _, lang := range c.chain(language) {
+		if m, ok := c.messages[lang][key]; ok {
+			msg, tag = m, lang
+			break
+		}
+	}
+	rule := c.pluralRule(tag)
+	c.mu.RUnlock()
+
+	named, isNamed := namedArgs(args)
+	if !isNamed {
+		args = flattenArgs(args)
+		named = make(map[string]interface{}, len(args))
+		for i, arg := range args {
+			named[fmt.Sprint(i)] = arg
+		}
+	}
+
+	var text string
+	switch m := msg.(type) {
+	case string:
+		text = m
+	case map[PluralForm]string:
+		text = pluralText(m, rule, pluralCount(named, isNamed, args))
+	}
+
+	if strings.IndexByte(text, '{') != -1 {
+		f := messageFormat{rule: rule, args: named}
+		return f.format(text)
+	}
+
+	if !isNamed && len(args) > 0 && strings.IndexByte(text, '%') != -1 {
+		return fmt.Sprintf(text, args...)
+	}
+
+	return text
+}
+
+// Tr returns a function which translates the messages of the "language", see `Translate`.
+func (c *Catalog) Tr(language string) func(key string, args ...interface{}) string {
+	return func(key string, args ...interface{}) string {
+		return c.Translate(language, key, args...)
+	}
+}
+
+// namedArgs returns the named arguments if the "args" is a single map with string keys.
+func namedArgs(args []interface{}) (map[string]interface{}, bool) {
+	if len(args) != 1 || args[0] == nil {
+		return nil, false
+	}
+
+	if m, ok := args[0].(map[string]interface{}); ok {
+		return m, true
+	}
+
+	val := reflect.ValueOf(args[0])
+	if val.Kind() != reflect.Map || val.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+
+	m := make(map[string]interface{}, val.Len())
+	for _, k := range val.MapKeys() {
+		m[k.String()] = val.MapIndex(k).Interface()
+	}
+
+	return m, true
+}
+
+// flattenArgs expands the slice arguments, as the global i18n package did.
+func flattenArgs(args []interface{}) []interface{} {
+	params := make([]interface{}, 0, len(args))
+	for _, arg := range args {
+		if arg == nil {
+			continue
+		}
+
+		if val := reflect.ValueOf(arg); val.Kind() == reflect.Slice {
+			for i := 0; i < val.Len(); i++ {
+				params = append(params, val.Index(i).Interface())
+			}
+			continue
+		}
+
+		params = append(params, arg)
+	}
+
+	return params
+}
+
+// pluralCount returns the number which chooses the form of a plural message,
+// the "count" named argument or the first number of the positional arguments.
+func pluralCount(named map[string]interface{}, isNamed bool, args []interface{}) float64 {
+	if isNamed {
+		n, _ := toNumber(named["count"])
+		return n
+	}
+
+	for _, arg := range args {
+		if n, ok := toNumber(arg); ok {
+			if _, isString := arg.(string); !isString {
+				return n
+			}
+		}
+	}
+
+	return 0
+}
+
+func pluralText(forms map[PluralForm]string, rule PluralRule, n float64) string {
+	// the zero form is used for the zero, if it's declared, even if the language's rule has no zero form.
+	if text, ok := forms[PluralZero]; ok && n == 0 {
+		return text
+	}
+
+	if text, ok := forms[rule(n)]; ok {
+		return text
+	}
+
+	return forms[PluralOther]
+}
@@ -10,9 +10,41 @@ type Config struct {
 	//
 	// Checked: Serving state, runtime
 	URLParameter string
-	// Languages is a map[string]string which the key is the language i81n and the value is the file location
+	// Languages is a map[string]string which the key is the language i81n and the value is the file location,
+	// more than one files can be separated by comma. The files can be ".ini", ".yaml", ".yml", ".json" or ".toml",
+	// the ".ini" is added to the files without one of these extensions.
 	//
 	// Example of key is: 'en-US'
-	// Example of value is: './locales/en-US.ini'
+	// Example of value is: './locales/en-US.ini' or './locales/en-US.yml'
 	Languages map[string]string
+	// Catalog is the translations catalog which the languages are loaded to,
+	// set it to share the same translations between more than one middlewares
+	// or to load them from embedded files, see `Catalog#LoadData`.
+	//
+	// Defaults to a new catalog, for each middleware.
+	Catalog *Catalog
+	// TemplateFunc is the name of the view data which the translate function of the request's language
+	// is setted to, i.e {{call .tr "hi" "iris"}} on the html templates or {{ tr("hi", "iris") }} on the django ones.
+	//
+	// Defaults to "tr".
+	TemplateFunc string
+	// Extractors are the functions which extract the language of a request, in order,
+	// the first supported language is used, see `FromPath`, `FromSubdomain`, `FromURLParameter`,
+	// `FromCookie`, `FromHeader` and `FromSession`.
+	// The extracted languages are negotiated against the loaded ones by their q-values,
+	// and the `Default` is used if none of them is supported.
+	//
+	// Defaults to the language prefix of the path removed by the `NewWrapper`, the `URLParameter`,
+	// the cookie and the Accept-Language header.
+	Extractors []LanguageExtractor
+	// DisableCookie disables the cookie which the language of the request is saved to.
+	//
+	// Defaults to false.
+	DisableCookie bool
+	// PathRedirect redirects the requests without a language prefix to the prefixed path,
+	// i.e "/about" to "/el-GR/about", by their Accept-Language or the `Default` language.
+	// It's used only by the `NewWrapper`.
+	//
+	// Defaults to false.
+	PathRedirect bool
 }
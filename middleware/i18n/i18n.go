@@ -3,86 +3,89 @@
 package i18n
 
 import (
+	"path/filepath"
 	"reflect"
 	"strings"
 
-	"github.com/hidevopsio/i18n"
 	"github.com/hidevopsio/iris/context"
 )
 
 // test file: ../../_examples/miscellaneous/i18n/main_test.go
 type i18nMiddleware struct {
-	config Config
+	config  Config
+	catalog *Catalog
 }
 
 // ServeHTTP serves the request, the actual middleware's job is here
 func (i *i18nMiddleware) ServeHTTP(ctx context.Context) {
-	wasByCookie := false
-
-	language := i.config.Default
-
 	langKey := ctx.Application().ConfigurationReadOnly().GetTranslateLanguageContextKey()
-	language = ctx.Values().GetString(langKey)
+	language := ctx.Values().GetString(langKey)
 	if language == "" {
-		// try to get by url parameter
-		language = ctx.URLParam(i.config.URLParameter)
-		if language == "" {
-			// then try to take the lang field from the cookie
-			language = ctx.GetCookie(langKey)
-
-			if len(language) > 0 {
-				wasByCookie = true
-			} else {
-				// try to get by the request headers.
-				langHeader := ctx.GetHeader("Accept-Language")
-				if len(langHeader) > 0 {
-					for _, langEntry := range strings.Split(langHeader, ",") {
-						lc := strings.Split(langEntry, ";")[0]
-						if lc, ok := i18n.IsExistSimilar(lc); ok {
-							language = lc
-							break
-						}
-					}
-				}
+		languages := i.catalog.Languages()
+		for _, extract := range i.config.Extractors {
+			if lc, ok := negotiate(languages, extract(ctx), i.config.Default); ok {
+				language = lc
+				break
 			}
 		}
-		// if it was not taken by the cookie, then set the cookie in order to have it
-		if !wasByCookie {
-			ctx.SetCookieKV(langKey, language)
-		}
+
 		if language == "" {
 			language = i.config.Default
 		}
 
+		// save the language to the cookie in order to have it on the next requests.
+		if !i.config.DisableCookie && ctx.GetCookie(langKey) != language {
+			ctx.SetCookieKV(langKey, language)
+		}
+
 		ctx.Values().Set(langKey, language)
 	}
-	locale := i18n.Locale{Lang: language}
 
 	// if unexpected language given, the middleware will  transtlate to the default language, the language key should be
 	// also this language instead of the user-given
-	if indexLang := locale.Index(); indexLang == -1 {
-		locale.Lang = i.config.Default
+	locale, ok := i.catalog.Match(language)
+	if !ok {
+		locale = i.config.Default
 	}
 
+	tr := i.catalog.Tr(locale)
 	translateFuncKey := ctx.Application().ConfigurationReadOnly().GetTranslateFunctionContextKey()
-	ctx.Values().Set(translateFuncKey, locale.Tr)
+	ctx.Values().Set(translateFuncKey, tr)
+	ctx.ViewData(i.config.TemplateFunc, tr)
 	ctx.Next()
 }
 
 // Translate returns the translated word from a context
-// the second parameter is the key of the world or line inside the .ini file
-// the third parameter is the '%s' of the world or line inside the .ini file
+// the second parameter is the key of the world or line inside the locale file
+// the third parameter is the '%s' of the world or line inside the locale file
+// or the named arguments, see `Catalog#Translate`.
 func Translate(ctx context.Context, format string, args ...interface{}) string {
 	return ctx.Translate(format, args...)
 }
 
 // New returns a new i18n middleware
 func New(c Config) context.Handler {
-	if len(c.Languages) == 0 {
+	if len(c.Languages) == 0 && c.Catalog == nil {
 		panic("You cannot use this middleware without set the Languages option, please try again and read the _example.")
 	}
-	i := &i18nMiddleware{config: c}
-	firstlanguage := ""
+
+	if c.Catalog == nil {
+		c.Catalog = NewCatalog()
+	}
+
+	if c.TemplateFunc == "" {
+		c.TemplateFunc = "tr"
+	}
+
+	if len(c.Extractors) == 0 {
+		c.Extractors = append(c.Extractors, fromWrapper)
+		if c.URLParameter != "" {
+			c.Extractors = append(c.Extractors, FromURLParameter(c.URLParameter))
+		}
+		c.Extractors = append(c.Extractors, FromCookie(""), FromHeader())
+	}
+
+	i := &i18nMiddleware{config: c, catalog: c.Catalog}
 	//load the files
 	for k, langFileOrFiles := range c.Languages {
 		// remove all spaces.
@@ -91,24 +94,24 @@ func New(c Config) context.Handler {
 		languages := strings.Split(langFileOrFiles, ",")
 
 		for _, v := range languages { // loop each of the files separated by comma, if any.
-			if !strings.HasSuffix(v, ".ini") {
+			switch filepath.Ext(v) {
+			case ".ini", ".yaml", ".yml", ".json", ".toml":
+			default:
 				v += ".ini"
 			}
-			err := i18n.SetMessage(k, v)
-			if err != nil && err != i18n.ErrLangAlreadyExist {
+
+			if err := i.catalog.Load(k, v); err != nil {
 				panic("Failed to set locale file'" + k + "' Error:" + err.Error())
 			}
-			if firstlanguage == "" {
-				firstlanguage = k
-			}
 		}
 	}
-	// if not default language setted then set to the first of the i.config.Languages
-	if c.Default == "" {
-		c.Default = firstlanguage
+
+	// if not default language setted then set to the first of the catalog's languages.
+	if c.Default != "" {
+		i.catalog.SetDefault(c.Default)
 	}
+	i.config.Default = i.catalog.Default()
 
-	i18n.SetDefaultLang(i.config.Default)
 	return i.ServeHTTP
 }
 
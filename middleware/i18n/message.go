@@ -0,0 +1,211 @@
+package i18n
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// messageFormat formats the ICU-like messages, the supported arguments are:
+//
+// - {name} the value of the "name" argument, the positional arguments are named by their index, i.e {0}
+// - {name, number} the number value of the "name" argument
+// - {name, plural, =0 {none} one {# item} other {# items}} the plural form of the "name" number,
+// the "#" is replaced by the number and an "offset:n" can precede the forms
+// - {name, select, male {he} female {she} other {they}} the form of the "name" value
+//
+// A quote escapes the special characters, i.e '{' or '#', and two quotes are a literal quote.
+type messageFormat struct {
+	rule PluralRule
+	args map[string]interface{}
+}
+
+func (f *messageFormat) format(msg string) string {
+	var b strings.Builder
+	f.write(&b, msg, "", false)
+	return b.String()
+}
+
+// write writes the formatted "msg" to the "b", the "number" replaces the "#"
+// if "inPlural" is true, which is true for the forms of a plural argument.
+func (f *messageFormat) write(b *strings.Builder, msg string, number string, inPlural bool) {
+	for i := 0; i < len(msg); i++ {
+		switch c := msg[i]; {
+		case c == '\'':
+			if i+1 < len(msg) && msg[i+1] == '\'' {
+				b.WriteByte('\'')
+				i++
+				continue
+			}
+
+			if i+1 < len(msg) && (msg[i+1] == '{' || msg[i+1] == '}' || msg[i+1] == '#') {
+				end := strings.IndexByte(msg[i+1:], '\'')
+				if end == -1 {
+					end = len(msg) - i - 1
+				}
+
+				b.WriteString(msg[i+1 : i+1+end])
+				i += end + 1
+				continue
+			}
+
+			b.WriteByte(c)
+		case c == '#' && inPlural:
+			b.WriteString(number)
+		case c == '{':
+			end := closingBrace(msg, i)
+			if end == -1 {
+				b.WriteString(msg[i:])
+				return
+			}
+
+			f.writeArgument(b, msg[i+1:end])
+			i = end
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+// closingBrace returns the index of the brace which closes the "start" one of the "msg",
+// or -1 if it's not closed.
+func closingBrace(msg string, start int) int {
+	depth := 0
+	for i := start; i < len(msg); i++ {
+		switch msg[i] {
+		case '\'':
+			if i+1 < len(msg) && (msg[i+1] == '{' || msg[i+1] == '}') {
+				if end := strings.IndexByte(msg[i+1:], '\''); end != -1 {
+					i += end + 1
+				}
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func (f *messageFormat) writeArgument(b *strings.Builder, arg string) {
+	parts := strings.SplitN(arg, ",", 3)
+	name := strings.TrimSpace(parts[0])
+
+	value, ok := f.args[name]
+	if !ok {
+		// keep the unknown arguments as they're.
+		b.WriteString("{" + arg + "}")
+		return
+	}
+
+	typ := ""
+	if len(parts) > 1 {
+		typ = strings.TrimSpace(parts[1])
+	}
+
+	switch typ {
+	case "plural":
+		if len(parts) < 3 {
+			break
+		}
+
+		n, ok := toNumber(value)
+		if !ok {
+			break
+		}
+
+		options, offset := parseOptions(parts[2])
+		form, ok := options["="+formatNumber(n)]
+		if !ok {
+			if form, ok = options[string(f.rule(n-offset))]; !ok {
+				form = options[string(PluralOther)]
+			}
+		}
+
+		f.write(b, form, formatNumber(n-offset), true)
+		return
+	case "select":
+		if len(parts) < 3 {
+			break
+		}
+
+		options, _ := parseOptions(parts[2])
+		form, ok := options[fmt.Sprint(value)]
+		if !ok {
+			form = options[string(PluralOther)]
+		}
+
+		f.write(b, form, "", false)
+		return
+	case "number":
+		if n, ok := toNumber(value); ok {
+			b.WriteString(formatNumber(n))
+			return
+		}
+	}
+
+	b.WriteString(fmt.Sprint(value))
+}
+
+// parseOptions parses the "key {message}" options of a plural or select argument
+// and the plural's "offset:n".
+func parseOptions(s string) (options map[string]string, offset float64) {
+	options = make(map[string]string)
+
+	for {
+		s = strings.TrimSpace(s)
+		start := strings.IndexByte(s, '{')
+		if start == -1 {
+			return
+		}
+
+		keys := strings.Fields(s[:start])
+		end := closingBrace(s, start)
+		if end == -1 {
+			return
+		}
+
+		for _, key := range keys {
+			if strings.HasPrefix(key, "offset:") {
+				offset, _ = strconv.ParseFloat(strings.TrimPrefix(key, "offset:"), 64)
+				continue
+			}
+
+			options[key] = s[start+1 : end]
+		}
+
+		s = s[end+1:]
+	}
+}
+
+// toNumber returns the "v" as float64 if it's a number or a numeric string.
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		return f, err == nil
+	case nil:
+		return 0, false
+	}
+
+	switch val := reflect.ValueOf(v); val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
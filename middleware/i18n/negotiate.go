@@ -0,0 +1,226 @@
+package i18n
+
+import (
+	stdContext "context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hidevopsio/iris/context"
+)
+
+// LanguageExtractor returns the language of a request, or empty if it's missing.
+// The result can be a single language or a list of languages with their q-values,
+// like the Accept-Language header, the first of them which is supported is used.
+//
+// See `Config.Extractors`.
+type LanguageExtractor func(ctx context.Context) string
+
+// FromURLParameter returns an extractor of the "name" url parameter, i.e "?lang=el-GR".
+func FromURLParameter(name string) LanguageExtractor {
+	return func(ctx context.Context) string {
+		return ctx.URLParam(name)
+	}
+}
+
+// FromCookie returns an extractor of the "name" cookie,
+// if "name" is empty then the cookie is the `Configuration.TranslateLanguageContextKey`,
+// which the middleware saves the language of the requests to.
+func FromCookie(name string) LanguageExtractor {
+	return func(ctx context.Context) string {
+		if name == "" {
+			return ctx.GetCookie(ctx.Application().ConfigurationReadOnly().GetTranslateLanguageContextKey())
+		}
+
+		return ctx.GetCookie(name)
+	}
+}
+
+// FromHeader returns an extractor of the Accept-Language header,
+// its languages are negotiated by their q-values.
+func FromHeader() LanguageExtractor {
+	return func(ctx context.Context) string {
+		return ctx.GetHeader("Accept-Language")
+	}
+}
+
+// FromSubdomain returns an extractor of the subdomain, i.e "el" for the "el.mydomain.com".
+func FromSubdomain() LanguageExtractor {
+	return func(ctx context.Context) string {
+		return ctx.Subdomain()
+	}
+}
+
+type pathLanguageContextKey struct{}
+
+// FromPath returns an extractor of the language prefix of the request path, i.e "el" for the "/el/about".
+// It's the language which the `NewWrapper` removed from the path
+// or, if the wrapper is not used, the first segment of the path.
+func FromPath() LanguageExtractor {
+	return func(ctx context.Context) string {
+		if language := fromWrapper(ctx); language != "" {
+			return language
+		}
+
+		segment, _ := splitPathLanguage(ctx.Path())
+		return segment
+	}
+}
+
+// fromWrapper returns the language prefix which the `NewWrapper` removed from the request path.
+func fromWrapper(ctx context.Context) string {
+	language, _ := ctx.Request().Context().Value(pathLanguageContextKey{}).(string)
+	return language
+}
+
+// Session is the part of a session, i.e the `sessions.Session`, which the `FromSession` reads the language from.
+type Session interface {
+	GetString(key string) string
+}
+
+// FromSession returns an extractor of the "key" value of the request's session.
+//
+// Usage:
+// i18n.FromSession(func(ctx iris.Context) i18n.Session { return sess.Start(ctx) }, "language")
+func FromSession(start func(ctx context.Context) Session, key string) LanguageExtractor {
+	return func(ctx context.Context) string {
+		if sess := start(ctx); sess != nil {
+			return sess.GetString(key)
+		}
+
+		return ""
+	}
+}
+
+// parseAcceptLanguage returns the languages of an Accept-Language header value,
+// ordered by their q-values, without the unacceptable (q=0) ones.
+func parseAcceptLanguage(header string) []string {
+	type entry struct {
+		language string
+		q        float64
+	}
+
+	var entries []entry
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		language := strings.TrimSpace(fields[0])
+		if language == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		if q > 0 {
+			entries = append(entries, entry{language, q})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].q > entries[j].q })
+
+	languages := make([]string, len(entries))
+	for i, e := range entries {
+		languages[i] = e.language
+	}
+
+	return languages
+}
+
+// negotiate returns the first of the "accepted" languages, a list like the Accept-Language header,
+// which is supported by the "languages", the "fallback" is returned for the "*".
+func negotiate(languages []string, accepted string, fallback string) (string, bool) {
+	for _, language := range parseAcceptLanguage(accepted) {
+		if language == "*" {
+			if fallback != "" {
+				return fallback, true
+			}
+			continue
+		}
+
+		if match, ok := matchLanguage(languages, language); ok {
+			return match, true
+		}
+	}
+
+	return "", false
+}
+
+// splitPathLanguage returns the first segment of the "path" and the rest of it.
+func splitPathLanguage(path string) (segment string, rest string) {
+	path = strings.TrimPrefix(path, "/")
+	if idx := strings.IndexByte(path, '/'); idx != -1 {
+		return path[:idx], path[idx:]
+	}
+
+	return path, "/"
+}
+
+// NewWrapper returns a router wrapper, which should be registered through the `app.WrapRouter`,
+// that removes the language prefix, i.e "/el", of the request paths before the routing,
+// so the same routes serve all the languages, i.e the "/about" route serves the "/el/about" too.
+// The removed language is extracted by the `FromPath` extractor.
+//
+// If the `Config.PathRedirect` is true then the requests without a language prefix
+// are redirected to the same path prefixed by their Accept-Language or the default language,
+// i.e "/about" to "/el/about".
+//
+// The languages are the keys of the `Config.Languages` or the languages of the `Config.Catalog`.
+func NewWrapper(c Config) func(w http.ResponseWriter, r *http.Request, router http.HandlerFunc) {
+	languages := func() []string {
+		if c.Catalog != nil {
+			return c.Catalog.Languages()
+		}
+
+		languages := make([]string, 0, len(c.Languages))
+		for language := range c.Languages {
+			languages = append(languages, language)
+		}
+		sort.Strings(languages)
+		return languages
+	}
+
+	return func(w http.ResponseWriter, r *http.Request, router http.HandlerFunc) {
+		supported := languages()
+		segment, rest := splitPathLanguage(r.URL.Path)
+
+		if language, ok := matchLanguage(supported, segment); ok && segment != "" {
+			r.URL.Path = rest
+			r.URL.RawPath = ""
+			router(w, r.WithContext(stdContext.WithValue(r.Context(), pathLanguageContextKey{}, language)))
+			return
+		}
+
+		if c.PathRedirect && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+			fallback := c.Default
+			if fallback == "" && c.Catalog != nil {
+				fallback = c.Catalog.Default()
+			}
+			if fallback == "" && len(supported) > 0 {
+				fallback = supported[0]
+			}
+
+			language, ok := negotiate(supported, r.Header.Get("Accept-Language"), fallback)
+			if !ok {
+				language = fallback
+			}
+
+			if language != "" {
+				u := *r.URL
+				u.Path = "/" + language + r.URL.Path
+				u.RawPath = ""
+				http.Redirect(w, r, u.RequestURI(), http.StatusFound)
+				return
+			}
+		}
+
+		router(w, r)
+	}
+}
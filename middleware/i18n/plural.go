@@ -0,0 +1,162 @@
+package i18n
+
+import (
+	"math"
+	"strings"
+)
+
+// PluralForm is a plural category of the CLDR plural rules.
+type PluralForm string
+
+// The plural forms, a plural message should declare at least its `PluralOther` form.
+const (
+	PluralZero  PluralForm = "zero"
+	PluralOne   PluralForm = "one"
+	PluralTwo   PluralForm = "two"
+	PluralFew   PluralForm = "few"
+	PluralMany  PluralForm = "many"
+	PluralOther PluralForm = "other"
+)
+
+// isPluralForm reports whether the "s" is the name of a plural form.
+func isPluralForm(s string) bool {
+	switch PluralForm(s) {
+	case PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// PluralRule returns the plural form of the "n" number for a language.
+type PluralRule func(n float64) PluralForm
+
+// pluralOperands returns the integer digits of the "n" and
+// whether it has visible fraction digits, the "i" and "v != 0" operands of the CLDR rules.
+func pluralOperands(n float64) (i int64, fraction bool) {
+	n = math.Abs(n)
+	return int64(n), n != math.Trunc(n)
+}
+
+// pluralOther is the rule of the languages without plurals, i.e japanese and chinese.
+func pluralOther(n float64) PluralForm {
+	return PluralOther
+}
+
+// pluralOneOther is the rule of english and most of the european languages.
+func pluralOneOther(n float64) PluralForm {
+	if i, fraction := pluralOperands(n); i == 1 && !fraction {
+		return PluralOne
+	}
+
+	return PluralOther
+}
+
+// pluralZeroOneOther is the rule of the languages which use the singular for zero too, i.e french.
+func pluralZeroOneOther(n float64) PluralForm {
+	if i, _ := pluralOperands(n); i == 0 || i == 1 {
+		return PluralOne
+	}
+
+	return PluralOther
+}
+
+// pluralEastSlavic is the rule of russian, ukrainian and belarusian.
+func pluralEastSlavic(n float64) PluralForm {
+	i, fraction := pluralOperands(n)
+	if fraction {
+		return PluralOther
+	}
+
+	switch mod10, mod100 := i%10, i%100; {
+	case mod10 == 1 && mod100 != 11:
+		return PluralOne
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+// pluralPolish is the rule of polish.
+func pluralPolish(n float64) PluralForm {
+	i, fraction := pluralOperands(n)
+	if fraction {
+		return PluralOther
+	}
+
+	switch mod10, mod100 := i%10, i%100; {
+	case i == 1:
+		return PluralOne
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
+
+// pluralCzech is the rule of czech and slovak.
+func pluralCzech(n float64) PluralForm {
+	i, fraction := pluralOperands(n)
+	switch {
+	case fraction:
+		return PluralMany
+	case i == 1:
+		return PluralOne
+	case i >= 2 && i <= 4:
+		return PluralFew
+	default:
+		return PluralOther
+	}
+}
+
+// pluralArabic is the rule of arabic.
+func pluralArabic(n float64) PluralForm {
+	i, fraction := pluralOperands(n)
+	if fraction {
+		return PluralOther
+	}
+
+	switch mod100 := i % 100; {
+	case i == 0:
+		return PluralZero
+	case i == 1:
+		return PluralOne
+	case i == 2:
+		return PluralTwo
+	case mod100 >= 3 && mod100 <= 10:
+		return PluralFew
+	case mod100 >= 11:
+		return PluralMany
+	default:
+		return PluralOther
+	}
+}
+
+// pluralRules are the built'n plural rules by language,
+// the rule of a language without a region, i.e "pt" for "pt-BR", is used for its regions too.
+var pluralRules = map[string]PluralRule{
+	"ja": pluralOther, "zh": pluralOther, "ko": pluralOther, "vi": pluralOther,
+	"th": pluralOther, "id": pluralOther, "ms": pluralOther,
+	"en": pluralOneOther, "de": pluralOneOther, "nl": pluralOneOther, "sv": pluralOneOther,
+	"da": pluralOneOther, "nb": pluralOneOther, "no": pluralOneOther, "fi": pluralOneOther,
+	"et": pluralOneOther, "it": pluralOneOther, "es": pluralOneOther, "el": pluralOneOther,
+	"hu": pluralOneOther, "tr": pluralOneOther, "bg": pluralOneOther, "ca": pluralOneOther, "pt-pt": pluralOneOther,
+	"fr": pluralZeroOneOther, "pt": pluralZeroOneOther, "hi": pluralZeroOneOther, "bn": pluralZeroOneOther,
+	"ru": pluralEastSlavic, "uk": pluralEastSlavic, "be": pluralEastSlavic,
+	"pl": pluralPolish,
+	"cs": pluralCzech, "sk": pluralCzech,
+	"ar": pluralArabic,
+}
+
+// pluralRuleFor returns the built'n plural rule of the "language",
+// the english one if the language has no built'n rule.
+func pluralRuleFor(language string) PluralRule {
+	for tag := normalizeLanguage(language); tag != ""; tag = parentLanguage(tag) {
+		if rule, ok := pluralRules[strings.ToLower(tag)]; ok {
+			return rule
+		}
+	}
+
+	return pluralOneOther
+}
@@ -0,0 +1,250 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	mathrand "math/rand"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hidevopsio/iris/context"
+)
+
+// Record is the access log record of a request.
+type Record struct {
+	// Time is the time which the request was served.
+	Time time.Time
+	// Latency is the time which the request took to be served.
+	Latency time.Duration
+	// RequestID is the value of the `AccessLogConfig.RequestIDHeader` request header
+	// or a random one if the request has not any.
+	RequestID string
+	// RouteName is the name of the matched route, empty if not found.
+	RouteName string
+	Method    string
+	Path      string
+	Query     string
+	Proto     string
+	Status    int
+	IP        string
+	// Username is the basic authentication's username, if any.
+	Username  string
+	Referer   string
+	UserAgent string
+	// Params are the named path parameters of the matched route.
+	Params map[string]string
+	// BytesIn is the length of the request body.
+	BytesIn int64
+	// BytesOut is the length of the response body.
+	BytesOut int64
+	// Headers are the `AccessLogConfig.Headers` of the request.
+	Headers map[string]string
+}
+
+// AccessLogConfig contains the options for the access logger, see `NewAccessLog`.
+type AccessLogConfig struct {
+	// Output is the writer which the records are written to, i.e a `RotateFile`.
+	//
+	// Defaults to the os.Stdout.
+	Output io.Writer
+	// Formatter formats the records, see `FormatJSON`, `FormatLogfmt`, `FormatCommon` and `FormatCombined`.
+	//
+	// Defaults to the `FormatJSON`.
+	Formatter Formatter
+	// BufferSize if greater than zero then the records are written to the `Output` asynchronously,
+	// through a buffer of "BufferSize" records, the records are dropped when the buffer is full,
+	// see `AsyncWriter`.
+	//
+	// Defaults to 0, the records are written synchronously.
+	BufferSize int
+	// RequestIDHeader is the request header of the request id,
+	// if missing then a random id is generated and set to the same response header.
+	//
+	// Defaults to "X-Request-Id".
+	RequestIDHeader string
+	// Headers are the request headers which are logged.
+	//
+	// Defaults to empty.
+	Headers []string
+	// SampleRate if between zero and one then only that fraction of the requests is logged,
+	// i.e 0.1 for the 10% of them, the requests which fail with a server error (5xx) are always logged.
+	//
+	// Defaults to 0, all requests are logged.
+	SampleRate float64
+	// Skippers used to skip the logging i.e by `ctx.Path()`.
+	Skippers []SkipperFunc
+}
+
+// AccessLog is a structured access logger middleware, see `NewAccessLog`.
+type AccessLog struct {
+	config AccessLogConfig
+	skip   SkipperFunc
+
+	mu     sync.Mutex // protects the output.
+	output io.Writer
+}
+
+// NewAccessLog returns a new access logger, its `ServeHTTP` is the middleware
+// and its `Close` should be called on the application's shutdown in order to flush the records.
+//
+// Usage:
+// ac := logger.NewAccessLog(logger.AccessLogConfig{Output: file, Formatter: logger.FormatCombined})
+// defer ac.Close()
+// app.Use(ac.ServeHTTP)
+func NewAccessLog(c AccessLogConfig) *AccessLog {
+	if c.Output == nil {
+		c.Output = os.Stdout
+	}
+
+	if c.Formatter == nil {
+		c.Formatter = FormatJSON
+	}
+
+	if c.RequestIDHeader == "" {
+		c.RequestIDHeader = "X-Request-Id"
+	}
+
+	cfg := Config{Skippers: c.Skippers}
+	cfg.buildSkipper()
+
+	output := c.Output
+	if c.BufferSize > 0 {
+		output = NewAsyncWriter(output, c.BufferSize)
+	}
+
+	return &AccessLog{config: c, skip: cfg.skip, output: output}
+}
+
+// ServeHTTP serves the middleware.
+func (a *AccessLog) ServeHTTP(ctx context.Context) {
+	if a.skip != nil && a.skip(ctx) {
+		ctx.Next()
+		return
+	}
+
+	r := ctx.Request()
+
+	requestID := ctx.GetHeader(a.config.RequestIDHeader)
+	if requestID == "" {
+		requestID = newRequestID()
+		ctx.Header(a.config.RequestIDHeader, requestID)
+	}
+
+	var body *countReader
+	if r.Body != nil && r.Body != http.NoBody {
+		body = &countReader{ReadCloser: r.Body}
+		r.Body = body
+	}
+
+	startTime := time.Now()
+	ctx.Next()
+	endTime := time.Now()
+
+	status := ctx.GetStatusCode()
+	if rate := a.config.SampleRate; rate > 0 && rate < 1 && status < http.StatusInternalServerError {
+		if mathrand.Float64() >= rate {
+			return
+		}
+	}
+
+	rec := &Record{
+		Time:      endTime,
+		Latency:   endTime.Sub(startTime),
+		RequestID: requestID,
+		Method:    r.Method,
+		Path:      ctx.Path(),
+		Query:     r.URL.RawQuery,
+		Proto:     r.Proto,
+		Status:    status,
+		IP:        ctx.RemoteAddr(),
+		Referer:   r.Referer(),
+		UserAgent: r.UserAgent(),
+	}
+
+	rec.Username, _, _ = r.BasicAuth()
+
+	if route := ctx.GetCurrentRoute(); route != nil {
+		rec.RouteName = route.Name()
+		ctx.Params().Visit(func(key string, value string) {
+			if rec.Params == nil {
+				rec.Params = make(map[string]string)
+			}
+			rec.Params[key] = value
+		})
+	}
+
+	if r.ContentLength > 0 {
+		rec.BytesIn = r.ContentLength
+	}
+	if body != nil && body.n > rec.BytesIn {
+		rec.BytesIn = body.n
+	}
+
+	if written := ctx.ResponseWriter().Written(); written > 0 {
+		rec.BytesOut = int64(written)
+	}
+
+	if len(a.config.Headers) > 0 {
+		rec.Headers = make(map[string]string, len(a.config.Headers))
+		for _, key := range a.config.Headers {
+			if value := ctx.GetHeader(key); value != "" {
+				rec.Headers[key] = value
+			}
+		}
+	}
+
+	a.mu.Lock()
+	_, err := a.output.Write(a.config.Formatter(rec))
+	a.mu.Unlock()
+
+	if err != nil {
+		ctx.Application().Logger().Errorf("access log: %v", err)
+	}
+}
+
+// Close flushes the buffered records, if `AccessLogConfig.BufferSize` was set,
+// and closes the output if it's an io.Closer, i.e a `RotateFile`.
+func (a *AccessLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return closeWriter(a.output)
+}
+
+// closeWriter closes the "w" if it's an io.Closer, except the standard output and error.
+func closeWriter(w io.Writer) error {
+	if w == io.Writer(os.Stdout) || w == io.Writer(os.Stderr) {
+		return nil
+	}
+
+	if closer, ok := w.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// newRequestID returns a random 16 bytes hex-encoded request id.
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// countReader counts the bytes read from the request body.
+type countReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+)
+
+// AsyncWriter is an io.WriteCloser which writes to another writer asynchronously,
+// through a bounded buffer, the writes are dropped when the buffer is full
+// in order to not block the requests on a slow output.
+type AsyncWriter struct {
+	w       io.Writer
+	records chan []byte
+	done    chan struct{}
+	dropped uint64
+
+	mu     sync.RWMutex // protects the closed.
+	closed bool
+}
+
+// NewAsyncWriter returns a new `AsyncWriter` which writes to the "w"
+// through a buffer of "size" writes.
+func NewAsyncWriter(w io.Writer, size int) *AsyncWriter {
+	if size <= 0 {
+		size = 1
+	}
+
+	a := &AsyncWriter{
+		w:       w,
+		records: make(chan []byte, size),
+		done:    make(chan struct{}),
+	}
+
+	go a.run()
+	return a
+}
+
+func (a *AsyncWriter) run() {
+	defer close(a.done)
+
+	for b := range a.records {
+		a.w.Write(b)
+	}
+}
+
+// Write buffers a copy of the "p" to be written, it never blocks,
+// if the buffer is full then the "p" is dropped, see `Dropped`.
+func (a *AsyncWriter) Write(p []byte) (int, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return 0, os.ErrClosed
+	}
+
+	b := make([]byte, len(p))
+	copy(b, p)
+
+	select {
+	case a.records <- b:
+	default:
+		atomic.AddUint64(&a.dropped, 1)
+	}
+
+	return len(p), nil
+}
+
+// Dropped returns the number of the writes which were dropped because the buffer was full.
+func (a *AsyncWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&a.dropped)
+}
+
+// Close writes the buffered writes and closes the underline writer if it's an io.Closer.
+func (a *AsyncWriter) Close() error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return nil
+	}
+	a.closed = true
+	close(a.records)
+	a.mu.Unlock()
+
+	<-a.done
+	return closeWriter(a.w)
+}
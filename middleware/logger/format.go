@@ -0,0 +1,177 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatter formats an access log record, including its trailing new line.
+// See `AccessLogConfig.Formatter`.
+type Formatter func(rec *Record) []byte
+
+// FormatJSON formats the record as a JSON object per line.
+func FormatJSON(rec *Record) []byte {
+	b, _ := json.Marshal(struct {
+		Time      string            `json:"time"`
+		RequestID string            `json:"request_id,omitempty"`
+		RouteName string            `json:"route,omitempty"`
+		Method    string            `json:"method"`
+		Path      string            `json:"path"`
+		Query     string            `json:"query,omitempty"`
+		Proto     string            `json:"proto,omitempty"`
+		Status    int               `json:"status"`
+		Latency   string            `json:"latency"`
+		IP        string            `json:"ip,omitempty"`
+		Username  string            `json:"username,omitempty"`
+		Referer   string            `json:"referer,omitempty"`
+		UserAgent string            `json:"user_agent,omitempty"`
+		Params    map[string]string `json:"params,omitempty"`
+		BytesIn   int64             `json:"bytes_in"`
+		BytesOut  int64             `json:"bytes_out"`
+		Headers   map[string]string `json:"headers,omitempty"`
+	}{
+		Time:      rec.Time.Format(time.RFC3339Nano),
+		RequestID: rec.RequestID,
+		RouteName: rec.RouteName,
+		Method:    rec.Method,
+		Path:      rec.Path,
+		Query:     rec.Query,
+		Proto:     rec.Proto,
+		Status:    rec.Status,
+		Latency:   rec.Latency.String(),
+		IP:        rec.IP,
+		Username:  rec.Username,
+		Referer:   rec.Referer,
+		UserAgent: rec.UserAgent,
+		Params:    rec.Params,
+		BytesIn:   rec.BytesIn,
+		BytesOut:  rec.BytesOut,
+		Headers:   rec.Headers,
+	})
+
+	return append(b, '\n')
+}
+
+// FormatLogfmt formats the record as a line of key=value pairs,
+// the path parameters are prefixed by "param." and the headers by "header.".
+func FormatLogfmt(rec *Record) []byte {
+	var b bytes.Buffer
+
+	writeLogfmt(&b, "time", rec.Time.Format(time.RFC3339Nano))
+	writeLogfmt(&b, "request_id", rec.RequestID)
+	writeLogfmt(&b, "route", rec.RouteName)
+	writeLogfmt(&b, "method", rec.Method)
+	writeLogfmt(&b, "path", rec.Path)
+	writeLogfmt(&b, "query", rec.Query)
+	writeLogfmt(&b, "status", strconv.Itoa(rec.Status))
+	writeLogfmt(&b, "latency", rec.Latency.String())
+	writeLogfmt(&b, "ip", rec.IP)
+	writeLogfmt(&b, "username", rec.Username)
+	writeLogfmt(&b, "bytes_in", strconv.FormatInt(rec.BytesIn, 10))
+	writeLogfmt(&b, "bytes_out", strconv.FormatInt(rec.BytesOut, 10))
+	writeLogfmt(&b, "referer", rec.Referer)
+	writeLogfmt(&b, "user_agent", rec.UserAgent)
+
+	for _, key := range sortedKeys(rec.Params) {
+		writeLogfmt(&b, "param."+key, rec.Params[key])
+	}
+
+	for _, key := range sortedKeys(rec.Headers) {
+		writeLogfmt(&b, "header."+key, rec.Headers[key])
+	}
+
+	b.WriteByte('\n')
+	return b.Bytes()
+}
+
+// writeLogfmt writes the "key=value" pair to the "b", the empty values are omitted
+// and the values with spaces, quotes or equal signs are quoted.
+func writeLogfmt(b *bytes.Buffer, key, value string) {
+	if value == "" {
+		return
+	}
+
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+
+	b.WriteString(key)
+	b.WriteByte('=')
+
+	if strings.ContainsAny(value, " \"=\t\r\n") {
+		b.WriteString(strconv.Quote(value))
+		return
+	}
+
+	b.WriteString(value)
+}
+
+// commonLogTimeFormat is the time format of the Common Log Format.
+const commonLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// FormatCommon formats the record with the Common Log Format, i.e
+// 127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326
+func FormatCommon(rec *Record) []byte {
+	b := appendCommon(nil, rec)
+	return append(b, '\n')
+}
+
+// FormatCombined formats the record with the Combined Log Format,
+// the Common Log Format followed by the referer and the user agent, i.e
+// 127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /apache_pb.gif HTTP/1.0" 200 2326 "http://www.example.com/start.html" "Mozilla/4.08"
+func FormatCombined(rec *Record) []byte {
+	b := appendCommon(nil, rec)
+	b = append(b, ' ')
+	b = strconv.AppendQuote(b, orDash(rec.Referer))
+	b = append(b, ' ')
+	b = strconv.AppendQuote(b, orDash(rec.UserAgent))
+	return append(b, '\n')
+}
+
+func appendCommon(b []byte, rec *Record) []byte {
+	uri := rec.Path
+	if rec.Query != "" {
+		uri += "?" + rec.Query
+	}
+
+	b = append(b, orDash(rec.IP)...)
+	b = append(b, " - "...)
+	b = append(b, orDash(rec.Username)...)
+	b = append(b, " ["...)
+	b = rec.Time.AppendFormat(b, commonLogTimeFormat)
+	b = append(b, "] "...)
+	b = strconv.AppendQuote(b, rec.Method+" "+uri+" "+rec.Proto)
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, int64(rec.Status), 10)
+	b = append(b, ' ')
+
+	if rec.BytesOut > 0 {
+		b = strconv.AppendInt(b, rec.BytesOut, 10)
+	} else {
+		b = append(b, '-')
+	}
+
+	return b
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	return keys
+}
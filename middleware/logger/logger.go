@@ -1,4 +1,5 @@
-// Package logger provides request logging via middleware. See _examples/http_request/request-logger
+// Package logger provides request logging via middleware, including a structured access logger
+// with file rotation. See _examples/http_request/request-logger
 package logger
 
 import (
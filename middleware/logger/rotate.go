@@ -0,0 +1,261 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateConfig contains the options for the `RotateFile`.
+type RotateConfig struct {
+	// MaxSize is the maximum size in bytes of the file before it's rotated.
+	//
+	// Defaults to 0, the file is not rotated by its size.
+	MaxSize int64
+	// Interval is the period of the file before it's rotated, i.e 24 * time.Hour for a file per day.
+	// The periods are aligned to the time.Time#Truncate, i.e on midnight (UTC) for a day.
+	//
+	// Defaults to 0, the file is not rotated by time.
+	Interval time.Duration
+	// MaxBackups is the maximum number of the rotated files which are kept, the oldest ones are removed.
+	//
+	// Defaults to 0, all rotated files are kept.
+	MaxBackups int
+	// Compress compresses the rotated files with gzip.
+	//
+	// Defaults to false.
+	Compress bool
+}
+
+// backupTimeFormat is the time format of the rotated files' names,
+// the rotated files of the "access.log" are named like "access-20181031T131300.000.log".
+const backupTimeFormat = "20060102T150405.000"
+
+// RotateFile is an io.WriteCloser which writes to a file and rotates it by its size or time,
+// the rotated files are renamed by the time they were rotated and, optionally, compressed.
+type RotateFile struct {
+	filename string
+	config   RotateConfig
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	rotateAt time.Time
+
+	millMu sync.Mutex     // serializes the compression and removal of the rotated files.
+	mills  sync.WaitGroup // waits the running compressions on `Close`.
+}
+
+// NewRotateFile opens, or creates, the "filename" for appending and returns a new `RotateFile`.
+func NewRotateFile(filename string, c RotateConfig) (*RotateFile, error) {
+	f := &RotateFile{filename: filename, config: c}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *RotateFile) open() error {
+	if dir := filepath.Dir(f.filename); dir != "" {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(f.filename, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	if interval := f.config.Interval; interval > 0 {
+		f.rotateAt = time.Now().Truncate(interval).Add(interval)
+	}
+
+	return nil
+}
+
+// Write writes the "p" to the file, the file is rotated before the write
+// if the write exceeds its `RotateConfig.MaxSize` or its `RotateConfig.Interval` has passed.
+func (f *RotateFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *RotateFile) shouldRotate(n int64) bool {
+	if f.config.MaxSize > 0 && f.size > 0 && f.size+n > f.config.MaxSize {
+		return true
+	}
+
+	return !f.rotateAt.IsZero() && !time.Now().Before(f.rotateAt)
+}
+
+// Rotate rotates the file, even if its size or time limits are not reached.
+func (f *RotateFile) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+
+	return f.rotate()
+}
+
+func (f *RotateFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	backup := f.backupName(time.Now())
+	if err := os.Rename(f.filename, backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.mills.Add(1)
+	go f.mill(backup)
+	return nil
+}
+
+// backupName returns a unique name for the file rotated at the "t".
+func (f *RotateFile) backupName(t time.Time) string {
+	ext := filepath.Ext(f.filename)
+	prefix := strings.TrimSuffix(f.filename, ext) + "-"
+
+	name := prefix + t.Format(backupTimeFormat) + ext
+	for i := 1; fileExists(name) || fileExists(name+".gz"); i++ {
+		name = prefix + t.Format(backupTimeFormat) + "-" + strconv.Itoa(i) + ext
+	}
+
+	return name
+}
+
+// mill compresses the "backup" and removes the oldest rotated files.
+func (f *RotateFile) mill(backup string) {
+	defer f.mills.Done()
+
+	f.millMu.Lock()
+	defer f.millMu.Unlock()
+
+	if f.config.Compress {
+		if err := compressFile(backup); err == nil {
+			os.Remove(backup)
+		}
+	}
+
+	if f.config.MaxBackups <= 0 {
+		return
+	}
+
+	backups := f.backups()
+	if len(backups) <= f.config.MaxBackups {
+		return
+	}
+
+	for _, name := range backups[:len(backups)-f.config.MaxBackups] {
+		os.Remove(name)
+	}
+}
+
+// backups returns the rotated files, the oldest first.
+func (f *RotateFile) backups() []string {
+	ext := filepath.Ext(f.filename)
+	prefix := strings.TrimSuffix(f.filename, ext) + "-"
+
+	matches, _ := filepath.Glob(prefix + "*" + ext + "*")
+
+	var backups []string
+	for _, name := range matches {
+		stamp := strings.TrimPrefix(name, prefix)
+		if len(stamp) < len(backupTimeFormat) {
+			continue
+		}
+
+		if _, err := time.Parse(backupTimeFormat, stamp[:len(backupTimeFormat)]); err == nil {
+			backups = append(backups, name)
+		}
+	}
+
+	sort.Strings(backups)
+	return backups
+}
+
+// Close closes the file and waits for the compression of the rotated files.
+func (f *RotateFile) Close() error {
+	f.mu.Lock()
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	f.mu.Unlock()
+
+	f.mills.Wait()
+	return err
+}
+
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(name+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	zw := gzip.NewWriter(dst)
+	if _, err = io.Copy(zw, src); err == nil {
+		err = zw.Close()
+	}
+
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(name + ".gz")
+	}
+
+	return err
+}
+
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
@@ -22,6 +22,14 @@ type BaseController interface {
 	EndRequest(context.Context)
 }
 
+// ErrorHandler is the optional controller interface, if it's
+// completed by the end controller then the HandleError is called
+// when a controller's method returns a non-nil error,
+// instead of writing the error's text with a 400 Bad Request status code, see `hero.DispatchErr`.
+type ErrorHandler interface {
+	HandleError(ctx context.Context, err error)
+}
+
 type shared interface {
 	Name() string
 	Router() router.Party
@@ -40,6 +48,8 @@ type shared interface {
 type BeforeActivation interface {
 	shared
 	Dependencies() *di.Values
+	MethodAttributes(funcName string) *MethodAttributes
+	Child(relativePath string, controller interface{}, middleware ...context.Handler)
 }
 
 // AfterActivation is being used as the onle one input argument of a
@@ -87,6 +97,18 @@ type ControllerActivator struct {
 
 	// initialized on the first `Handle`.
 	injector *di.StructInjector
+
+	// the declared attributes of the methods' routes, key = the controller's function name.
+	attributes map[string]*MethodAttributes
+
+	// the child controllers, activated after this controller.
+	children []*childController
+}
+
+type childController struct {
+	relativePath string
+	controller   interface{}
+	middleware   context.Handlers
 }
 
 // NameOf returns the package name + the struct type's name,
@@ -134,6 +156,10 @@ func whatReservedMethods(typ reflect.Type) map[string]*router.Route {
 		methods = append(methods, "BeginRequest", "EndRequest")
 	}
 
+	if isErrorHandler(typ) {
+		methods = append(methods, "HandleError")
+	}
+
 	routes := make(map[string]*router.Route, len(methods))
 	for _, m := range methods {
 		routes[m] = &router.Route{}
@@ -229,8 +255,40 @@ func (c *ControllerActivator) isReservedMethod(name string) bool {
 	return false
 }
 
+// routerParamsLen returns the number of the path parameters of the router's path,
+// i.e a child controller's "/users/{id:long}/posts", they come first on the route's parameters.
+func (c *ControllerActivator) routerParamsLen() int {
+	tmpl, err := macro.Parse(c.router.GetRelPath(), *c.router.Macros())
+	if err != nil {
+		return 0
+	}
+
+	return len(tmpl.Params)
+}
+
+// Child registers a child controller under the "relativePath" of this controller's router,
+// i.e "/{id:long}/posts" for a "/users" controller serves the "/users/{id:long}/posts".
+// The child controller inherits this controller's dependencies, including
+// the ones that are added on `BeforeActivation`, and it's activated after this controller.
+//
+// The path parameters of the "relativePath" are not binded to the child's methods input arguments,
+// they can be retrieved through the `Context#Params`.
+//
+// Can used at `BeforeActivation`.
+func (c *ControllerActivator) Child(relativePath string, controller interface{}, middleware ...context.Handler) {
+	c.children = append(c.children, &childController{
+		relativePath: relativePath,
+		controller:   controller,
+		middleware:   middleware,
+	})
+}
+
 func (c *ControllerActivator) activate() {
 	c.parseMethods()
+
+	for _, child := range c.children {
+		newApp(c.router.Party(child.relativePath, child.middleware...), c.dependencies.Clone()).Handle(child.controller)
+	}
 }
 
 func (c *ControllerActivator) addErr(err error) bool {
@@ -294,13 +352,18 @@ func (c *ControllerActivator) Handle(method, path, funcName string, middleware .
 	// get the path parameters bindings from the template,
 	// use the function's input except the receiver which is the
 	// end-dev's controller pointer.
-	pathParams := getPathParamsForInput(tmpl.Params, funcIn[1:]...)
+	pathParams := getPathParamsForInput(c.routerParamsLen(), tmpl.Params, funcIn[1:]...)
 	// get the function's input arguments' bindings.
 	funcDependencies := c.dependencies.Clone()
 	funcDependencies.AddValues(pathParams...)
 
 	handler := c.handlerOf(m, funcDependencies)
 
+	attrs, hasAttributes := c.attributes[funcName]
+	if hasAttributes && len(attrs.middleware) > 0 {
+		middleware = append(attrs.middleware[0:len(attrs.middleware):len(attrs.middleware)], middleware...)
+	}
+
 	// register the handler now.
 	route := c.router.Handle(method, path, append(middleware, handler)...)
 	if route == nil {
@@ -312,6 +375,10 @@ func (c *ControllerActivator) Handle(method, path, funcName string, middleware .
 	// a proper debug message.
 	route.MainHandlerName = fmt.Sprintf("%s.%s", c.fullName, funcName)
 
+	if hasAttributes {
+		attrs.applyTo(route)
+	}
+
 	// add this as a reserved method name in order to
 	// be sure that the same func will not be registered again,
 	// even if a custom .Handle later on.
@@ -349,13 +416,31 @@ func (c *ControllerActivator) handlerOf(m reflect.Method, funcDependencies []ref
 		implementsBase        = isBaseController(c.Type)
 		hasBindableFields     = c.injector.CanInject
 		hasBindableFuncInputs = funcInjector.Has
+		hasCleanup            = (hasBindableFields && c.injector.HasCleanup) || funcInjector.HasCleanup
 
 		call = m.Func.Call
 	)
 
+	dispatch := func(ctx context.Context, _ reflect.Value, values []reflect.Value) {
+		hero.DispatchFuncResult(ctx, values)
+	}
+
+	if isErrorHandler(c.Type) {
+		// the controller handles the errors of its methods.
+		dispatch = func(ctx context.Context, ctrl reflect.Value, values []reflect.Value) {
+			if err, ok := lookupErr(values); ok {
+				ctrl.Interface().(ErrorHandler).HandleError(ctx, err)
+				return
+			}
+
+			hero.DispatchFuncResult(ctx, values)
+		}
+	}
+
 	if !implementsBase && !hasBindableFields && !hasBindableFuncInputs {
 		return func(ctx context.Context) {
-			hero.DispatchFuncResult(ctx, call(c.injector.AcquireSlice()))
+			in := c.injector.AcquireSlice()
+			dispatch(ctx, in[0], call(in))
 		}
 	}
 
@@ -366,6 +451,15 @@ func (c *ControllerActivator) handlerOf(m reflect.Method, funcDependencies []ref
 			ctxValue reflect.Value
 		)
 
+		// the cleanup functions of the dependencies are called after the EndRequest, if any,
+		// and on panics too.
+		if hasCleanup {
+			ctxValue = reflect.ValueOf(ctx)
+			if end := di.Cleanup(ctxValue); end != nil {
+				defer end()
+			}
+		}
+
 		// inject struct fields first before the BeginRequest and EndRequest, if any,
 		// in order to be able to have access there.
 		if hasBindableFields {
@@ -402,11 +496,11 @@ func (c *ControllerActivator) handlerOf(m reflect.Method, funcDependencies []ref
 			// 	println("controller.go: execution: in.Value = "+inn.String()+" and in.Type = "+inn.Type().Kind().String()+" of index: ", idxx)
 			// }
 
-			hero.DispatchFuncResult(ctx, call(in))
+			dispatch(ctx, ctrl, call(in))
 			return
 		}
 
-		hero.DispatchFuncResult(ctx, ctrl.Method(m.Index).Call(emptyIn))
+		dispatch(ctx, ctrl, ctrl.Method(m.Index).Call(emptyIn))
 	}
 
 }
@@ -0,0 +1,177 @@
+package mvc
+
+import (
+	"fmt"
+
+	"github.com/hidevopsio/iris/context"
+	"github.com/hidevopsio/iris/core/router"
+)
+
+// MethodAttributes are the attributes of a controller's method route,
+// they are declared on the `BeforeActivation` and they are applied
+// to the method's route when it's registered, the informational ones are kept on the route's `Meta`.
+//
+// Example:
+//
+//	func (c *UserController) BeforeActivation(b mvc.BeforeActivation) {
+//		b.MethodAttributes("GetBy").
+//			Name("user").
+//			Description("Get a user by its id").
+//			Tags("users").
+//			Response(iris.StatusOK, User{}).
+//			Permissions("users:read").
+//			Middleware(auth)
+//	}
+type MethodAttributes struct {
+	c        *ControllerActivator
+	funcName string
+
+	name       string
+	middleware context.Handlers
+	meta       context.RouteMeta
+}
+
+// MethodAttributes returns the attributes of the controller's "funcName" method,
+// see `MethodAttributes` type for more.
+// Can used at `BeforeActivation`.
+func (c *ControllerActivator) MethodAttributes(funcName string) *MethodAttributes {
+	if attrs, ok := c.attributes[funcName]; ok {
+		return attrs
+	}
+
+	attrs := &MethodAttributes{c: c, funcName: funcName}
+	if c.attributes == nil {
+		c.attributes = make(map[string]*MethodAttributes)
+	}
+
+	c.attributes[funcName] = attrs
+	return attrs
+}
+
+// route returns the method's route if it's already registered, i.e by a `Handle` call.
+func (a *MethodAttributes) route() *router.Route {
+	if route, ok := a.c.routes[a.funcName]; ok && route.Method != "" {
+		return route
+	}
+
+	return nil
+}
+
+// Name sets the route's name, which is useful for reverse routing.
+//
+// returns itself.
+func (a *MethodAttributes) Name(name string) *MethodAttributes {
+	a.name = name
+	if route := a.route(); route != nil {
+		route.Name = name
+	}
+
+	return a
+}
+
+// Middleware adds handlers which are executed before the method,
+// they should be declared before the method's route is registered.
+//
+// returns itself.
+func (a *MethodAttributes) Middleware(handlers ...context.Handler) *MethodAttributes {
+	if a.route() != nil {
+		a.c.addErr(fmt.Errorf("MVC: middleware of '%s.%s' should be declared before its route's registration",
+			a.c.fullName, a.funcName))
+		return a
+	}
+
+	a.middleware = append(a.middleware, handlers...)
+	return a
+}
+
+// Description sets the route's description.
+//
+// returns itself.
+func (a *MethodAttributes) Description(description string) *MethodAttributes {
+	a.meta.Description = description
+	return a.apply()
+}
+
+// Tags adds tags to the route, i.e "users".
+//
+// returns itself.
+func (a *MethodAttributes) Tags(tags ...string) *MethodAttributes {
+	a.meta.Tags = append(a.meta.Tags, tags...)
+	return a.apply()
+}
+
+// Response sets an example value of the route's response for the "statusCode",
+// i.e `Response(iris.StatusOK, User{})`.
+//
+// returns itself.
+func (a *MethodAttributes) Response(statusCode int, v interface{}) *MethodAttributes {
+	if a.meta.Responses == nil {
+		a.meta.Responses = make(map[int]interface{})
+	}
+
+	a.meta.Responses[statusCode] = v
+	return a.apply()
+}
+
+// Permissions adds permissions which are required to access the route, i.e "users:read".
+// They are not checked by the mvc itself, an authorization middleware should check them.
+//
+// returns itself.
+func (a *MethodAttributes) Permissions(permissions ...string) *MethodAttributes {
+	a.meta.Permissions = append(a.meta.Permissions, permissions...)
+	return a.apply()
+}
+
+// Meta sets a custom metadata value, its "key" should be unique.
+//
+// returns itself.
+func (a *MethodAttributes) Meta(key string, value interface{}) *MethodAttributes {
+	if a.meta.Values == nil {
+		a.meta.Values = make(map[string]interface{})
+	}
+
+	a.meta.Values[key] = value
+	return a.apply()
+}
+
+// apply sets the metadata to the method's route if it's already registered.
+func (a *MethodAttributes) apply() *MethodAttributes {
+	if route := a.route(); route != nil {
+		a.applyTo(route)
+	}
+
+	return a
+}
+
+func (a *MethodAttributes) applyTo(route *router.Route) {
+	if a.name != "" {
+		route.Name = a.name
+	}
+
+	route.Meta = copyRouteMeta(a.meta)
+}
+
+// copyRouteMeta returns a copy of the "meta", the route's metadata
+// should not share their slices and maps with the attributes which are still modified.
+func copyRouteMeta(meta context.RouteMeta) context.RouteMeta {
+	meta.Tags = append([]string(nil), meta.Tags...)
+	meta.Permissions = append([]string(nil), meta.Permissions...)
+
+	if meta.Responses != nil {
+		responses := make(map[int]interface{}, len(meta.Responses))
+		for statusCode, v := range meta.Responses {
+			responses[statusCode] = v
+		}
+		meta.Responses = responses
+	}
+
+	if meta.Values != nil {
+		values := make(map[string]interface{}, len(meta.Values))
+		for key, value := range meta.Values {
+			values[key] = value
+		}
+		meta.Values = values
+	}
+
+	return meta
+}
@@ -2,11 +2,14 @@
 package mvc_test
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/hidevopsio/iris"
 	"github.com/hidevopsio/iris/context"
 	"github.com/hidevopsio/iris/core/router"
+	"github.com/hidevopsio/iris/hero"
 	"github.com/hidevopsio/iris/httptest"
 
 	. "github.com/hidevopsio/iris/mvc"
@@ -502,3 +505,195 @@ func TestControllerNotCreateNewDueManuallySettingAllFields(t *testing.T) {
 	e.GET("/").Expect().Status(iris.StatusOK).
 		Body().Equal("my title")
 }
+
+type testTx struct {
+	log []string
+}
+
+type testControllerDependencyCleanup struct {
+	Ctx context.Context
+	Tx  *testTx
+}
+
+func (c *testControllerDependencyCleanup) BeginRequest(ctx context.Context) {
+	c.Tx.log = append(c.Tx.log, "begin")
+}
+
+func (c *testControllerDependencyCleanup) EndRequest(ctx context.Context) {
+	c.Tx.log = append(c.Tx.log, "end")
+}
+
+func (c *testControllerDependencyCleanup) Get(tx *testTx) {
+	// the field and the input argument share the same per-request value.
+	tx.log = append(tx.log, "get")
+}
+
+func TestControllerDependencyCleanup(t *testing.T) {
+	var logs []string
+
+	app := iris.New()
+	m := New(app)
+	m.Register(hero.PerRequest(func(ctx context.Context) (*testTx, func()) {
+		tx := new(testTx)
+		return tx, func() {
+			logs = append(logs, strings.Join(append(tx.log, "cleanup"), " "))
+		}
+	}))
+	m.Handle(new(testControllerDependencyCleanup))
+
+	e := httptest.New(t, app)
+	e.GET("/").Expect().Status(iris.StatusOK)
+	e.GET("/").Expect().Status(iris.StatusOK)
+
+	expected := "begin get end cleanup"
+	if len(logs) != 2 || logs[0] != expected || logs[1] != expected {
+		t.Fatalf("expected two requests with '%s' but got: %v", expected, logs)
+	}
+}
+
+type testControllerMethodAttributes struct{}
+
+func (c *testControllerMethodAttributes) BeforeActivation(b BeforeActivation) {
+	b.Handle("GET", "/manual", "Manual")
+	// applied to the already registered route.
+	b.MethodAttributes("Manual").Description("manual route").Tags("manual")
+
+	requirePermission := func(ctx context.Context) {
+		if !ctx.GetCurrentRoute().Meta().HasPermission(ctx.GetHeader("X-Permission")) {
+			ctx.StatusCode(iris.StatusForbidden)
+			return
+		}
+
+		ctx.Next()
+	}
+
+	b.MethodAttributes("GetBy").
+		Name("user").
+		Description("get a user").
+		Tags("users").
+		Response(iris.StatusOK, testBindType{}).
+		Permissions("users:read").
+		Meta("cache", true).
+		Middleware(requirePermission)
+}
+
+func (c *testControllerMethodAttributes) GetBy(id int64) string {
+	return "user"
+}
+
+func (c *testControllerMethodAttributes) Manual() string {
+	return "manual"
+}
+
+func TestControllerMethodAttributes(t *testing.T) {
+	app := iris.New()
+	New(app).Handle(new(testControllerMethodAttributes))
+
+	route := app.GetRoute("user")
+	if route == nil {
+		t.Fatalf("expected the route to be named by its attributes")
+	}
+
+	if expected, got := "get a user", route.Meta.Description; expected != got {
+		t.Fatalf("expected description '%s' but got '%s'", expected, got)
+	}
+
+	if !route.Meta.HasTag("users") || route.Meta.Values["cache"] != true {
+		t.Fatalf("expected the route's metadata to be set but got: %#v", route.Meta)
+	}
+
+	if _, ok := route.Meta.Responses[iris.StatusOK].(testBindType); !ok {
+		t.Fatalf("expected the route's 200 response type to be set but got: %#v", route.Meta.Responses)
+	}
+
+	if manual := app.GetRoute("GET/manual"); manual == nil || manual.Meta.Description != "manual route" {
+		t.Fatalf("expected the attributes to be applied to the registered route")
+	}
+
+	e := httptest.New(t, app)
+	e.GET("/42").Expect().Status(iris.StatusForbidden)
+	e.GET("/42").WithHeader("X-Permission", "users:read").Expect().Status(iris.StatusOK).
+		Body().Equal("user")
+	e.GET("/manual").Expect().Status(iris.StatusOK).Body().Equal("manual")
+}
+
+type testControllerMethodAttributesCopy struct{}
+
+func (c *testControllerMethodAttributesCopy) BeforeActivation(b BeforeActivation) {
+	route := b.Handle("GET", "/", "Get")
+	attrs := b.MethodAttributes("Get").Permissions("users:read").Meta("cache", true)
+
+	// the route's metadata are a copy of the attributes' ones.
+	route.Meta.Permissions[0] = "users:write"
+	route.Meta.Values["cache"] = false
+	attrs.Tags("users")
+}
+
+func (c *testControllerMethodAttributesCopy) Get() string {
+	return "users"
+}
+
+func TestControllerMethodAttributesCopyMeta(t *testing.T) {
+	app := iris.New()
+	New(app).Handle(new(testControllerMethodAttributesCopy))
+
+	route := app.GetRoute("GET/")
+	if route == nil {
+		t.Fatalf("expected the route to be registered")
+	}
+
+	if !route.Meta.HasPermission("users:read") || route.Meta.Values["cache"] != true || !route.Meta.HasTag("users") {
+		t.Fatalf("expected the route's metadata to be the attributes' ones but got: %#v", route.Meta)
+	}
+}
+
+type testControllerUsers struct{}
+
+func (c *testControllerUsers) BeforeActivation(b BeforeActivation) {
+	b.Dependencies().Add(&testBindType{title: "posts of"})
+	b.Child("/{id:long}/posts", new(testControllerUserPosts))
+}
+
+func (c *testControllerUsers) GetBy(id int64) string {
+	return "user"
+}
+
+type testControllerUserPosts struct {
+	Ctx          context.Context
+	TitlePointer *testBindType
+}
+
+var errTestPostNotFound = errors.New("post not found")
+
+func (c *testControllerUserPosts) Get() string {
+	return c.TitlePointer.title + " " + c.Ctx.Params().Get("id")
+}
+
+func (c *testControllerUserPosts) GetBy(postID int64) (string, error) {
+	if postID != 1 {
+		return "", errTestPostNotFound
+	}
+
+	return c.Ctx.Params().Get("id") + ":" + c.Ctx.Params().Get("param1"), nil
+}
+
+func (c *testControllerUserPosts) HandleError(ctx context.Context, err error) {
+	if err == errTestPostNotFound {
+		ctx.StatusCode(iris.StatusNotFound)
+		ctx.WriteString("custom: " + err.Error())
+		return
+	}
+
+	ctx.StatusCode(iris.StatusInternalServerError)
+}
+
+func TestControllerChildAndHandleError(t *testing.T) {
+	app := iris.New()
+	New(app.Party("/users")).Handle(new(testControllerUsers))
+
+	e := httptest.New(t, app)
+	e.GET("/users/42").Expect().Status(iris.StatusOK).Body().Equal("user")
+	e.GET("/users/42/posts").Expect().Status(iris.StatusOK).Body().Equal("posts of 42")
+	e.GET("/users/42/posts/1").Expect().Status(iris.StatusOK).Body().Equal("42:1")
+	e.GET("/users/42/posts/2").Expect().Status(iris.StatusNotFound).Body().Equal("custom: post not found")
+}
@@ -118,6 +118,10 @@ func (app *Application) Register(values ...interface{}) *Application {
 // or/and `AfterActivation(a mvc.AfterActivation)` then these will be called between the controller's `.activate`,
 // use those when you want to modify the controller before or/and after
 // the controller will be registered to the main Iris Application.
+// Child controllers can be registered through the `BeforeActivation#Child`.
+//
+// If "controller" has `HandleError(ctx iris.Context, err error)` then it's called
+// when the controller's methods return a non-nil error.
 //
 // It returns this mvc Application.
 //
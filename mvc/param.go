@@ -7,7 +7,9 @@ import (
 	"github.com/hidevopsio/iris/macro"
 )
 
-func getPathParamsForInput(params []macro.TemplateParam, funcIn ...reflect.Type) (values []reflect.Value) {
+// getPathParamsForInput returns the path parameters bindings of the "funcIn",
+// the "offset" is the number of the parameters which come before the "params" on the route's path.
+func getPathParamsForInput(offset int, params []macro.TemplateParam, funcIn ...reflect.Type) (values []reflect.Value) {
 	if len(funcIn) == 0 || len(params) == 0 {
 		return
 	}
@@ -39,7 +41,7 @@ func getPathParamsForInput(params []macro.TemplateParam, funcIn ...reflect.Type)
 		if len(funcIn) <= i {
 			return
 		}
-		funcDep, ok := context.ParamResolverByTypeAndIndex(funcIn[i], param.Index)
+		funcDep, ok := context.ParamResolverByTypeAndIndex(funcIn[i], offset+param.Index)
 		if !ok {
 			continue
 		}
@@ -8,6 +8,27 @@ func isBaseController(ctrlTyp reflect.Type) bool {
 	return ctrlTyp.Implements(baseControllerTyp)
 }
 
+var errorHandlerTyp = reflect.TypeOf((*ErrorHandler)(nil)).Elem()
+
+func isErrorHandler(ctrlTyp reflect.Type) bool {
+	return ctrlTyp.Implements(errorHandlerTyp)
+}
+
+// lookupErr returns the first non-nil error of a method's output values.
+func lookupErr(values []reflect.Value) (error, bool) {
+	for _, v := range values {
+		if !v.IsValid() || !v.CanInterface() {
+			continue
+		}
+
+		if err, ok := v.Interface().(error); ok && err != nil {
+			return err, true
+		}
+	}
+
+	return nil, false
+}
+
 func getInputArgsFromFunc(funcTyp reflect.Type) []reflect.Type {
 	n := funcTyp.NumIn()
 	funcIn := make([]reflect.Type, n, n)
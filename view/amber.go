@@ -4,11 +4,17 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/eknkc/amber"
+	"github.com/hidevopsio/iris/context"
 )
 
 // AmberEngine contains the amber view engine structure.
@@ -16,24 +22,29 @@ type AmberEngine struct {
 	// files configuration
 	directory string
 	extension string
-	assetFn   func(name string) ([]byte, error) // for embedded, in combination with directory & extension
-	namesFn   func() []string                   // for embedded, in combination with directory & extension
+	fs        http.FileSystem // for embedded or virtual files, in combination with directory & extension
 	reload    bool
+	stream    bool
 	//
-	rmu           sync.RWMutex // locks for `ExecuteWiter` when `reload` is true.
-	funcs         map[string]interface{}
-	templateCache map[string]*template.Template
+	rmu   sync.RWMutex // locks for funcs.
+	funcs map[string]interface{}
+	// the loaded templates, which are rendered, they are replaced on reload.
+	loaded    atomic.Value // map[string]*template.Template
+	fragments fragmentCache
+	watcher   watcher
 }
 
-var _ Engine = &AmberEngine{}
+var (
+	_ Engine           = &AmberEngine{}
+	_ EngineFragmenter = &AmberEngine{}
+)
 
 // Amber creates and returns a new amber view engine.
 func Amber(directory, extension string) *AmberEngine {
 	s := &AmberEngine{
-		directory:     directory,
-		extension:     extension,
-		templateCache: make(map[string]*template.Template, 0),
-		funcs:         make(map[string]interface{}, 0),
+		directory: directory,
+		extension: extension,
+		funcs:     make(map[string]interface{}, 0),
 	}
 
 	return s
@@ -48,26 +59,57 @@ func (s *AmberEngine) Ext() string {
 // inside the app executable (.go generated files).
 //
 // The assetFn and namesFn can come from the go-bindata library.
+// It's a shortcut of the `FS(context.BindataFS(assetFn, namesFn))`.
 func (s *AmberEngine) Binary(assetFn func(name string) ([]byte, error), namesFn func() []string) *AmberEngine {
-	s.assetFn, s.namesFn = assetFn, namesFn
+	return s.FS(context.BindataFS(assetFn, namesFn))
+}
+
+// FS optionally, use it when template files are loaded from a virtual file system
+// instead of the system's directory, the templates are the "directory" of the "fs"
+// i.e an `http.FS(embedFS)`, a `context.BindataFS` or a `context.OverlayFS`
+// which can shadow embedded templates with local ones.
+func (s *AmberEngine) FS(fs http.FileSystem) *AmberEngine {
+	s.fs = fs
 	return s
 }
 
-// Reload if setted to true the templates are reloading on each render,
-// use it when you're in development and you're boring of restarting
+// Reload if setted to true the templates directory is watched for changes
+// and the changed template files, and the templates which extend or import them,
+// are compiled again, use it when you're in development and you're boring of restarting
 // the whole app when you edit a template file.
 //
-// Note that if `true` is passed then only one `View -> ExecuteWriter` will be render each time,
-// no concurrent access across clients, use it only on development status.
+// The renders are not blocked while the templates are reloaded
+// and if a changed template can't be compiled then the `ReloadErrorHandler` is called
+// and the previously loaded templates are still rendered.
+// See `WatchInterval` and `Close` too.
+//
+// It has no effect on embedded templates, see `Binary` and `FS`.
 // It's good to be used side by side with the https://github.com/kataras/rizla reloader for go source files.
 func (s *AmberEngine) Reload(developmentMode bool) *AmberEngine {
 	s.reload = developmentMode
 	return s
 }
 
+// Close stops watching the templates directory, see `Reload`.
+// It's called by the `iris#Application.Shutdown`.
+func (s *AmberEngine) Close() error {
+	s.watcher.close()
+	return nil
+}
+
+// Stream if setted to true the templates are written directly to the response
+// while they are executed and they are flushed to the client in chunks of `StreamFlushSize` bytes,
+// instead of being sent when the whole template is executed.
+//
+// Note that the status code can't be changed on a template's execution error.
+func (s *AmberEngine) Stream(enable bool) *AmberEngine {
+	s.stream = enable
+	return s
+}
+
 // AddFunc adds the function to the template's function map.
 // It is legal to overwrite elements of the default actions:
-// - url func(routeName string, args ...string) string
+// - url func(routeName string, pairs ...interface{}) string
 // - urlpath func(routeName string, args ...string) string
 // - render func(fullPartialName string) (template.HTML, error).
 func (s *AmberEngine) AddFunc(funcName string, funcBody interface{}) {
@@ -81,7 +123,9 @@ func (s *AmberEngine) AddFunc(funcName string, funcBody interface{}) {
 //
 // Returns an error if something bad happens, user is responsible to catch it.
 func (s *AmberEngine) Load() error {
-	if s.assetFn != nil && s.namesFn != nil {
+	s.fragments.reset()
+
+	if s.fs != nil {
 		// embedded
 		return s.loadAssets()
 	}
@@ -119,78 +163,116 @@ func (s *AmberEngine) loadDirectory() error {
 
 	templates, err := amber.CompileDir(dir, opt, amber.DefaultOptions) // this returns the map with stripped extension, we want extension so we copy the map
 	if err == nil {
-		s.templateCache = make(map[string]*template.Template)
+		templateCache := make(map[string]*template.Template)
 		for k, v := range templates {
 			name := filepath.ToSlash(k + opt.Ext)
-			s.templateCache[name] = v
+			templateCache[name] = v
 			delete(templates, k)
 		}
 
+		s.loaded.Store(templateCache)
+		if s.reload {
+			s.watcher.watch(dir, extension, s.reloadFiles)
+		}
 	}
 	return err
 }
 
-// loadAssets builds the templates by binary, embedded.
-func (s *AmberEngine) loadAssets() error {
-	virtualDirectory, virtualExtension := s.directory, s.extension
-	assetFn, namesFn := s.assetFn, s.namesFn
+var amberDependency = regexp.MustCompile(`(?m)^\s*(?:extends|import)\s+(\S+)\s*$`)
 
-	// prepare the global amber funcs
-	funcs := template.FuncMap{}
+// reloadFiles compiles the "changed" template files and the templates which depend on them
+// and removes the "removed" ones.
+func (s *AmberEngine) reloadFiles(changed, removed []string) {
+	prev := s.templates()
+	templateCache := make(map[string]*template.Template, len(prev))
+	for name, tmpl := range prev {
+		templateCache[name] = tmpl
+	}
 
-	for k, v := range amber.FuncMap { // add the amber's default funcs
-		funcs[k] = v
+	for _, name := range removed {
+		delete(templateCache, name)
 	}
 
-	for k, v := range s.funcs {
-		funcs[k] = v
+	names := make([]string, 0, len(templateCache))
+	for name := range scanTemplates(s.directory, s.extension) {
+		names = append(names, name)
 	}
 
-	if len(virtualDirectory) > 0 {
-		if virtualDirectory[0] == '.' { // first check for .wrong
-			virtualDirectory = virtualDirectory[1:]
+	deps := func(name string) (deps []string) {
+		source, err := s.readSource(name)
+		if err != nil {
+			return
 		}
-		if virtualDirectory[0] == '/' || virtualDirectory[0] == filepath.Separator { // second check for /something, (or ./something if we had dot on 0 it will be removed
-			virtualDirectory = virtualDirectory[1:]
+
+		for _, sm := range amberDependency.FindAllStringSubmatch(string(source), -1) {
+			deps = append(deps, templateDep(name, sm[1], s.extension, true))
 		}
+		return
 	}
-	amber.FuncMap = funcs //set the funcs
 
-	names := namesFn()
+	for _, name := range withDependents(append(changed, removed...), names, deps) {
+		if _, err := os.Stat(filepath.Join(s.directory, filepath.FromSlash(name))); os.IsNotExist(err) {
+			continue // removed.
+		}
 
-	for _, path := range names {
-		if !strings.HasPrefix(path, virtualDirectory) {
-			continue
+		tmpl, err := amber.CompileFile(filepath.Join(s.directory, filepath.FromSlash(name)), amber.DefaultOptions)
+		if err != nil {
+			ReloadErrorHandler(fmt.Errorf("%s: %v", name, err))
+			return
 		}
-		ext := filepath.Ext(path)
-		if ext == virtualExtension {
 
-			rel, err := filepath.Rel(virtualDirectory, path)
-			if err != nil {
-				return err
-			}
+		templateCache[name] = tmpl
+	}
 
-			buf, err := assetFn(path)
-			if err != nil {
-				return err
-			}
+	s.loaded.Store(templateCache)
+	s.fragments.reset()
+}
 
-			name := filepath.ToSlash(rel)
-			tmpl, err := amber.CompileData(buf, name, amber.DefaultOptions)
+// templates returns the templates which are rendered.
+func (s *AmberEngine) templates() map[string]*template.Template {
+	templates, _ := s.loaded.Load().(map[string]*template.Template)
+	return templates
+}
 
-			if err != nil {
-				return err
-			}
+// loadAssets builds the templates of the virtual file system (i.e go-bindata for embedded).
+func (s *AmberEngine) loadAssets() error {
+	// prepare the global amber funcs
+	funcs := template.FuncMap{}
+
+	for k, v := range amber.FuncMap { // add the amber's default funcs
+		funcs[k] = v
+	}
+
+	for k, v := range s.funcs {
+		funcs[k] = v
+	}
+
+	amber.FuncMap = funcs //set the funcs
 
-			s.templateCache[name] = tmpl
+	// the extended and imported templates are read from the templates directory of the file system.
+	opts := amber.DefaultOptions
+	opts.VirtualFilesystem = context.SubFS(s.fs, s.directory)
+
+	templateCache := make(map[string]*template.Template)
+	err := walkTemplates(s.fs, s.directory, s.extension, func(name string, buf []byte) error {
+		tmpl, err := amber.CompileData(buf, name, opts)
+		if err != nil {
+			return err
 		}
+
+		templateCache[name] = tmpl
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
+	s.loaded.Store(templateCache)
 	return nil
 }
 
 func (s *AmberEngine) fromCache(relativeName string) *template.Template {
-	tmpl, ok := s.templateCache[relativeName]
+	tmpl, ok := s.templates()[relativeName]
 	if ok {
 		return tmpl
 	}
@@ -200,20 +282,97 @@ func (s *AmberEngine) fromCache(relativeName string) *template.Template {
 // ExecuteWriter executes a template and writes its result to the w writer.
 // layout here is useless.
 func (s *AmberEngine) ExecuteWriter(w io.Writer, filename string, layout string, bindingData interface{}) error {
-	// re-parse the templates if reload is enabled.
-	if s.reload {
-		// locks to fix #872, it's the simplest solution and the most correct,
-		// to execute writers with "wait list", one at a time.
-		s.rmu.Lock()
-		defer s.rmu.Unlock()
-		if err := s.Load(); err != nil {
-			return err
+	if tmpl := s.fromCache(filename); tmpl != nil {
+		return stream(w, s.stream, func(w io.Writer) error {
+			return tmpl.Execute(w, bindingData)
+		})
+	}
+
+	return fmt.Errorf("Template with name %s doesn't exists in the dir", filename)
+}
+
+// ExecuteFragment executes the "block" named block of the "filename" template
+// and writes its result to the w writer.
+//
+// Note that the block is compiled as a standalone template,
+// the mixins of the rest of the template are not available.
+func (s *AmberEngine) ExecuteFragment(w io.Writer, filename string, block string, bindingData interface{}) error {
+	if s.fromCache(filename) == nil {
+		return fmt.Errorf("Template with name %s doesn't exists in the dir", filename)
+	}
+
+	fragment, err := s.fragments.get(filename, block, func() (interface{}, error) {
+		source, err := s.readSource(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		blockSource, ok := amberBlockSource(string(source), block)
+		if !ok {
+			return nil, fmt.Errorf("block %s of the template %s doesn't exist", block, filename)
 		}
+
+		return amber.Compile(blockSource, amber.DefaultOptions)
+	})
+	if err != nil {
+		return err
 	}
 
-	if tmpl := s.fromCache(filename); tmpl != nil {
-		return tmpl.Execute(w, bindingData)
+	return stream(w, s.stream, func(w io.Writer) error {
+		return fragment.(*template.Template).Execute(w, bindingData)
+	})
+}
+
+func (s *AmberEngine) readSource(filename string) ([]byte, error) {
+	if s.fs != nil {
+		return context.ReadFile(context.SubFS(s.fs, s.directory), filename)
 	}
 
-	return fmt.Errorf("Template with name %s doesn't exists in the dir", filename)
+	return ioutil.ReadFile(filepath.Join(s.directory, filepath.FromSlash(filename)))
+}
+
+var amberBlock = regexp.MustCompile(`^block\s+(?:(?:append|prepend)\s+)?([0-9a-zA-Z_\-\. \/]*)$`)
+
+// amberBlockSource returns the contents of the "block" named block of the amber template's "source",
+// the indented lines under its declaration, without their indentation.
+func amberBlockSource(source string, block string) (string, bool) {
+	lines := strings.Split(source, "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		sm := amberBlock.FindStringSubmatch(trimmed)
+		if len(sm) == 0 || strings.TrimSpace(sm[1]) != block {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		childIndent := -1
+		var children []string
+		for _, child := range lines[i+1:] {
+			if strings.TrimSpace(child) == "" {
+				children = append(children, "")
+				continue
+			}
+
+			n := len(child) - len(strings.TrimLeft(child, " \t"))
+			if n <= indent {
+				break
+			}
+
+			if childIndent == -1 || n < childIndent {
+				childIndent = n
+			}
+
+			children = append(children, child)
+		}
+
+		for j, child := range children {
+			if child != "" {
+				children[j] = child[childIndent:]
+			}
+		}
+
+		return strings.Join(children, "\n"), true
+	}
+
+	return "", false
 }
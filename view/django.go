@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"os"
 	stdPath "path"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/flosch/pongo2"
 	"github.com/hidevopsio/iris/context"
@@ -81,8 +84,8 @@ func (token *Token) GetToken() *pongo2.Token {
 }
 
 type tDjangoAssetLoader struct {
-	baseDir  string
-	assetGet func(name string) ([]byte, error)
+	baseDir string
+	fs      http.FileSystem
 }
 
 // Abs calculates the path to a given template. Whenever a path must be resolved
@@ -97,11 +100,7 @@ func (dal *tDjangoAssetLoader) Abs(base, name string) string {
 
 // Get returns an io.Reader where the template's content can be read from.
 func (dal *tDjangoAssetLoader) Get(path string) (io.Reader, error) {
-	if stdPath.IsAbs(path) {
-		path = path[1:]
-	}
-
-	res, err := dal.assetGet(path)
+	res, err := context.ReadFile(dal.fs, path)
 	if err != nil {
 		return nil, err
 	}
@@ -114,29 +113,61 @@ type DjangoEngine struct {
 	// files configuration
 	directory string
 	extension string
-	assetFn   func(name string) ([]byte, error) // for embedded, in combination with directory & extension
-	namesFn   func() []string                   // for embedded, in combination with directory & extension
+	fs        http.FileSystem // for embedded or virtual files, in combination with directory & extension
 	reload    bool
+	stream    bool
 	//
-	rmu sync.RWMutex // locks for filters, globals and `ExecuteWiter` when `reload` is true.
+	rmu sync.RWMutex // locks for filters and globals.
 	// filters for pongo2, map[name of the filter] the filter function . The filters are auto register
 	filters map[string]FilterFunction
 	// globals share context fields between templates. https://github.com/flosch/pongo2/issues/35
-	globals       map[string]interface{}
-	mu            sync.Mutex // locks for template cache
-	templateCache map[string]*pongo2.Template
+	globals map[string]interface{}
+	// the loaded templates, which are rendered, they are replaced on reload.
+	loaded    atomic.Value // *djangoTemplates
+	fragments fragmentCache
+	watcher   watcher
+}
+
+// djangoTemplates are the loaded templates of the django view engine,
+// their sources are used to compile their fragments and to reload them.
+type djangoTemplates struct {
+	set       *pongo2.TemplateSet
+	templates map[string]*pongo2.Template
+	sources   map[string]string
+}
+
+func newDjangoTemplates(set *pongo2.TemplateSet) *djangoTemplates {
+	return &djangoTemplates{
+		set:       set,
+		templates: make(map[string]*pongo2.Template),
+		sources:   make(map[string]string),
+	}
 }
 
-var _ Engine = &DjangoEngine{}
+// add compiles and adds the "source" of the "name" template.
+func (t *djangoTemplates) add(name string, source string) error {
+	tmpl, err := t.set.FromString(source)
+	if err != nil {
+		return err
+	}
+
+	t.templates[name] = tmpl
+	t.sources[name] = source
+	return nil
+}
+
+var (
+	_ Engine           = &DjangoEngine{}
+	_ EngineFragmenter = &DjangoEngine{}
+)
 
 // Django creates and returns a new amber view engine.
 func Django(directory, extension string) *DjangoEngine {
 	s := &DjangoEngine{
-		directory:     directory,
-		extension:     extension,
-		globals:       make(map[string]interface{}, 0),
-		filters:       make(map[string]FilterFunction, 0),
-		templateCache: make(map[string]*pongo2.Template, 0),
+		directory: directory,
+		extension: extension,
+		globals:   make(map[string]interface{}, 0),
+		filters:   make(map[string]FilterFunction, 0),
 	}
 
 	return s
@@ -151,26 +182,57 @@ func (s *DjangoEngine) Ext() string {
 // inside the app executable (.go generated files).
 //
 // The assetFn and namesFn can come from the go-bindata library.
+// It's a shortcut of the `FS(context.BindataFS(assetFn, namesFn))`.
 func (s *DjangoEngine) Binary(assetFn func(name string) ([]byte, error), namesFn func() []string) *DjangoEngine {
-	s.assetFn, s.namesFn = assetFn, namesFn
+	return s.FS(context.BindataFS(assetFn, namesFn))
+}
+
+// FS optionally, use it when template files are loaded from a virtual file system
+// instead of the system's directory, the templates are the "directory" of the "fs"
+// i.e an `http.FS(embedFS)`, a `context.BindataFS` or a `context.OverlayFS`
+// which can shadow embedded templates with local ones.
+func (s *DjangoEngine) FS(fs http.FileSystem) *DjangoEngine {
+	s.fs = fs
 	return s
 }
 
-// Reload if setted to true the templates are reloading on each render,
-// use it when you're in development and you're boring of restarting
+// Reload if setted to true the templates directory is watched for changes
+// and the changed template files, and the templates which extend, include or import them,
+// are parsed again, use it when you're in development and you're boring of restarting
 // the whole app when you edit a template file.
 //
-// Note that if `true` is passed then only one `View -> ExecuteWriter` will be render each time,
-// no concurrent access across clients, use it only on development status.
+// The renders are not blocked while the templates are reloaded
+// and if a changed template can't be parsed then the `ReloadErrorHandler` is called
+// and the previously loaded templates are still rendered.
+// See `WatchInterval` and `Close` too.
+//
+// It has no effect on embedded templates, see `Binary` and `FS`.
 // It's good to be used side by side with the https://github.com/kataras/rizla reloader for go source files.
 func (s *DjangoEngine) Reload(developmentMode bool) *DjangoEngine {
 	s.reload = developmentMode
 	return s
 }
 
+// Close stops watching the templates directory, see `Reload`.
+// It's called by the `iris#Application.Shutdown`.
+func (s *DjangoEngine) Close() error {
+	s.watcher.close()
+	return nil
+}
+
+// Stream if setted to true the templates are written directly to the response
+// while they are executed and they are flushed to the client in chunks of `StreamFlushSize` bytes,
+// instead of being buffered and sent when the whole template is executed.
+//
+// Note that the status code can't be changed on a template's execution error.
+func (s *DjangoEngine) Stream(enable bool) *DjangoEngine {
+	s.stream = enable
+	return s
+}
+
 // AddFunc adds the function to the template's Globals.
 // It is legal to overwrite elements of the default actions:
-// - url func(routeName string, args ...string) string
+// - url func(routeName string, pairs ...interface{}) string
 // - urlpath func(routeName string, args ...string) string
 // - render func(fullPartialName string) (template.HTML, error).
 func (s *DjangoEngine) AddFunc(funcName string, funcBody interface{}) {
@@ -231,7 +293,7 @@ func (s *DjangoEngine) RegisterTag(tagName string, parserFn TagParser) error {
 // Returns an error if something bad happens, user is responsible to catch it.
 func (s *DjangoEngine) Load() error {
 
-	if s.assetFn != nil && s.namesFn != nil {
+	if s.fs != nil {
 		// embedded
 		return s.loadAssets()
 	}
@@ -257,9 +319,7 @@ func (s *DjangoEngine) loadDirectory() (templateErr error) {
 
 	set := pongo2.NewSet("", fsLoader)
 	set.Globals = getPongoContext(s.globals)
-
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	loaded := newDjangoTemplates(set)
 
 	// Walk the supplied directory and compile any files that match our extension list.
 	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
@@ -287,8 +347,7 @@ func (s *DjangoEngine) loadDirectory() (templateErr error) {
 				}
 				name := filepath.ToSlash(rel)
 
-				s.templateCache[name], templateErr = set.FromString(string(buf))
-
+				templateErr = loaded.add(name, string(buf))
 				if templateErr != nil {
 					return templateErr
 				}
@@ -298,62 +357,107 @@ func (s *DjangoEngine) loadDirectory() (templateErr error) {
 		return nil
 	})
 
+	if templateErr != nil {
+		return
+	}
+
+	s.setTemplates(loaded)
+	if s.reload {
+		s.watcher.watch(dir, extension, s.reloadFiles)
+	}
+
 	return
 }
 
-// loadAssets loads the templates by binary (go-bindata for embedded).
-func (s *DjangoEngine) loadAssets() error {
+var djangoDependencyTag = regexp.MustCompile(`{%-?\s*(?:extends|include|import)\s+["']([^"']+)["']`)
+
+// reloadFiles parses the "changed" template files and the templates which depend on them
+// and removes the "removed" ones.
+func (s *DjangoEngine) reloadFiles(changed, removed []string) {
+	prev := s.templates()
+	loaded := newDjangoTemplates(prev.set)
+	for name, tmpl := range prev.templates {
+		loaded.templates[name] = tmpl
+	}
 
-	virtualDirectory, virtualExtension := s.directory, s.extension
-	assetFn, namesFn := s.assetFn, s.namesFn
+	for name, source := range prev.sources {
+		loaded.sources[name] = source
+	}
 
-	// Make a file set with a template loader based on asset function
-	set := pongo2.NewSet("", &tDjangoAssetLoader{baseDir: s.directory, assetGet: s.assetFn})
-	set.Globals = getPongoContext(s.globals)
+	for _, name := range removed {
+		delete(loaded.templates, name)
+		delete(loaded.sources, name)
+	}
 
-	if len(virtualDirectory) > 0 {
-		if virtualDirectory[0] == '.' { // first check for .wrong
-			virtualDirectory = virtualDirectory[1:]
-		}
-		if virtualDirectory[0] == '/' || virtualDirectory[0] == os.PathSeparator { // second check for /something, (or ./something if we had dot on 0 it will be removed
-			virtualDirectory = virtualDirectory[1:]
+	for _, name := range changed {
+		buf, err := ioutil.ReadFile(filepath.Join(s.directory, filepath.FromSlash(name)))
+		if err != nil {
+			ReloadErrorHandler(err)
+			return
 		}
+
+		loaded.sources[name] = string(buf)
 	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	names := make([]string, 0, len(loaded.sources))
+	for name := range loaded.sources {
+		names = append(names, name)
+	}
 
-	var templateErr error
+	deps := func(name string) (deps []string) {
+		for _, sm := range djangoDependencyTag.FindAllStringSubmatch(loaded.sources[name], -1) {
+			deps = append(deps, templateDep(name, sm[1], s.extension, false))
+		}
+		return
+	}
 
-	names := namesFn()
-	for _, path := range names {
-		if !strings.HasPrefix(path, virtualDirectory) {
+	for _, name := range withDependents(append(changed, removed...), names, deps) {
+		source, ok := loaded.sources[name]
+		if !ok { // removed.
 			continue
 		}
 
-		rel, err := filepath.Rel(virtualDirectory, path)
-		if err != nil {
-			templateErr = err
-			return err
+		if err := loaded.add(name, source); err != nil {
+			ReloadErrorHandler(fmt.Errorf("%s: %v", name, err))
+			return
 		}
+	}
 
-		ext := filepath.Ext(rel)
-		if ext == virtualExtension {
+	s.setTemplates(loaded)
+}
 
-			buf, err := assetFn(path)
-			if err != nil {
-				templateErr = err
-				return err
-			}
-			name := filepath.ToSlash(rel)
-			s.templateCache[name], err = set.FromString(string(buf))
-			if err != nil {
-				templateErr = err
-				return err
-			}
-		}
+// setTemplates sets the templates which are rendered.
+func (s *DjangoEngine) setTemplates(loaded *djangoTemplates) {
+	s.loaded.Store(loaded)
+	s.fragments.reset()
+}
+
+// templates returns the templates which are rendered.
+func (s *DjangoEngine) templates() *djangoTemplates {
+	loaded, _ := s.loaded.Load().(*djangoTemplates)
+	if loaded == nil {
+		return newDjangoTemplates(nil)
 	}
-	return templateErr
+
+	return loaded
+}
+
+// loadAssets loads the templates of the virtual file system (i.e go-bindata for embedded).
+func (s *DjangoEngine) loadAssets() error {
+	// Make a file set with a template loader based on the file system.
+	set := pongo2.NewSet("", &tDjangoAssetLoader{baseDir: s.directory, fs: s.fs})
+	set.Globals = getPongoContext(s.globals)
+
+	loaded := newDjangoTemplates(set)
+	err := walkTemplates(s.fs, s.directory, s.extension, func(name string, buf []byte) error {
+		return loaded.add(name, string(buf))
+	})
+	if err != nil {
+		return err
+	}
+
+	s.setTemplates(loaded)
+	return nil
 }
 
 // getPongoContext returns the pongo2.Context from map[string]interface{} or from pongo2.Context, used internaly
@@ -374,35 +478,82 @@ func getPongoContext(templateData interface{}) pongo2.Context {
 }
 
 func (s *DjangoEngine) fromCache(relativeName string) *pongo2.Template {
-	s.mu.Lock()
-
-	tmpl, ok := s.templateCache[relativeName]
-
-	if ok {
-		s.mu.Unlock()
-		return tmpl
-	}
-	s.mu.Unlock()
-	return nil
+	return s.templates().templates[relativeName]
 }
 
 // ExecuteWriter executes a templates and write its results to the w writer
 // layout here is useless.
 func (s *DjangoEngine) ExecuteWriter(w io.Writer, filename string, layout string, bindingData interface{}) error {
-	// re-parse the templates if reload is enabled.
-	if s.reload {
-		// locks to fix #872, it's the simplest solution and the most correct,
-		// to execute writers with "wait list", one at a time.
-		s.rmu.Lock()
-		defer s.rmu.Unlock()
-		if err := s.Load(); err != nil {
-			return err
-		}
+	if tmpl := s.fromCache(filename); tmpl != nil {
+		return s.execute(w, tmpl, bindingData)
 	}
 
-	if tmpl := s.fromCache(filename); tmpl != nil {
+	return fmt.Errorf("template with name %s doesn't exists in the dir", filename)
+}
+
+func (s *DjangoEngine) execute(w io.Writer, tmpl *pongo2.Template, bindingData interface{}) error {
+	if !s.stream {
 		return tmpl.ExecuteWriter(getPongoContext(bindingData), w)
 	}
 
-	return fmt.Errorf("template with name %s doesn't exists in the dir", filename)
+	return stream(w, true, func(w io.Writer) error {
+		return tmpl.ExecuteWriterUnbuffered(getPongoContext(bindingData), w)
+	})
+}
+
+// ExecuteFragment executes the {% block "block" %} of the "filename" template
+// and writes its result to the w writer.
+//
+// Note that the block is executed as a standalone template,
+// the {{ block.Super }} of a child template is not available.
+func (s *DjangoEngine) ExecuteFragment(w io.Writer, filename string, block string, bindingData interface{}) error {
+	fragment, err := s.fragments.get(filename, block, func() (interface{}, error) {
+		loaded := s.templates()
+		source, ok := loaded.sources[filename]
+		if !ok {
+			return nil, fmt.Errorf("template with name %s doesn't exists in the dir", filename)
+		}
+
+		blockSource, ok := djangoBlockSource(source, block)
+		if !ok {
+			return nil, fmt.Errorf("block %s of the template %s doesn't exist", block, filename)
+		}
+
+		return loaded.set.FromString(blockSource)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.execute(w, fragment.(*pongo2.Template), bindingData)
+}
+
+var djangoBlockTag = regexp.MustCompile(`{%-?\s*(block\s+(\w+)|endblock)\b[^%]*-?%}`)
+
+// djangoBlockSource returns the contents of the "block" of the django template's "source",
+// the nested blocks are part of it.
+func djangoBlockSource(source string, block string) (string, bool) {
+	depth, start := 0, 0
+	for _, loc := range djangoBlockTag.FindAllStringSubmatchIndex(source, -1) {
+		tag := source[loc[2]:loc[3]]
+		if strings.HasPrefix(tag, "endblock") {
+			if depth == 0 {
+				continue
+			}
+
+			if depth--; depth == 0 {
+				return source[start:loc[0]], true
+			}
+
+			continue
+		}
+
+		if depth > 0 {
+			depth++
+		} else if source[loc[4]:loc[5]] == block {
+			depth, start = 1, loc[1]
+		}
+	}
+
+	return "", false
 }
@@ -0,0 +1,58 @@
+package view
+
+import (
+	"io"
+	"sync"
+)
+
+// EngineFragmenter is an addition of a view engine,
+// if a view engine implements that interface
+// then it can render a single named block (fragment) of a template,
+// i.e for partial page updates of libraries like the htmx.
+//
+// The html, django, handlebars and amber view engines implement it.
+type EngineFragmenter interface {
+	// ExecuteFragment should execute the "block" of the "filename" template
+	// with the bindingData, without its layout.
+	ExecuteFragment(w io.Writer, filename string, block string, bindingData interface{}) error
+}
+
+// fragmentCache is a cache of the compiled fragments of the templates,
+// by their filename and block, it's reset on each engine's `Load`.
+type fragmentCache struct {
+	mu    sync.RWMutex
+	items map[string]interface{}
+}
+
+// get returns the cached fragment of the "filename"'s "block",
+// if it's not cached then it calls the "compile" and caches its result.
+func (c *fragmentCache) get(filename, block string, compile func() (interface{}, error)) (interface{}, error) {
+	key := filename + "#" + block
+
+	c.mu.RLock()
+	item, ok := c.items[key]
+	c.mu.RUnlock()
+	if ok {
+		return item, nil
+	}
+
+	item, err := compile()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.items == nil {
+		c.items = make(map[string]interface{})
+	}
+	c.items[key] = item
+	c.mu.Unlock()
+
+	return item, nil
+}
+
+func (c *fragmentCache) reset() {
+	c.mu.Lock()
+	c.items = nil
+	c.mu.Unlock()
+}
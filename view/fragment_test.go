@@ -0,0 +1,82 @@
+package view_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/httptest"
+	"github.com/hidevopsio/iris/view"
+)
+
+func writeTemplates(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "iris-view")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, contents := range files {
+		if err = ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), os.FileMode(0644)); err != nil {
+			os.RemoveAll(dir)
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestViewFragment(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"index.html": `<h1>{{.Title}}</h1>{{block "users" .}}<ul>{{range .Users}}<li>{{.}}</li>{{end}}</ul>{{end}}`,
+		"index.django": `<h1>{{Title}}</h1>{% block users %}<ul>{% for u in Users %}<li>{{u}}</li>{% endfor %}` +
+			`{% block count %}{{Users|length}}{% endblock %}</ul>{% endblock %}<footer></footer>`,
+		"index.hbs":   `<h1>{{Title}}</h1>{{#block "users"}}<ul>{{#each Users}}<li>{{this}}</li>{{/each}}</ul>{{/block}}`,
+		"index.amber": "h1 #{Title}\nblock users\n\tul\n\t\teach $u in Users\n\t\t\tli #{$u}\np footer\n",
+	})
+	defer os.RemoveAll(dir)
+
+	app := iris.New()
+	app.RegisterView(view.HTML(dir, ".html"))
+	app.RegisterView(view.Django(dir, ".django").Stream(true))
+	app.RegisterView(view.Handlebars(dir, ".hbs"))
+	app.RegisterView(view.Amber(dir, ".amber").Stream(true))
+
+	data := map[string]interface{}{
+		"Title": "Users",
+		"Users": []string{"a", "b"},
+	}
+
+	app.Get("/{ext}", func(ctx iris.Context) {
+		ctx.View("index."+ctx.Params().Get("ext"), data)
+	})
+	app.Get("/{ext}/{block}", func(ctx iris.Context) {
+		ctx.ViewData("Title", data["Title"])
+		ctx.ViewData("Users", data["Users"])
+		ctx.ViewFragment("index."+ctx.Params().Get("ext"), ctx.Params().Get("block"))
+	})
+
+	e := httptest.New(t, app)
+
+	for _, ext := range []string{"html", "django", "hbs", "amber"} {
+		e.GET("/" + ext).Expect().Status(httptest.StatusOK).Body().Contains("<h1>Users</h1>")
+
+		body := e.GET("/" + ext + "/users").Expect().Status(httptest.StatusOK).
+			ContentType("text/html").Body().Raw()
+		// the amber templates are indented.
+		body = strings.Join(strings.Fields(body), "")
+		if !strings.HasPrefix(body, "<ul>") || !strings.Contains(body, "<li>a</li><li>b</li>") ||
+			strings.Contains(body, "<h1>") || strings.Contains(body, "footer") {
+			t.Fatalf("[%s] expected the users fragment but got: '%s'", ext, body)
+		}
+
+		e.GET("/" + ext + "/missing").Expect().Status(httptest.StatusInternalServerError)
+	}
+
+	// nested blocks.
+	e.GET("/django/count").Expect().Status(httptest.StatusOK).Body().Equal("2")
+}
@@ -0,0 +1,30 @@
+package view
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hidevopsio/iris/context"
+)
+
+// walkTemplates calls the "load" with the name, relative to the "directory",
+// and the contents of each "extension" file of the "directory" of the "fs".
+func walkTemplates(fs http.FileSystem, directory, extension string, load func(name string, contents []byte) error) error {
+	root := path.Clean("/" + filepath.ToSlash(directory))
+
+	return context.WalkFS(fs, root, func(p string, info os.FileInfo) error {
+		if info.IsDir() || path.Ext(p) != extension {
+			return nil
+		}
+
+		contents, err := context.ReadFile(fs, p)
+		if err != nil {
+			return err
+		}
+
+		return load(strings.TrimPrefix(strings.TrimPrefix(p, root), "/"), contents)
+	})
+}
@@ -0,0 +1,57 @@
+package view_test
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/context"
+	"github.com/hidevopsio/iris/httptest"
+	"github.com/hidevopsio/iris/view"
+)
+
+func TestViewFS(t *testing.T) {
+	embedded := http.FS(fstest.MapFS{
+		"templates/index.html":      {Data: []byte(`<h1>{{.Name}}</h1>`)},
+		"templates/users/list.html": {Data: []byte(`<ul>{{.Name}}</ul>`)},
+		"templates/base.django":     {Data: []byte(`<b>{% block content %}{% endblock %}</b>`)},
+		"templates/page.django":     {Data: []byte(`{% extends "base.django" %}{% block content %}{{ Name }}{% endblock %}`)},
+		"templates/base.amber":      {Data: []byte("div\n\tblock content\n")},
+		"templates/page.amber":      {Data: []byte("extends base\nblock content\n\tp #{Name}\n")},
+	})
+
+	dir := writeTemplates(t, map[string]string{
+		"index.html": `<h2>{{.Name}}</h2>`,
+	})
+	defer os.RemoveAll(dir)
+
+	// the local templates shadow the embedded ones.
+	overlay := context.OverlayFS(http.Dir(dir), context.SubFS(embedded, "templates"))
+
+	app := iris.New()
+	app.RegisterView(view.HTML("./templates", ".html").FS(embedded))
+	app.RegisterView(view.Django("./templates", ".django").FS(embedded))
+	app.RegisterView(view.Amber("./templates", ".amber").FS(embedded))
+
+	app.Get("/{file:path}", func(ctx iris.Context) {
+		ctx.View(ctx.Params().Get("file"), iris.Map{"Name": "iris"})
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/index.html").Expect().Status(httptest.StatusOK).Body().Equal("<h1>iris</h1>")
+	e.GET("/users/list.html").Expect().Status(httptest.StatusOK).Body().Equal("<ul>iris</ul>")
+	e.GET("/page.django").Expect().Status(httptest.StatusOK).Body().Equal("<b>iris</b>")
+	e.GET("/page.amber").Expect().Status(httptest.StatusOK).Body().Equal("<div>\n\t<p>iris</p>\n</div>\n")
+
+	overlayApp := iris.New()
+	overlayApp.RegisterView(view.HTML(".", ".html").FS(overlay))
+	overlayApp.Get("/{file:path}", func(ctx iris.Context) {
+		ctx.View(ctx.Params().Get("file"), iris.Map{"Name": "iris"})
+	})
+
+	e = httptest.New(t, overlayApp)
+	e.GET("/index.html").Expect().Status(httptest.StatusOK).Body().Equal("<h2>iris</h2>")
+	e.GET("/users/list.html").Expect().Status(httptest.StatusOK).Body().Equal("<ul>iris</ul>")
+}
@@ -3,12 +3,17 @@ package view
 // EngineFuncer is an addition of a view engine,
 // if a view engine implements that interface
 // then iris can add some closed-relative iris functions
-// like {{ urlpath }} and {{ urlpath }}.
+// like {{ url }} and {{ urlpath }}.
 type EngineFuncer interface {
 	// AddFunc should adds a function to the template's function map.
 	AddFunc(funcName string, funcBody interface{})
 }
 
+// URLFunc is the "url" template function, it receives pairs of names and values,
+// i.e {{ url "user.show" "id" 42 }}, see `router.RoutePathReverser#Href`.
+// The engines which do not support variadic functions adapt it, see `HandlebarsEngine#AddFunc`.
+type URLFunc func(routeName string, pairs ...interface{}) string
+
 // these will be added to all template engines used
 // and completes the EngineFuncer interface.
 //
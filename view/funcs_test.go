@@ -0,0 +1,45 @@
+package view_test
+
+import (
+	"html"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/httptest"
+	"github.com/hidevopsio/iris/view"
+)
+
+func TestViewURLFunc(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"url.html":   `<a href="{{url "user.show" "id" 42 "tab" "posts"}}">{{url "missing"}}</a>`,
+		"url.django": `<a href="{{ url("user.show", "id", 42, "tab", "posts") }}">{{ url("missing") }}</a>`,
+		"url.hbs":    `<a href="{{url "user.show" id=42 tab="posts"}}">{{url "missing"}}</a>`,
+		"url.amber":  "a[href=url(\"user.show\", \"id\", 42, \"tab\", \"posts\")] #{url(\"missing\")}\n",
+		"url.pug":    "a(href=(url \"user.show\" \"id\" 42 \"tab\" \"posts\"))\n",
+	})
+	defer os.RemoveAll(dir)
+
+	app := iris.New()
+	app.RegisterView(view.HTML(dir, ".html"))
+	app.RegisterView(view.Django(dir, ".django"))
+	app.RegisterView(view.Handlebars(dir, ".hbs"))
+	app.RegisterView(view.Amber(dir, ".amber"))
+	app.RegisterView(view.Pug(dir, ".pug"))
+
+	app.Get("/users/{id:int}", func(ctx iris.Context) {}).Name = "user.show"
+	app.Get("/url/{ext}", func(ctx iris.Context) {
+		ctx.View("url." + ctx.Params().Get("ext"))
+	})
+
+	e := httptest.New(t, app)
+
+	for _, ext := range []string{"html", "django", "hbs", "amber", "pug"} {
+		body := e.GET("/url/" + ext).Expect().Status(httptest.StatusOK).Body().Raw()
+		body = html.UnescapeString(strings.Join(strings.Fields(body), ""))
+		if expected := `<ahref="/users/42?tab=posts"></a>`; body != expected {
+			t.Fatalf("[%s] expected the url '%s' but got: '%s'", ext, expected, body)
+		}
+	}
+}
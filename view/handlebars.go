@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
-	"strings"
+	"sort"
 	"sync"
+	"sync/atomic"
 
 	"github.com/aymerick/raymond"
+	"github.com/hidevopsio/iris/context"
 )
 
 // HandlebarsEngine contains the handlebars view engine structure.
@@ -17,32 +20,44 @@ type HandlebarsEngine struct {
 	// files configuration
 	directory string
 	extension string
-	assetFn   func(name string) ([]byte, error) // for embedded, in combination with directory & extension
-	namesFn   func() []string                   // for embedded, in combination with directory & extension
-	reload    bool                              // if true, each time the ExecuteWriter is called the templates will be reloaded.
+	fs        http.FileSystem // for embedded or virtual files, in combination with directory & extension
+	reload    bool            // if true, the templates directory is watched and the changed templates are reloaded.
 	// parser configuration
-	layout        string
-	rmu           sync.RWMutex // locks for helpers and `ExecuteWiter` when `reload` is true.
-	helpers       map[string]interface{}
-	templateCache map[string]*raymond.Template
+	layout  string
+	rmu     sync.RWMutex // locks for helpers.
+	helpers map[string]interface{}
+	// the loaded templates, which are rendered, they are replaced on reload.
+	loaded  atomic.Value // map[string]*raymond.Template
+	watcher watcher
 }
 
+var (
+	_ Engine           = &HandlebarsEngine{}
+	_ EngineFragmenter = &HandlebarsEngine{}
+)
+
+var registerBlockHelper sync.Once
+
 // Handlebars creates and returns a new handlebars view engine.
 func Handlebars(directory, extension string) *HandlebarsEngine {
 	s := &HandlebarsEngine{
-		directory:     directory,
-		extension:     extension,
-		templateCache: make(map[string]*raymond.Template, 0),
-		helpers:       make(map[string]interface{}, 0),
+		directory: directory,
+		extension: extension,
+		helpers:   make(map[string]interface{}, 0),
 	}
 
-	// register the render helper here
-	raymond.RegisterHelper("render", func(partial string, binding interface{}) raymond.SafeString {
+	// the render helper is registered to each template of this engine, see `parse`.
+	s.helpers["render"] = func(partial string, binding interface{}) raymond.SafeString {
 		contents, err := s.executeTemplateBuf(partial, binding)
 		if err != nil {
 			return raymond.SafeString("template with name: " + partial + " couldn't not be found.")
 		}
 		return raymond.SafeString(contents)
+	}
+
+	// register the block helper once, it's global.
+	registerBlockHelper.Do(func() {
+		raymond.RegisterHelper("block", handlebarsBlockHelper)
 	})
 
 	return s
@@ -57,23 +72,44 @@ func (s *HandlebarsEngine) Ext() string {
 // inside the app executable (.go generated files).
 //
 // The assetFn and namesFn can come from the go-bindata library.
+// It's a shortcut of the `FS(context.BindataFS(assetFn, namesFn))`.
 func (s *HandlebarsEngine) Binary(assetFn func(name string) ([]byte, error), namesFn func() []string) *HandlebarsEngine {
-	s.assetFn, s.namesFn = assetFn, namesFn
+	return s.FS(context.BindataFS(assetFn, namesFn))
+}
+
+// FS optionally, use it when template files are loaded from a virtual file system
+// instead of the system's directory, the templates are the "directory" of the "fs"
+// i.e an `http.FS(embedFS)`, a `context.BindataFS` or a `context.OverlayFS`
+// which can shadow embedded templates with local ones.
+func (s *HandlebarsEngine) FS(fs http.FileSystem) *HandlebarsEngine {
+	s.fs = fs
 	return s
 }
 
-// Reload if setted to true the templates are reloading on each render,
+// Reload if setted to true the templates directory is watched for changes
+// and the changed template files are parsed again,
 // use it when you're in development and you're boring of restarting
 // the whole app when you edit a template file.
 //
-// Note that if `true` is passed then only one `View -> ExecuteWriter` will be render each time,
-// no concurrent access across clients, use it only on development status.
+// The renders are not blocked while the templates are reloaded
+// and if a changed template can't be parsed then the `ReloadErrorHandler` is called
+// and the previously loaded templates are still rendered.
+// See `WatchInterval` and `Close` too.
+//
+// It has no effect on embedded templates, see `Binary` and `FS`.
 // It's good to be used side by side with the https://github.com/kataras/rizla reloader for go source files.
 func (s *HandlebarsEngine) Reload(developmentMode bool) *HandlebarsEngine {
 	s.reload = developmentMode
 	return s
 }
 
+// Close stops watching the templates directory, see `Reload`.
+// It's called by the `iris#Application.Shutdown`.
+func (s *HandlebarsEngine) Close() error {
+	s.watcher.close()
+	return nil
+}
+
 // Layout sets the layout template file which should use
 // the {{ yield }} func to yield the main template file
 // and optionally {{partial/partial_r/render}} to render
@@ -85,10 +121,15 @@ func (s *HandlebarsEngine) Layout(layoutFile string) *HandlebarsEngine {
 
 // AddFunc adds the function to the template's function map.
 // It is legal to overwrite elements of the default actions:
-// - url func(routeName string, args ...string) string
+// - url, its pairs are given as hash arguments, i.e {{url "user.show" id=42 tab="posts"}}
 // - urlpath func(routeName string, args ...string) string
 // - render func(fullPartialName string) (raymond.HTML, error).
 func (s *HandlebarsEngine) AddFunc(funcName string, funcBody interface{}) {
+	// raymond does not support variadic helpers.
+	if fn, ok := funcBody.(URLFunc); ok {
+		funcBody = handlebarsURLHelper(fn)
+	}
+
 	s.rmu.Lock()
 	s.helpers[funcName] = funcBody
 	s.rmu.Unlock()
@@ -99,7 +140,7 @@ func (s *HandlebarsEngine) AddFunc(funcName string, funcBody interface{}) {
 //
 // Returns an error if something bad happens, user is responsible to catch it.
 func (s *HandlebarsEngine) Load() error {
-	if s.assetFn != nil && s.namesFn != nil {
+	if s.fs != nil {
 		// embedded
 		return s.loadAssets()
 	}
@@ -117,17 +158,13 @@ func (s *HandlebarsEngine) Load() error {
 // loadDirectory builds the handlebars templates from directory.
 func (s *HandlebarsEngine) loadDirectory() error {
 
-	// register the global helpers on the first load
-	if len(s.templateCache) == 0 && s.helpers != nil {
-		raymond.RegisterHelpers(s.helpers)
-	}
-
 	dir, extension := s.directory, s.extension
 
 	// the render works like {{ render "myfile.html" theContext.PartialContext}}
 	// instead of the html/template engine which works like {{ render "myfile.html"}} and accepts the parent binding, with handlebars we can't do that because of lack of runtime helpers (dublicate error)
 
 	var templateErr error
+	templates := make(map[string]*raymond.Template)
 	filepath.Walk(dir, func(path string, info os.FileInfo, _ error) error {
 		if info == nil || info.IsDir() {
 			return nil
@@ -150,75 +187,122 @@ func (s *HandlebarsEngine) loadDirectory() error {
 
 			name := filepath.ToSlash(rel)
 
-			tmpl, err := raymond.Parse(contents)
+			tmpl, err := s.parse(contents)
 			if err != nil {
 				templateErr = err
 				return err
 			}
-			s.templateCache[name] = tmpl
+			templates[name] = tmpl
 		}
 		return nil
 	})
 
-	return templateErr
+	if templateErr != nil {
+		return templateErr
+	}
+
+	s.loaded.Store(templates)
+	if s.reload {
+		s.watcher.watch(dir, extension, s.reloadFiles)
+	}
+
+	return nil
 }
 
-// loadAssets loads the templates by binary, embedded.
-func (s *HandlebarsEngine) loadAssets() error {
-	// register the global helpers
-	if len(s.templateCache) == 0 && s.helpers != nil {
-		raymond.RegisterHelpers(s.helpers)
+// reloadFiles parses the "changed" template files and removes the "removed" ones,
+// the handlebars templates are rendered by name, so their dependents are not parsed again.
+func (s *HandlebarsEngine) reloadFiles(changed, removed []string) {
+	prev := s.templates()
+	templates := make(map[string]*raymond.Template, len(prev))
+	for name, tmpl := range prev {
+		templates[name] = tmpl
 	}
 
-	virtualDirectory, virtualExtension := s.directory, s.extension
-	assetFn, namesFn := s.assetFn, s.namesFn
+	for _, name := range removed {
+		delete(templates, name)
+	}
 
-	if len(virtualDirectory) > 0 {
-		if virtualDirectory[0] == '.' { // first check for .wrong
-			virtualDirectory = virtualDirectory[1:]
+	for _, name := range changed {
+		buf, err := ioutil.ReadFile(filepath.Join(s.directory, filepath.FromSlash(name)))
+		if err != nil {
+			ReloadErrorHandler(err)
+			return
 		}
-		if virtualDirectory[0] == '/' || virtualDirectory[0] == os.PathSeparator { // second check for /something, (or ./something if we had dot on 0 it will be removed
-			virtualDirectory = virtualDirectory[1:]
+
+		tmpl, err := s.parse(string(buf))
+		if err != nil {
+			ReloadErrorHandler(fmt.Errorf("%s: %v", name, err))
+			return
 		}
+
+		templates[name] = tmpl
 	}
-	var templateErr error
 
-	names := namesFn()
-	for _, path := range names {
-		if !strings.HasPrefix(path, virtualDirectory) {
-			continue
+	s.loaded.Store(templates)
+}
+
+// parse parses the "contents" and registers the helpers of the engine to the template,
+// they are not registered globally so more than one handlebars engines can be used.
+func (s *HandlebarsEngine) parse(contents string) (*raymond.Template, error) {
+	tmpl, err := raymond.Parse(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	s.rmu.RLock()
+	tmpl.RegisterHelpers(s.helpers)
+	s.rmu.RUnlock()
+	return tmpl, nil
+}
+
+// handlebarsURLHelper adapts the "url" func to a handlebars helper,
+// the hash arguments are the pairs of names and values, sorted by name.
+func handlebarsURLHelper(fn URLFunc) func(routeName string, options *raymond.Options) string {
+	return func(routeName string, options *raymond.Options) string {
+		hash := options.Hash()
+		names := make([]string, 0, len(hash))
+		for name := range hash {
+			names = append(names, name)
 		}
-		ext := filepath.Ext(path)
-		if ext == virtualExtension {
+		sort.Strings(names)
 
-			rel, err := filepath.Rel(virtualDirectory, path)
-			if err != nil {
-				templateErr = err
-				return err
-			}
+		pairs := make([]interface{}, 0, len(hash)*2)
+		for _, name := range names {
+			pairs = append(pairs, name, hash[name])
+		}
 
-			buf, err := assetFn(path)
-			if err != nil {
-				templateErr = err
-				return err
-			}
-			contents := string(buf)
-			name := filepath.ToSlash(rel)
+		return fn(routeName, pairs...)
+	}
+}
 
-			tmpl, err := raymond.Parse(contents)
-			if err != nil {
-				templateErr = err
-				return err
-			}
-			s.templateCache[name] = tmpl
+// templates returns the templates which are rendered.
+func (s *HandlebarsEngine) templates() map[string]*raymond.Template {
+	templates, _ := s.loaded.Load().(map[string]*raymond.Template)
+	return templates
+}
 
+// loadAssets loads the templates of the virtual file system (i.e go-bindata for embedded).
+func (s *HandlebarsEngine) loadAssets() error {
+	templates := make(map[string]*raymond.Template)
+	err := walkTemplates(s.fs, s.directory, s.extension, func(name string, buf []byte) error {
+		tmpl, err := s.parse(string(buf))
+		if err != nil {
+			return err
 		}
+
+		templates[name] = tmpl
+		return nil
+	})
+	if err != nil {
+		return err
 	}
-	return templateErr
+
+	s.loaded.Store(templates)
+	return nil
 }
 
 func (s *HandlebarsEngine) fromCache(relativeName string) *raymond.Template {
-	tmpl, ok := s.templateCache[relativeName]
+	tmpl, ok := s.templates()[relativeName]
 	if !ok {
 		return nil
 	}
@@ -234,17 +318,6 @@ func (s *HandlebarsEngine) executeTemplateBuf(name string, binding interface{})
 
 // ExecuteWriter executes a template and writes its result to the w writer.
 func (s *HandlebarsEngine) ExecuteWriter(w io.Writer, filename string, layout string, bindingData interface{}) error {
-	// re-parse the templates if reload is enabled.
-	if s.reload {
-		// locks to fix #872, it's the simplest solution and the most correct,
-		// to execute writers with "wait list", one at a time.
-		s.rmu.Lock()
-		defer s.rmu.Unlock()
-		if err := s.Load(); err != nil {
-			return err
-		}
-	}
-
 	isLayout := false
 	layout = getLayout(layout, s.layout)
 	renderFilename := filename
@@ -287,3 +360,50 @@ func (s *HandlebarsEngine) ExecuteWriter(w io.Writer, filename string, layout st
 
 	return fmt.Errorf("template with name %s[original name = %s] doesn't exists in the dir", renderFilename, filename)
 }
+
+// the private data key of the fragment which is rendered by the `ExecuteFragment`.
+const handlebarsFragmentKey = "iris.fragment"
+
+type handlebarsFragment struct {
+	name     string
+	contents string
+	found    bool
+}
+
+// handlebarsBlockHelper renders the contents of a named block, i.e
+// {{#block "users"}}...{{/block}}, and keeps them if it's the block
+// which is rendered by the `ExecuteFragment`.
+func handlebarsBlockHelper(name string, options *raymond.Options) raymond.SafeString {
+	contents := options.Fn()
+	if f, ok := options.DataFrame().Get(handlebarsFragmentKey).(*handlebarsFragment); ok && !f.found && f.name == name {
+		f.contents, f.found = contents, true
+	}
+
+	return raymond.SafeString(contents)
+}
+
+// ExecuteFragment executes the {{#block "block"}}...{{/block}} of the "filename" template
+// and writes its result to the w writer.
+//
+// Note that the whole template is executed in order to render the block.
+func (s *HandlebarsEngine) ExecuteFragment(w io.Writer, filename string, block string, bindingData interface{}) error {
+	tmpl := s.fromCache(filename)
+	if tmpl == nil {
+		return fmt.Errorf("template with name %s doesn't exists in the dir", filename)
+	}
+
+	fragment := &handlebarsFragment{name: block}
+	data := raymond.NewDataFrame()
+	data.Set(handlebarsFragmentKey, fragment)
+
+	if _, err := tmpl.ExecWith(bindingData, data); err != nil {
+		return err
+	}
+
+	if !fragment.found {
+		return fmt.Errorf("block %s of the template %s doesn't exist", block, filename)
+	}
+
+	_, err := io.WriteString(w, fragment.contents)
+	return err
+}
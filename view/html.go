@@ -6,11 +6,16 @@ import (
 	"html/template"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/hidevopsio/iris/context"
 )
 
 type (
@@ -19,9 +24,9 @@ type (
 		// files configuration
 		directory string
 		extension string
-		assetFn   func(name string) ([]byte, error) // for embedded, in combination with directory & extension
-		namesFn   func() []string                   // for embedded, in combination with directory & extension
-		reload    bool                              // if true, each time the ExecuteWriter is called the templates will be reloaded, each ExecuteWriter waits to be finished before writing to a new one.
+		fs        http.FileSystem // for embedded or virtual files, in combination with directory & extension
+		reload    bool            // if true, the templates directory is watched and the changed templates are reloaded.
+		stream    bool            // if true, the ExecuteWriter writes and flushes the output to the response while the template is executed.
 		// parser configuration
 		options     []string // text options
 		left        string
@@ -34,11 +39,54 @@ type (
 		//
 		middleware func(name string, contents []byte) (string, error)
 		Templates  *template.Template
-		//
+		// the loaded templates, which are rendered, they are replaced on reload.
+		current atomic.Value // *htmlTemplates
+		// the sources of the templates directory, used to rebuild the templates on reload.
+		sources map[string]string
+		watcher watcher
 	}
 )
 
-var _ Engine = &HTMLEngine{}
+var (
+	_ Engine           = &HTMLEngine{}
+	_ EngineFragmenter = &HTMLEngine{}
+)
+
+// htmlTemplates are the loaded html templates.
+// The master templates are never executed, each render executes a clone of them
+// with its own layout and render functions, which are bound to its data,
+// the clones are reused by the next renders.
+type htmlTemplates struct {
+	master *template.Template
+	// the clones of the renders without and with a layout,
+	// the layout functions of the second ones are not left to the first ones.
+	clones       sync.Pool
+	layoutClones sync.Pool
+}
+
+// acquire returns a clone of the master templates which is executed by a single render at a time.
+func (t *htmlTemplates) acquire(layout bool) (*template.Template, error) {
+	pool := &t.clones
+	if layout {
+		pool = &t.layoutClones
+	}
+
+	if tmpl, ok := pool.Get().(*template.Template); ok {
+		return tmpl, nil
+	}
+
+	return t.master.Clone()
+}
+
+// release gives back the clone of an `acquire` when its render is done.
+func (t *htmlTemplates) release(tmpl *template.Template, layout bool) {
+	if layout {
+		t.layoutClones.Put(tmpl)
+		return
+	}
+
+	t.clones.Put(tmpl)
+}
 
 var emptyFuncs = template.FuncMap{
 	"yield": func() (string, error) {
@@ -67,8 +115,6 @@ func HTML(directory, extension string) *HTMLEngine {
 	s := &HTMLEngine{
 		directory:   directory,
 		extension:   extension,
-		assetFn:     nil,
-		namesFn:     nil,
 		reload:      false,
 		left:        "{{",
 		right:       "}}",
@@ -89,23 +135,55 @@ func (s *HTMLEngine) Ext() string {
 // inside the app executable (.go generated files).
 //
 // The assetFn and namesFn can come from the go-bindata library.
+// It's a shortcut of the `FS(context.BindataFS(assetFn, namesFn))`.
 func (s *HTMLEngine) Binary(assetFn func(name string) ([]byte, error), namesFn func() []string) *HTMLEngine {
-	s.assetFn, s.namesFn = assetFn, namesFn
+	return s.FS(context.BindataFS(assetFn, namesFn))
+}
+
+// FS optionally, use it when template files are loaded from a virtual file system
+// instead of the system's directory, the templates are the "directory" of the "fs"
+// i.e an `http.FS(embedFS)`, a `context.BindataFS` or a `context.OverlayFS`
+// which can shadow embedded templates with local ones.
+func (s *HTMLEngine) FS(fs http.FileSystem) *HTMLEngine {
+	s.fs = fs
 	return s
 }
 
-// Reload if setted to true the templates are reloading on each render,
+// Reload if setted to true the templates directory is watched for changes
+// and the templates are rebuilt when a template file is changed, added or removed,
 // use it when you're in development and you're boring of restarting
 // the whole app when you edit a template file.
 //
-// Note that if `true` is passed then only one `View -> ExecuteWriter` will be render each time,
-// no concurrent access across clients, use it only on development status.
+// The renders are not blocked while the templates are reloaded
+// and if a changed template can't be parsed then the `ReloadErrorHandler` is called
+// and the previously loaded templates are still rendered.
+// See `WatchInterval` and `Close` too.
+//
+// It has no effect on embedded templates, see `Binary` and `FS`.
 // It's good to be used side by side with the https://github.com/kataras/rizla reloader for go source files.
 func (s *HTMLEngine) Reload(developmentMode bool) *HTMLEngine {
 	s.reload = developmentMode
 	return s
 }
 
+// Close stops watching the templates directory, see `Reload`.
+// It's called by the `iris#Application.Shutdown`.
+func (s *HTMLEngine) Close() error {
+	s.watcher.close()
+	return nil
+}
+
+// Stream if setted to true the templates are written directly to the response
+// while they are executed and they are flushed to the client in chunks of `StreamFlushSize` bytes,
+// instead of being sent when the whole template is executed.
+//
+// Note that the main template of a layout is still buffered by the {{ yield }},
+// and the status code can't be changed on a template's execution error.
+func (s *HTMLEngine) Stream(enable bool) *HTMLEngine {
+	s.stream = enable
+	return s
+}
+
 // Option sets options for the template. Options are described by
 // strings, either a simple string or "key=value". There can be at
 // most one equals sign in an option string. If the option string
@@ -174,7 +252,7 @@ func (s *HTMLEngine) AddLayoutFunc(funcName string, funcBody interface{}) *HTMLE
 
 // AddFunc adds the function to the template's function map.
 // It is legal to overwrite elements of the default actions:
-// - url func(routeName string, args ...string) string
+// - url func(routeName string, pairs ...interface{}) string
 // - urlpath func(routeName string, args ...string) string
 // - render func(fullPartialName string) (template.HTML, error).
 func (s *HTMLEngine) AddFunc(funcName string, funcBody interface{}) {
@@ -197,7 +275,7 @@ func (s *HTMLEngine) Load() error {
 	// 	atomic.StoreUint32(&s.isLoading, 0)
 	// }()
 
-	if s.assetFn != nil && s.namesFn != nil {
+	if s.fs != nil {
 		// NOT NECESSARY "fix" of https://github.com/hidevopsio/iris/issues/784,
 		// IT'S BAD CODE WRITTEN WE KEEP HERE ONLY FOR A REMINDER
 		// for any future questions.
@@ -240,8 +318,7 @@ func (s *HTMLEngine) loadDirectory() error {
 	dir, extension := s.directory, s.extension
 
 	var templateErr error
-	s.Templates = template.New(dir)
-	s.Templates.Delims(s.left, s.right)
+	sources := make(map[string]string)
 
 	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if info == nil || info.IsDir() {
@@ -261,142 +338,187 @@ func (s *HTMLEngine) loadDirectory() error {
 					return err
 				}
 
-				contents := string(buf)
-
-				name := filepath.ToSlash(rel)
-				tmpl := s.Templates.New(name)
-				tmpl.Option(s.options...)
-				if s.middleware != nil {
-					contents, err = s.middleware(name, buf)
-				}
-				if err != nil {
-					templateErr = err
-					return err
-				}
-				//s.mu.Lock()
-				// Add our funcmaps.
-				_, err = tmpl.Funcs(emptyFuncs).Funcs(s.funcs).Parse(contents)
-				//s.mu.Unlock()
-				if err != nil {
-					templateErr = err
-					return err
-				}
+				sources[filepath.ToSlash(rel)] = string(buf)
 			}
 
 		}
 		return nil
 	})
 
-	return templateErr
+	if templateErr != nil {
+		return templateErr
+	}
+
+	tmpl, err := s.parse(sources)
+	if err != nil {
+		return err
+	}
+
+	s.sources = sources
+	s.setTemplates(tmpl)
+
+	if s.reload {
+		s.watcher.watch(dir, extension, s.reloadFiles)
+	}
+
+	return nil
 }
 
-// loadAssets loads the templates by binary (go-bindata for embedded).
-func (s *HTMLEngine) loadAssets() error {
-	virtualDirectory, virtualExtension := s.directory, s.extension
-	assetFn, namesFn := s.assetFn, s.namesFn
+// parse builds the templates of the "sources", by their names order.
+func (s *HTMLEngine) parse(sources map[string]string) (*template.Template, error) {
+	templates := template.New(s.directory)
+	templates.Delims(s.left, s.right)
 
-	var templateErr error
-	s.Templates = template.New(virtualDirectory)
-	s.Templates.Delims(s.left, s.right)
-	names := namesFn()
-	if len(virtualDirectory) > 0 {
-		if virtualDirectory[0] == '.' { // first check for .wrong
-			virtualDirectory = virtualDirectory[1:]
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := s.parseFile(templates, name, sources[name]); err != nil {
+			return nil, err
 		}
-		if virtualDirectory[0] == '/' || virtualDirectory[0] == os.PathSeparator { // second check for /something, (or ./something if we had dot on 0 it will be removed
-			virtualDirectory = virtualDirectory[1:]
+	}
+
+	return templates, nil
+}
+
+// parseFile parses the "contents" of the "name" template file to the "templates",
+// a template of the same name is replaced.
+func (s *HTMLEngine) parseFile(templates *template.Template, name, contents string) error {
+	if s.middleware != nil {
+		var err error
+		if contents, err = s.middleware(name, []byte(contents)); err != nil {
+			return err
 		}
 	}
 
-	for _, path := range names {
-		// if filepath.IsAbs(virtualDirectory) {
-		// 	// fixes https://github.com/hidevopsio/iris/issues/784
-		// 	// we take the absolute fullpath of the template file.
-		// 	pathFileAbs, err := filepath.Abs(path)
-		// 	if err != nil {
-		// 		templateErr = err
-		// 		continue
-		// 	}
-		//
-		// 	path = pathFileAbs
-		// }
+	tmpl := templates.New(name)
+	tmpl.Option(s.options...)
+	// Add our funcmaps.
+	_, err := tmpl.Funcs(emptyFuncs).Funcs(s.funcs).Parse(contents)
+	return err
+}
+
+// reloadFiles reloads the "changed" template files to a clone of the loaded templates,
+// the templates which depend on them, i.e by a {{ template "name" }} action,
+// find their new definitions by name when they are executed, they are not parsed again.
+// The html templates can't be removed from their set,
+// so the whole templates are rebuilt when a template file is removed.
+func (s *HTMLEngine) reloadFiles(changed, removed []string) {
+	sources := make(map[string]string, len(s.sources))
+	for name, contents := range s.sources {
+		sources[name] = contents
+	}
+
+	for _, name := range removed {
+		delete(sources, name)
+	}
 
-		// bindata may contain more files than the templates
-		// so keep that check as it's.
-		if !strings.HasPrefix(path, virtualDirectory) {
-			continue
+	for _, name := range changed {
+		buf, err := ioutil.ReadFile(filepath.Join(s.directory, filepath.FromSlash(name)))
+		if err != nil {
+			ReloadErrorHandler(err)
+			return
 		}
 
-		ext := filepath.Ext(path)
-		// check if extension matches
-		if ext == virtualExtension {
+		sources[name] = string(buf)
+	}
 
-			// take the relative path of the path as base of
-			// virtualDirectory (the absolute path of the view engine that dev passed).
-			rel, err := filepath.Rel(virtualDirectory, path)
-			if err != nil {
-				templateErr = err
-				continue
+	var (
+		tmpl *template.Template
+		err  error
+	)
+
+	if len(removed) > 0 {
+		tmpl, err = s.parse(sources)
+	} else if tmpl, err = s.templates().master.Clone(); err == nil {
+		for _, name := range changed {
+			if err = s.parseFile(tmpl, name, sources[name]); err != nil {
+				err = fmt.Errorf("%s: %v", name, err)
+				break
 			}
+		}
+	}
 
-			// // take the current working directory
-			// cpath, err := filepath.Abs(".")
-			// if err == nil {
-			// 	// set the path as relative to "path" of the current working dir.
-			// 	// fixes https://github.com/hidevopsio/iris/issues/784
-			// 	rpath, err := filepath.Rel(cpath, path)
-			// 	// fix view: Asset  not found for path ''
-			// 	if err == nil && rpath != "" {
-			// 		path = rpath
-			// 	}
-			// }
-
-			buf, err := assetFn(path)
-			if err != nil {
-				templateErr = fmt.Errorf("%v for path '%s'", err, path)
-				continue
-			}
+	if err != nil {
+		ReloadErrorHandler(err)
+		return
+	}
 
-			contents := string(buf)
-			name := filepath.ToSlash(rel)
+	s.sources = sources
+	s.setTemplates(tmpl)
+}
 
-			// name should be the filename of the template.
-			tmpl := s.Templates.New(name)
-			tmpl.Option(s.options...)
+// setTemplates sets the templates which are rendered,
+// the "tmpl" should not be executed, the `Templates` field is a clone of it.
+func (s *HTMLEngine) setTemplates(tmpl *template.Template) {
+	if clone, err := tmpl.Clone(); err == nil {
+		s.Templates = clone
+	}
 
-			if s.middleware != nil {
-				contents, err = s.middleware(name, buf)
-				if err != nil {
-					templateErr = fmt.Errorf("%v for name '%s'", err, name)
-					continue
-				}
-			}
+	s.current.Store(&htmlTemplates{master: tmpl})
+}
+
+// templates returns the templates which are rendered.
+func (s *HTMLEngine) templates() *htmlTemplates {
+	t, _ := s.current.Load().(*htmlTemplates)
+	return t
+}
+
+// loadAssets loads the templates of the virtual file system (i.e go-bindata for embedded).
+func (s *HTMLEngine) loadAssets() error {
+	var templateErr error
+	templates := template.New(s.directory)
+	templates.Delims(s.left, s.right)
+
+	err := walkTemplates(s.fs, s.directory, s.extension, func(name string, buf []byte) error {
+		contents := string(buf)
 
-			// Add our funcmaps.
-			tmpl.Funcs(emptyFuncs).Funcs(s.funcs).Parse(contents)
+		// name should be the filename of the template.
+		tmpl := templates.New(name)
+		tmpl.Option(s.options...)
+
+		if s.middleware != nil {
+			var err error
+			contents, err = s.middleware(name, buf)
+			if err != nil {
+				templateErr = fmt.Errorf("%v for name '%s'", err, name)
+				return nil
+			}
 		}
+
+		// Add our funcmaps.
+		tmpl.Funcs(emptyFuncs).Funcs(s.funcs).Parse(contents)
+		return nil
+	})
+	if err != nil {
+		templateErr = err
 	}
+
+	s.setTemplates(templates)
 	return templateErr
 }
 
-func (s *HTMLEngine) executeTemplateBuf(name string, binding interface{}) (*bytes.Buffer, error) {
+func executeTemplateBuf(templates *template.Template, name string, binding interface{}) (*bytes.Buffer, error) {
 	buf := new(bytes.Buffer)
-	err := s.Templates.ExecuteTemplate(buf, name, binding)
+	err := templates.ExecuteTemplate(buf, name, binding)
 
 	return buf, err
 }
 
-func (s *HTMLEngine) layoutFuncsFor(name string, binding interface{}) {
+func (s *HTMLEngine) layoutFuncsFor(templates *template.Template, name string, binding interface{}) {
 	funcs := template.FuncMap{
 		"yield": func() (template.HTML, error) {
-			buf, err := s.executeTemplateBuf(name, binding)
+			buf, err := executeTemplateBuf(templates, name, binding)
 			// Return safe HTML here since we are rendering our own template.
 			return template.HTML(buf.String()), err
 		},
 		"part": func(partName string) (template.HTML, error) {
 			nameTemp := strings.Replace(name, ".html", "", -1)
 			fullPartName := fmt.Sprintf("%s-%s", nameTemp, partName)
-			buf, err := s.executeTemplateBuf(fullPartName, binding)
+			buf, err := executeTemplateBuf(templates, fullPartName, binding)
 			if err != nil {
 				return "", nil
 			}
@@ -407,8 +529,8 @@ func (s *HTMLEngine) layoutFuncsFor(name string, binding interface{}) {
 		},
 		"partial": func(partialName string) (template.HTML, error) {
 			fullPartialName := fmt.Sprintf("%s-%s", partialName, name)
-			if s.Templates.Lookup(fullPartialName) != nil {
-				buf, err := s.executeTemplateBuf(fullPartialName, binding)
+			if templates.Lookup(fullPartialName) != nil {
+				buf, err := executeTemplateBuf(templates, fullPartialName, binding)
 				return template.HTML(buf.String()), err
 			}
 			return "", nil
@@ -421,14 +543,14 @@ func (s *HTMLEngine) layoutFuncsFor(name string, binding interface{}) {
 			ext := filepath.Ext(name)
 			root := name[:len(name)-len(ext)]
 			fullPartialName := fmt.Sprintf("%s%s%s", root, partialName, ext)
-			if s.Templates.Lookup(fullPartialName) != nil {
-				buf, err := s.executeTemplateBuf(fullPartialName, binding)
+			if templates.Lookup(fullPartialName) != nil {
+				buf, err := executeTemplateBuf(templates, fullPartialName, binding)
 				return template.HTML(buf.String()), err
 			}
 			return "", nil
 		},
 		"render": func(fullPartialName string) (template.HTML, error) {
-			buf, err := s.executeTemplateBuf(fullPartialName, binding)
+			buf, err := executeTemplateBuf(templates, fullPartialName, binding)
 			return template.HTML(buf.String()), err
 		},
 	}
@@ -436,20 +558,20 @@ func (s *HTMLEngine) layoutFuncsFor(name string, binding interface{}) {
 	for k, v := range s.layoutFuncs {
 		funcs[k] = v
 	}
-	if tpl := s.Templates.Lookup(name); tpl != nil {
+	if tpl := templates.Lookup(name); tpl != nil {
 		tpl.Funcs(funcs)
 	}
 }
 
-func (s *HTMLEngine) runtimeFuncsFor(name string, binding interface{}) {
+func (s *HTMLEngine) runtimeFuncsFor(templates *template.Template, name string, binding interface{}) {
 	funcs := template.FuncMap{
 		"render": func(fullPartialName string) (template.HTML, error) {
-			buf, err := s.executeTemplateBuf(fullPartialName, binding)
+			buf, err := executeTemplateBuf(templates, fullPartialName, binding)
 			return template.HTML(buf.String()), err
 		},
 	}
 
-	if tpl := s.Templates.Lookup(name); tpl != nil {
+	if tpl := templates.Lookup(name); tpl != nil {
 		tpl.Funcs(funcs)
 	}
 }
@@ -458,25 +580,54 @@ var zero = time.Time{}
 
 // ExecuteWriter executes a template and writes its result to the w writer.
 func (s *HTMLEngine) ExecuteWriter(w io.Writer, name string, layout string, bindingData interface{}) error {
-	// re-parse the templates if reload is enabled.
-	if s.reload {
-		// locks to fix #872, it's the simplest solution and the most correct,
-		// to execute writers with "wait list", one at a time.
-		s.rmu.Lock()
-		defer s.rmu.Unlock()
-		if err := s.Load(); err != nil {
-			return err
-		}
+	loaded := s.templates()
+	if loaded == nil {
+		return fmt.Errorf("template with name %s doesn't exists in the dir", name)
 	}
 
 	layout = getLayout(layout, s.layout)
+	hasLayout := layout != ""
+
+	templates, err := loaded.acquire(hasLayout)
+	if err != nil {
+		return err
+	}
+	defer loaded.release(templates, hasLayout)
 
-	if layout != "" {
-		s.layoutFuncsFor(name, bindingData)
+	if hasLayout {
+		s.layoutFuncsFor(templates, name, bindingData)
 		name = layout
 	} else {
-		s.runtimeFuncsFor(name, bindingData)
+		s.runtimeFuncsFor(templates, name, bindingData)
+	}
+
+	return stream(w, s.stream, func(w io.Writer) error {
+		return templates.ExecuteTemplate(w, name, bindingData)
+	})
+}
+
+// ExecuteFragment executes the "block" template, which is declared by a {{ define "block" }}
+// or a {{ block "block" . }} action of the "filename" template, and writes its result to the w writer.
+//
+// Note that the html templates share their namespace, the blocks of different files should have unique names.
+func (s *HTMLEngine) ExecuteFragment(w io.Writer, filename string, block string, bindingData interface{}) error {
+	loaded := s.templates()
+	if loaded == nil || loaded.master.Lookup(filename) == nil {
+		return fmt.Errorf("template with name %s doesn't exists in the dir", filename)
 	}
 
-	return s.Templates.ExecuteTemplate(w, name, bindingData)
+	if loaded.master.Lookup(block) == nil {
+		return fmt.Errorf("block %s of the template %s doesn't exist", block, filename)
+	}
+
+	templates, err := loaded.acquire(false)
+	if err != nil {
+		return err
+	}
+	defer loaded.release(templates, false)
+
+	s.runtimeFuncsFor(templates, block, bindingData)
+	return stream(w, s.stream, func(w io.Writer) error {
+		return templates.ExecuteTemplate(w, block, bindingData)
+	})
 }
@@ -0,0 +1,62 @@
+package view_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hidevopsio/iris/view"
+)
+
+func TestHTMLConcurrentRenders(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"layout.html":  `<main>{{ yield }}</main>`,
+		"index.html":   `<p>{{.}}</p>{{ wait }}{{ render "partial.html" }}`,
+		"partial.html": `<b>{{.}}</b>`,
+	})
+	defer os.RemoveAll(dir)
+
+	engine := view.HTML(dir, ".html")
+	// gives the time to the rest of the renders to run.
+	engine.AddFunc("wait", func() string {
+		time.Sleep(time.Millisecond)
+		return ""
+	})
+	if err := engine.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the yield and render functions of each render are bound to its own data.
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			layout, expected := "", fmt.Sprintf("<p>%d</p><b>%d</b>", i, i)
+			if i%2 == 0 {
+				layout, expected = "layout.html", "<main>"+expected+"</main>"
+			}
+
+			var buf bytes.Buffer
+			if err := engine.ExecuteWriter(&buf, "index.html", layout, i); err != nil {
+				errs <- err
+				return
+			}
+
+			if got := buf.String(); got != expected {
+				errs <- fmt.Errorf("expected '%s' but got '%s'", expected, got)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
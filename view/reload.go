@@ -0,0 +1,174 @@
+package view
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hidevopsio/golog"
+)
+
+// WatchInterval is the interval which the view engines with the `Reload` option
+// check their templates directory for changed template files.
+// Defaults to 500 milliseconds.
+var WatchInterval = 500 * time.Millisecond
+
+// ReloadErrorHandler is called when the changed template files of a view engine
+// with the `Reload` option can't be loaded, i.e because of a parse error.
+// The previously loaded templates are kept and rendered until the files are fixed.
+//
+// Defaults to log the error.
+var ReloadErrorHandler = func(err error) {
+	golog.Errorf("view: reload: %v", err)
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// watcher checks the template files of a directory for changes,
+// on each `WatchInterval`, until it's closed.
+type watcher struct {
+	mu   sync.Mutex
+	stop chan struct{} // nil when it's not watching.
+	done chan struct{} // closed when the watching goroutine returns.
+}
+
+// watch starts watching the "extension" files of the "dir", if not already,
+// the "onChange" is called with the names of the added or modified
+// and the names of the removed template files.
+func (w *watcher) watch(dir, extension string, onChange func(changed, removed []string)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stop != nil {
+		return
+	}
+
+	stamps := scanTemplates(dir, extension)
+	stop, done := make(chan struct{}), make(chan struct{})
+	w.stop, w.done = stop, done
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(WatchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				stamps = checkTemplates(dir, extension, stamps, onChange)
+			}
+		}
+	}()
+}
+
+// close stops the watching goroutine, if any,
+// and waits for its running check, so no template is reloaded after it.
+func (w *watcher) close() {
+	w.mu.Lock()
+	if w.stop != nil {
+		close(w.stop)
+		<-w.done
+		w.stop, w.done = nil, nil
+	}
+	w.mu.Unlock()
+}
+
+// checkTemplates calls the "onChange" if the "extension" files of the "dir" are changed
+// since their "prev" stamps, it returns their current stamps.
+func checkTemplates(dir, extension string, prev map[string]fileStamp, onChange func(changed, removed []string)) map[string]fileStamp {
+	stamps := scanTemplates(dir, extension)
+
+	var changed, removed []string
+	for name, stamp := range stamps {
+		if prevStamp, ok := prev[name]; !ok || prevStamp != stamp {
+			changed = append(changed, name)
+		}
+	}
+
+	for name := range prev {
+		if _, ok := stamps[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	if len(changed) == 0 && len(removed) == 0 {
+		return stamps
+	}
+
+	sort.Strings(changed)
+	sort.Strings(removed)
+	onChange(changed, removed)
+	return stamps
+}
+
+// scanTemplates returns the stamps of the "extension" files of the "dir",
+// by their relative and slash-separated names, the template names.
+func scanTemplates(dir, extension string) map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if info == nil || info.IsDir() || filepath.Ext(p) != extension {
+			return nil
+		}
+
+		if rel, err := filepath.Rel(dir, p); err == nil {
+			stamps[filepath.ToSlash(rel)] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+		}
+
+		return nil
+	})
+
+	return stamps
+}
+
+// withDependents returns the "changed" template names and the names of the templates
+// which depend on them, i.e the templates which extend a changed layout,
+// the "deps" returns the template names that a template depends on.
+func withDependents(changed []string, names []string, deps func(name string) []string) []string {
+	result := append([]string(nil), changed...)
+	seen := make(map[string]bool, len(changed))
+	for _, name := range changed {
+		seen[name] = true
+	}
+
+	for i := 0; i < len(result); i++ {
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+
+			for _, dep := range deps(name) {
+				if dep == result[i] {
+					seen[name] = true
+					result = append(result, name)
+					break
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// templateDep returns the template name of the "dep" of the "name" template,
+// if "relative" is true then the "dep" is relative to the template's directory.
+// The "extension" is added if it's missing.
+func templateDep(name, dep, extension string, relative bool) string {
+	if relative {
+		dep = path.Join(path.Dir(name), dep)
+	}
+
+	dep = path.Clean(dep)
+	if path.Ext(dep) == "" {
+		dep += extension
+	}
+
+	return dep
+}
@@ -0,0 +1,119 @@
+package view_test
+
+import (
+	stdContext "context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hidevopsio/iris"
+	"github.com/hidevopsio/iris/httptest"
+	"github.com/hidevopsio/iris/view"
+)
+
+func TestViewReload(t *testing.T) {
+	view.WatchInterval = 10 * time.Millisecond
+
+	var (
+		mu        sync.Mutex
+		reloadErr error
+	)
+	view.ReloadErrorHandler = func(err error) {
+		mu.Lock()
+		reloadErr = err
+		mu.Unlock()
+	}
+
+	dir := writeTemplates(t, map[string]string{
+		"index.html":  `<p>{{.Name}}</p>`,
+		"page.html":   `{{ template "name.html" . }}!`,
+		"name.html":   `<em>{{.Name}}</em>`,
+		"base.django": `<b>{% block content %}{% endblock %}</b>`,
+		"page.django": `{% extends "base.django" %}{% block content %}{{ Name }}{% endblock %}`,
+		"index.amber": "p #{Name}\n",
+	})
+	defer os.RemoveAll(dir)
+
+	app := iris.New()
+	app.RegisterView(view.HTML(dir, ".html").Reload(true))
+	app.RegisterView(view.Django(dir, ".django").Reload(true))
+	app.RegisterView(view.Amber(dir, ".amber").Reload(true))
+
+	app.Get("/{file:path}", func(ctx iris.Context) {
+		ctx.View(ctx.Params().Get("file"), iris.Map{"Name": "iris"})
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/index.html").Expect().Status(httptest.StatusOK).Body().Equal("<p>iris</p>")
+	e.GET("/page.html").Expect().Status(httptest.StatusOK).Body().Equal("<em>iris</em>!")
+	e.GET("/page.django").Expect().Status(httptest.StatusOK).Body().Equal("<b>iris</b>")
+	e.GET("/index.amber").Expect().Status(httptest.StatusOK).Body().Equal("<p>iris</p>\n")
+
+	write := func(name, contents string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), os.FileMode(0644)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expectBody := func(path, expected string) {
+		t.Helper()
+
+		var body string
+		for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			if body = e.GET(path).Expect().Body().Raw(); body == expected {
+				return
+			}
+		}
+
+		t.Fatalf("%s: expected the body to be '%s' but got '%s'", path, expected, body)
+	}
+
+	write("index.html", `<h1>{{.Name}}</h1>`)
+	// the page which includes the changed template renders its new contents.
+	write("name.html", `<strong>{{.Name}}</strong>`)
+	// the page which extends the base is parsed again too.
+	write("base.django", `<i>{% block content %}{% endblock %}</i>`)
+	write("index.amber", "h1 #{Name}\n")
+	write("new.html", `<h2>{{.Name}}</h2>`)
+
+	expectBody("/index.html", "<h1>iris</h1>")
+	expectBody("/page.django", "<i>iris</i>")
+	expectBody("/index.amber", "<h1>iris</h1>\n")
+	expectBody("/new.html", "<h2>iris</h2>")
+	expectBody("/page.html", "<strong>iris</strong>!")
+
+	// the templates are rebuilt without the removed ones.
+	if err := os.Remove(filepath.Join(dir, "new.html")); err != nil {
+		t.Fatal(err)
+	}
+	expectBody("/new.html", "Internal Server Error")
+	e.GET("/page.html").Expect().Status(httptest.StatusOK).Body().Equal("<strong>iris</strong>!")
+
+	// a parse error keeps the previous templates.
+	write("index.html", `<h3>{{.Name</h3>`)
+
+	var err error
+	for deadline := time.Now().Add(2 * time.Second); err == nil && time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		mu.Lock()
+		err = reloadErr
+		mu.Unlock()
+	}
+
+	if err == nil {
+		t.Fatal("expected a reload error")
+	}
+
+	e.GET("/index.html").Expect().Status(httptest.StatusOK).Body().Equal("<h1>iris</h1>")
+
+	// the shutdown stops watching the templates.
+	if err = app.Shutdown(stdContext.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	write("index.html", `<h4>{{.Name}}</h4>`)
+	time.Sleep(10 * view.WatchInterval)
+	e.GET("/index.html").Expect().Status(httptest.StatusOK).Body().Equal("<h1>iris</h1>")
+}
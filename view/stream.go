@@ -0,0 +1,56 @@
+package view
+
+import (
+	"io"
+	"net/http"
+)
+
+// StreamFlushSize is the number of the bytes which are written to the client
+// before each flush of the view engines which render with the `Stream` option.
+// Defaults to 4KB.
+var StreamFlushSize = 4 << 10
+
+// streamWriter writes the template's output directly to the response
+// and it flushes it in chunks of `StreamFlushSize` bytes.
+type streamWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+	pending int
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	n, err := s.w.Write(p)
+	s.pending += n
+	if err == nil && s.pending >= StreamFlushSize {
+		s.Flush()
+	}
+
+	return n, err
+}
+
+// Flush sends the pending bytes to the client.
+func (s *streamWriter) Flush() {
+	if s.pending > 0 {
+		s.pending = 0
+		s.flusher.Flush()
+	}
+}
+
+// stream calls the "execute" with a streaming writer of the "w" if "enabled" is true
+// and the "w" can be flushed, i.e the response writer, otherwise with the "w" itself.
+func stream(w io.Writer, enabled bool, execute func(w io.Writer) error) error {
+	if !enabled {
+		return execute(w)
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return execute(w)
+	}
+
+	sw := &streamWriter{w: w, flusher: flusher}
+	err := execute(sw)
+	// flush the rest of the output too.
+	sw.Flush()
+	return err
+}
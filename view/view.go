@@ -39,15 +39,12 @@ func (v *View) Len() int {
 
 var (
 	errNoViewEngineForExt = errors.New("no view engine found for '%s'")
+	errNoFragmentsForExt  = errors.New("view engine for '%s' does not support fragments")
 )
 
 // ExecuteWriter calls the correct view Engine's ExecuteWriter func
 func (v *View) ExecuteWriter(w io.Writer, filename string, layout string, bindingData interface{}) error {
-	if len(filename) > 2 {
-		if filename[0] == '/' { // omit first slash
-			filename = filename[1:]
-		}
-	}
+	filename = trimFilename(filename)
 
 	e := v.Find(filename)
 	if e == nil {
@@ -57,6 +54,34 @@ func (v *View) ExecuteWriter(w io.Writer, filename string, layout string, bindin
 	return e.ExecuteWriter(w, filename, layout, bindingData)
 }
 
+// ExecuteFragment calls the correct view Engine's ExecuteFragment func,
+// the engine should implement the `EngineFragmenter`.
+func (v *View) ExecuteFragment(w io.Writer, filename string, block string, bindingData interface{}) error {
+	filename = trimFilename(filename)
+
+	e := v.Find(filename)
+	if e == nil {
+		return errNoViewEngineForExt.Format(filepath.Ext(filename))
+	}
+
+	fragmenter, ok := e.(EngineFragmenter)
+	if !ok {
+		return errNoFragmentsForExt.Format(filepath.Ext(filename))
+	}
+
+	return fragmenter.ExecuteFragment(w, filename, block, bindingData)
+}
+
+func trimFilename(filename string) string {
+	if len(filename) > 2 {
+		if filename[0] == '/' { // omit first slash
+			filename = filename[1:]
+		}
+	}
+
+	return filename
+}
+
 // AddFunc adds a function to all registered engines.
 // Each template engine that supports functions has its own AddFunc too.
 func (v *View) AddFunc(funcName string, funcBody interface{}) {
@@ -68,6 +93,21 @@ func (v *View) AddFunc(funcName string, funcBody interface{}) {
 	}
 }
 
+// Close closes the registered engines which implement the io.Closer,
+// i.e it stops watching the templates of the engines with the `Reload` option.
+// Returns the first error, if any.
+func (v *View) Close() error {
+	var err error
+	for i, n := 0, len(v.engines); i < n; i++ {
+		if closer, ok := v.engines[i].(io.Closer); ok {
+			if closeErr := closer.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	}
+	return err
+}
+
 // Load compiles all the registered engines.
 func (v *View) Load() error {
 	for i, n := 0, len(v.engines); i < n; i++ {